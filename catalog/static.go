@@ -0,0 +1,70 @@
+// Package catalog provides implementations of the product catalog, which
+// holds the quantity limits of individual products.
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Product is an entry of a catalog file
+type Product struct {
+	ID          int64  `json:"id"`
+	MaxQuantity uint64 `json:"max_quantity"`
+}
+
+// Static holds the limits of products in memory. It doesn't change once
+// created, so it is safe for concurrent use.
+type Static struct {
+	maxQuantities map[int64]uint64
+}
+
+// NewStatic returns a new Static catalog with the maximum quantities of
+// products by product ID
+func NewStatic(maxQuantities map[int64]uint64) *Static {
+	static := &Static{maxQuantities: make(map[int64]uint64, len(maxQuantities))}
+	for productID, max := range maxQuantities {
+		static.maxQuantities[productID] = max
+	}
+
+	return static
+}
+
+// Load reads a Static catalog from a JSON list of products
+func Load(r io.Reader) (*Static, error) {
+	var products []Product
+	if err := json.NewDecoder(r).Decode(&products); err != nil {
+		return nil, fmt.Errorf("can't decode catalog: %w", err)
+	}
+
+	maxQuantities := make(map[int64]uint64, len(products))
+	for _, product := range products {
+		if _, ok := maxQuantities[product.ID]; ok {
+			return nil, fmt.Errorf("product %d is listed more than once", product.ID)
+		}
+		maxQuantities[product.ID] = product.MaxQuantity
+	}
+
+	return &Static{maxQuantities: maxQuantities}, nil
+}
+
+// LoadFile reads a Static catalog from a JSON file, as described by Load
+func LoadFile(path string) (*Static, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// MaxQuantity returns the maximum quantity of the product in a single cart.
+// It reports false if the product is not in the catalog.
+func (static *Static) MaxQuantity(ctx context.Context, productID int64) (uint64, bool, error) {
+	max, ok := static.maxQuantities[productID]
+	return max, ok, nil
+}
@@ -0,0 +1,69 @@
+package catalog
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    map[int64]uint64
+		wantErr bool
+	}{
+		{
+			name: "products",
+			json: `[{"id": 1, "max_quantity": 5}, {"id": 2, "max_quantity": 1}]`,
+			want: map[int64]uint64{1: 5, 2: 1},
+		},
+		{
+			name: "empty",
+			json: `[]`,
+			want: map[int64]uint64{},
+		},
+		{
+			name:    "duplicate product",
+			json:    `[{"id": 1, "max_quantity": 5}, {"id": 1, "max_quantity": 1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			json:    `{"id": 1}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			static, err := Load(strings.NewReader(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(static.maxQuantities) != len(tt.want) {
+				t.Fatalf("Load() = %v, want %v", static.maxQuantities, tt.want)
+			}
+			for productID, want := range tt.want {
+				if max, ok, _ := static.MaxQuantity(context.Background(), productID); !ok || max != want {
+					t.Fatalf("MaxQuantity(%d) = %d, %t, want %d", productID, max, ok, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStatic_MaxQuantity(t *testing.T) {
+	static := NewStatic(map[int64]uint64{1: 3})
+
+	if max, ok, err := static.MaxQuantity(context.Background(), 1); err != nil || !ok || max != 3 {
+		t.Fatalf("MaxQuantity() = %d, %t, %v, want %d", max, ok, err, 3)
+	}
+
+	if _, ok, err := static.MaxQuantity(context.Background(), 2); err != nil || ok {
+		t.Fatalf("MaxQuantity() reported a limit of a product which is not in the catalog")
+	}
+}
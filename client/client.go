@@ -0,0 +1,237 @@
+// Package client provides a Go client for the shopping cart HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// Default values used when the corresponding Config fields are not set.
+const (
+	DefaultTimeout   = 10 * time.Second
+	DefaultRetryWait = 100 * time.Millisecond
+)
+
+// Config describes how the Client reaches and authenticates against the API.
+type Config struct {
+	// BaseURL is the address of the service, e.g. http://localhost:8080
+	BaseURL string
+
+	// Username and Password are sent as Basic credentials with every request.
+	Username string
+	Password string
+
+	// Timeout limits the duration of a single attempt.
+	Timeout time.Duration
+
+	// MaxRetries is the number of times an idempotent request (GET, DELETE) is
+	// retried after a connection error or a 429, 502, 503 or 504 response.
+	MaxRetries int
+
+	// RetryWait is the delay before the first retry, doubled on every next one.
+	RetryWait time.Duration
+
+	// HTTPClient is used to perform requests, http.DefaultClient if not set.
+	HTTPClient *http.Client
+}
+
+// Client is a client for the shopping cart API.
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	timeout    time.Duration
+	maxRetries int
+	retryWait  time.Duration
+	httpClient *http.Client
+}
+
+// New returns a new Client.
+func New(config Config) *Client {
+	client := &Client{
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		username:   config.Username,
+		password:   config.Password,
+		timeout:    config.Timeout,
+		maxRetries: config.MaxRetries,
+		retryWait:  config.RetryWait,
+		httpClient: config.HTTPClient,
+	}
+
+	if client.timeout == 0 {
+		client.timeout = DefaultTimeout
+	}
+	if client.retryWait == 0 {
+		client.retryWait = DefaultRetryWait
+	}
+	if client.httpClient == nil {
+		client.httpClient = http.DefaultClient
+	}
+
+	return client
+}
+
+// Create creates a new shopping cart for the authenticated user
+func (client *Client) Create(ctx context.Context) (shoppingcart.ShoppingCart, error) {
+	var cart shoppingcart.ShoppingCart
+	err := client.do(ctx, http.MethodPost, "/v1/shoppingcart", nil, &cart)
+
+	return cart, err
+}
+
+// Get retrieves a shopping cart along with shopping cart items
+func (client *Client) Get(ctx context.Context, shoppingCartID int64) (shoppingcart.ShoppingCart, error) {
+	var cart shoppingcart.ShoppingCart
+	err := client.do(ctx, http.MethodGet, fmt.Sprintf("/v1/shoppingcart/%d", shoppingCartID), nil, &cart)
+
+	return cart, err
+}
+
+// Empty removes all items from a shopping cart
+func (client *Client) Empty(ctx context.Context, shoppingCartID int64) error {
+	return client.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/shoppingcart/%d/item", shoppingCartID), nil, nil)
+}
+
+// Checkout checks out a shopping cart and returns its content
+func (client *Client) Checkout(ctx context.Context, shoppingCartID int64) (shoppingcart.ShoppingCart, error) {
+	var cart shoppingcart.ShoppingCart
+	err := client.do(ctx, http.MethodPost, fmt.Sprintf("/v1/shoppingcart/%d/checkout", shoppingCartID), nil, &cart)
+
+	return cart, err
+}
+
+// AddProduct adds a product to the shopping cart referenced by cartItem.ShoppingCartID.
+// On success cartItem is replaced by the stored item.
+func (client *Client) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	req := struct {
+		ProductID  int64                   `json:"product_id"`
+		VariantID  string                  `json:"variant_id,omitempty"`
+		Attributes shoppingcart.Attributes `json:"attributes,omitempty"`
+		Quantity   uint64                  `json:"quantity"`
+	}{
+		ProductID:  cartItem.ProductID,
+		VariantID:  cartItem.VariantID,
+		Attributes: cartItem.Attributes,
+		Quantity:   cartItem.Quantity,
+	}
+
+	path := fmt.Sprintf("/v1/shoppingcart/%d/item", cartItem.ShoppingCartID)
+	return client.do(ctx, http.MethodPost, path, req, cartItem)
+}
+
+// RemoveProduct removes all lines of a product from a shopping cart
+func (client *Client) RemoveProduct(ctx context.Context, shoppingCartID, productID int64) error {
+	path := fmt.Sprintf("/v1/shoppingcart/%d/item/%d", shoppingCartID, productID)
+	return client.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// RemoveItem removes a single line from a shopping cart
+func (client *Client) RemoveItem(ctx context.Context, shoppingCartID, itemID int64) error {
+	path := fmt.Sprintf("/v1/shoppingcart/%d/line/%d", shoppingCartID, itemID)
+	return client.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// SaveForLater moves a line of the cart to the items saved for later and returns the saved line
+func (client *Client) SaveForLater(ctx context.Context, shoppingCartID, itemID int64) (shoppingcart.ShoppingCartItem, error) {
+	var cartItem shoppingcart.ShoppingCartItem
+	path := fmt.Sprintf("/v1/shoppingcart/%d/line/%d/save-for-later", shoppingCartID, itemID)
+	err := client.do(ctx, http.MethodPost, path, nil, &cartItem)
+
+	return cartItem, err
+}
+
+// MoveToCart moves a line saved for later back to the cart and returns the active line
+func (client *Client) MoveToCart(ctx context.Context, shoppingCartID, itemID int64) (shoppingcart.ShoppingCartItem, error) {
+	var cartItem shoppingcart.ShoppingCartItem
+	path := fmt.Sprintf("/v1/shoppingcart/%d/line/%d/move-to-cart", shoppingCartID, itemID)
+	err := client.do(ctx, http.MethodPost, path, nil, &cartItem)
+
+	return cartItem, err
+}
+
+// do performs the request, retrying it if allowed, and decodes the response into out.
+func (client *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	wait := client.retryWait
+	for attempt := 0; ; attempt++ {
+		retry, err := client.attempt(ctx, method, path, body, out)
+		if !retry || attempt >= client.maxRetries || !idempotent(method) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// attempt performs a single request. It reports whether the request may be retried.
+func (client *Client) attempt(ctx context.Context, method, path string, body []byte, out interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, client.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.SetBasicAuth(client.username, client.password)
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() != context.Canceled, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return retryable(resp.StatusCode), decodeError(resp)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return false, nil
+	}
+
+	return false, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// idempotent reports whether a request with this method can be safely repeated.
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodDelete
+}
+
+// retryable reports whether a response with this status code is worth retrying.
+func retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
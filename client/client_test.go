@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/handler"
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/service"
+)
+
+// newServer runs the shopping cart handler backed by the mocked storage.
+func newServer(t *testing.T) *httptest.Server {
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+	})
+
+	server := httptest.NewServer(handler.New(handler.Services{
+		ShoppingCart: services.ShoppingCart,
+		Auth:         auth.New(),
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_Create(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	t.Run("create shopping cart successful", func(t *testing.T) {
+		client := New(Config{BaseURL: server.URL, Username: "test", Password: "test"})
+
+		cart, err := client.Create(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if cart.UserID != 1 {
+			t.Fatalf("Expected user id %d, got %d", 1, cart.UserID)
+		}
+	})
+
+	t.Run("create shopping cart without credentials", func(t *testing.T) {
+		client := New(Config{BaseURL: server.URL})
+
+		if _, err := client.Create(ctx); err != shoppingcart.ErrNoPermission {
+			t.Fatalf("Expected error %v, got %v", shoppingcart.ErrNoPermission, err)
+		}
+	})
+}
+
+func TestClient_Get(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	t.Run("get shopping cart successful", func(t *testing.T) {
+		client := New(Config{BaseURL: server.URL, Username: "test", Password: "test"})
+
+		cart, err := client.Get(ctx, 1)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(cart.Items) != 4 {
+			t.Fatalf("Expected %d items, got %d", 4, len(cart.Items))
+		}
+	})
+
+	t.Run("get shopping cart which doesn't belong to this user", func(t *testing.T) {
+		client := New(Config{BaseURL: server.URL, Username: "hacker", Password: "password"})
+
+		if _, err := client.Get(ctx, 1); err != shoppingcart.ErrCartNotFound {
+			t.Fatalf("Expected error %v, got %v", shoppingcart.ErrCartNotFound, err)
+		}
+	})
+}
+
+func TestClient_AddProduct(t *testing.T) {
+	server := newServer(t)
+	client := New(Config{BaseURL: server.URL, Username: "test", Password: "test"})
+	ctx := context.Background()
+
+	t.Run("add existing product", func(t *testing.T) {
+		item := shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 2, Quantity: 1}
+		if err := client.AddProduct(ctx, &item); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if item.Quantity != 11 {
+			t.Fatalf("Expected %d product quantity, got %d", 11, item.Quantity)
+		}
+	})
+
+	t.Run("add existing variant", func(t *testing.T) {
+		item := shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 3, VariantID: "M", Quantity: 1}
+		if err := client.AddProduct(ctx, &item); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if item.ID != 3 || item.Quantity != 2 {
+			t.Fatalf("Expected line %d with quantity %d, got line %d with quantity %d", 3, 2, item.ID, item.Quantity)
+		}
+	})
+
+	t.Run("add product without quantity", func(t *testing.T) {
+		item := shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 2}
+		if err := client.AddProduct(ctx, &item); !errors.Is(err, shoppingcart.ErrCartItemNoQuantitySet) {
+			t.Fatalf("Expected error %v, got %v", shoppingcart.ErrCartItemNoQuantitySet, err)
+		}
+	})
+}
+
+func TestClient_RemoveProduct(t *testing.T) {
+	server := newServer(t)
+	client := New(Config{BaseURL: server.URL, Username: "test", Password: "test"})
+
+	if err := client.RemoveProduct(context.Background(), 1, 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := client.RemoveItem(context.Background(), 1, 4); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := client.RemoveItem(context.Background(), 1, 99); !errors.Is(err, shoppingcart.ErrCartItemNotFound) {
+		t.Fatalf("Expected error %v, got %v", shoppingcart.ErrCartItemNotFound, err)
+	}
+
+	if err := client.Empty(context.Background(), 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestClient_savedItems(t *testing.T) {
+	server := newServer(t)
+	client := New(Config{BaseURL: server.URL, Username: "test", Password: "test"})
+	ctx := context.Background()
+
+	item, err := client.SaveForLater(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if item.ID != 6 || item.Quantity != 13 {
+		t.Fatalf("Expected line %d with quantity %d, got line %d with quantity %d", 6, 13, item.ID, item.Quantity)
+	}
+
+	if _, err := client.MoveToCart(ctx, 1, 2); !errors.Is(err, shoppingcart.ErrCartItemNotFound) {
+		t.Fatalf("Expected error %v, got %v", shoppingcart.ErrCartItemNotFound, err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	server := newServer(t)
+
+	// The first two attempts fail before the request reaches the service.
+	attempts := 0
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		proxy, err := http.NewRequest(r.Method, server.URL+r.URL.Path, r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proxy.Header = r.Header
+
+		resp, err := http.DefaultClient.Do(proxy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write([]byte(`{"id":1,"user_id":1}`))
+	}))
+	t.Cleanup(flaky.Close)
+
+	t.Run("retries are exhausted", func(t *testing.T) {
+		attempts = 0
+		client := New(Config{BaseURL: flaky.URL, Username: "test", Password: "test", MaxRetries: 1})
+
+		_, err := client.Get(context.Background(), 1)
+		if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status %d, got %v", http.StatusServiceUnavailable, err)
+		}
+	})
+
+	t.Run("request succeeds after retries", func(t *testing.T) {
+		attempts = 0
+		client := New(Config{BaseURL: flaky.URL, Username: "test", Password: "test", MaxRetries: 2})
+
+		if _, err := client.Get(context.Background(), 1); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if attempts != 3 {
+			t.Fatalf("Expected %d attempts, got %d", 3, attempts)
+		}
+	})
+
+	t.Run("non-idempotent requests are not retried", func(t *testing.T) {
+		attempts = 0
+		client := New(Config{BaseURL: flaky.URL, Username: "test", Password: "test", MaxRetries: 2})
+
+		if _, err := client.Create(context.Background()); err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		if attempts != 1 {
+			t.Fatalf("Expected %d attempts, got %d", 1, attempts)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// knownErrors maps the error codes of the API to the errors of the shoppingcart package.
+var knownErrors = map[string]error{
+	"validation_failed": shoppingcart.ErrValidation,
+	"storage_timeout":   shoppingcart.ErrStorageTimeout,
+	"rate_limited":      shoppingcart.ErrRateLimitExceeded,
+	"not_supported":     shoppingcart.ErrNotSupported,
+
+	// Shopping cart
+	"no_permission":     shoppingcart.ErrNoPermission,
+	"user_not_set":      shoppingcart.ErrUserNotSet,
+	"cart_not_found":    shoppingcart.ErrCartNotFound,
+	"cart_has_no_items": shoppingcart.ErrCartHasNoItems,
+	"forbidden":         shoppingcart.ErrForbidden,
+
+	// Shopping cart item
+	"cart_item_not_found":      shoppingcart.ErrCartItemNotFound,
+	"cart_item_already_exists": shoppingcart.ErrCartItemAlreadyExists,
+	"product_not_set":          shoppingcart.ErrCartItemNoProductSet,
+	"quantity_not_set":         shoppingcart.ErrCartItemNoQuantitySet,
+	"out_of_stock":             shoppingcart.ErrOutOfStock,
+	"snapshot_not_found":       shoppingcart.ErrSnapshotNotFound,
+
+	// Wishlist
+	"wishlist_not_found":      shoppingcart.ErrWishlistNotFound,
+	"wishlist_name_not_set":   shoppingcart.ErrWishlistNameNotSet,
+	"wishlist_item_not_found": shoppingcart.ErrWishlistItemNotFound,
+
+	// Cart member
+	"member_not_found":      shoppingcart.ErrMemberNotFound,
+	"member_already_exists": shoppingcart.ErrMemberAlreadyExists,
+	"invalid_role":          shoppingcart.ErrInvalidRole,
+}
+
+// quantityLimitExceeded is the code of shoppingcart.ErrQuantityLimitExceeded,
+// which is returned as shoppingcart.QuantityLimitError
+const quantityLimitExceeded = "quantity_limit_exceeded"
+
+// Error is returned when the API responds with an error which does not match
+// any of the errors of the shoppingcart package.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("shoppingcart: %s (status %d, code %s)", err.Message, err.StatusCode, err.Code)
+}
+
+// problem mirrors the problem details response of the API
+type problem struct {
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id"`
+	Errors    []struct {
+		Field  string `json:"field"`
+		Code   string `json:"code"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+	Limit     string `json:"limit"`
+	ProductID int64  `json:"product_id"`
+	Max       uint64 `json:"max"`
+}
+
+// decodeError converts a problem details response into an error.
+func decodeError(resp *http.Response) error {
+	var p problem
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil || p.Code == "" {
+		return &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+
+	if len(p.Errors) > 0 {
+		errs := make(shoppingcart.ValidationErrors, 0, len(p.Errors))
+		for _, fieldErr := range p.Errors {
+			err, ok := knownErrors[fieldErr.Code]
+			if !ok {
+				err = errors.New(fieldErr.Detail)
+			}
+
+			errs = append(errs, shoppingcart.FieldError{Field: fieldErr.Field, Err: err})
+		}
+
+		return errs
+	}
+
+	if p.Code == quantityLimitExceeded {
+		return &shoppingcart.QuantityLimitError{Limit: p.Limit, ProductID: p.ProductID, Max: p.Max}
+	}
+
+	if err, ok := knownErrors[p.Code]; ok {
+		return err
+	}
+
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+
+	return &Error{StatusCode: resp.StatusCode, Code: p.Code, Message: message, RequestID: p.RequestID}
+}
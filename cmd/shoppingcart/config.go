@@ -1,21 +1,137 @@
 package main
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 // DatabaseConfig defines a configuration for database connection.
+// Driver is either "mysql" (default), "postgres" or "sqlite", which keeps
+// the data in the file of Database. QueryTimeout bounds the queries of every
+// storage call, zero means no limit.
+//
+// The pool settings keep the defaults of database/sql if zero. TLSMode is
+// "disable" (default), "require" or "verify-full"; Params are added to the
+// DSN of the driver. Connecting is retried until ConnectTimeout passes.
 type DatabaseConfig struct {
-	User     string `envconfig:"database_user"`
-	Password string `envconfig:"database_password"`
-	Database string `envconfig:"database_name"`
-	Host     string `envconfig:"database_host"`
-	Port     int    `envconfig:"database_port"`
+	Driver       string        `envconfig:"database_driver" default:"mysql"`
+	User         string        `envconfig:"database_user"`
+	Password     string        `envconfig:"database_password"`
+	Database     string        `envconfig:"database_name"`
+	Host         string        `envconfig:"database_host"`
+	Port         int           `envconfig:"database_port"`
+	QueryTimeout time.Duration `envconfig:"database_query_timeout" default:"5s"`
+
+	MaxOpenConns    int           `envconfig:"database_max_open_conns"`
+	MaxIdleConns    int           `envconfig:"database_max_idle_conns"`
+	ConnMaxLifetime time.Duration `envconfig:"database_conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `envconfig:"database_conn_max_idle_time"`
+
+	TLSMode        string            `envconfig:"database_tls_mode"`
+	TLSCA          string            `envconfig:"database_tls_ca"`
+	TLSCert        string            `envconfig:"database_tls_cert"`
+	TLSKey         string            `envconfig:"database_tls_key"`
+	Params         map[string]string `envconfig:"database_params"`
+	ConnectTimeout time.Duration     `envconfig:"database_connect_timeout" default:"1m"`
+}
+
+// ServerConfig defines how requests are served. RequestTimeout bounds the
+// handling of every HTTP request, zero means no limit. ReadinessTimeout
+// bounds every check of /readyz. On shutdown /readyz fails for ShutdownDelay
+// before the servers stop, so load balancers stop sending requests.
+type ServerConfig struct {
+	RequestTimeout   time.Duration `envconfig:"request_timeout" default:"10s"`
+	ReadinessTimeout time.Duration `envconfig:"readiness_timeout" default:"2s"`
+	ShutdownDelay    time.Duration `envconfig:"shutdown_delay" default:"5s"`
+}
+
+// StorageConfig defines how shopping carts are persisted.
+// Backend is either "sql" (default, "mysql" is kept as an alias) or
+// "eventsourced", which keeps carts as a log of events in the database.
+type StorageConfig struct {
+	Backend          string `envconfig:"backend" default:"sql"`
+	SnapshotInterval int64  `envconfig:"snapshot_interval"`
+}
+
+// CacheConfig defines the in-process cache of shopping carts, zero Size
+// disables it. Carts are cached per instance, so writes through one instance
+// are only seen by the others once the TTL expires.
+type CacheConfig struct {
+	Size        int           `envconfig:"size"`
+	TTL         time.Duration `envconfig:"ttl"`
+	LoadTimeout time.Duration `envconfig:"load_timeout"`
+}
+
+// LimitsConfig defines quantity limits of shopping carts, zero means no limit.
+type LimitsConfig struct {
+	MaxLineQuantity    uint64           `envconfig:"max_line_quantity"`
+	MaxLines           int              `envconfig:"max_lines"`
+	MaxTotalQuantity   uint64           `envconfig:"max_total_quantity"`
+	ProductMaxQuantity map[int64]uint64 `envconfig:"product_max_quantity"`
+}
+
+// CatalogConfig defines the product catalog holding the quantity limits of
+// individual products. File is a JSON list of products with their
+// max_quantity, empty File means there are no such limits besides
+// LimitsConfig.ProductMaxQuantity, which takes precedence.
+type CatalogConfig struct {
+	File string `envconfig:"file"`
+}
+
+// InventoryConfig defines how stock is reserved for products in shopping carts.
+// Backend is either empty (no reservations), "local" or "http".
+type InventoryConfig struct {
+	Backend        string           `envconfig:"backend"`
+	URL            string           `envconfig:"url"`
+	Timeout        time.Duration    `envconfig:"timeout"`
+	ReservationTTL time.Duration    `envconfig:"reservation_ttl"`
+	Stock          map[int64]uint64 `envconfig:"stock"`
+}
+
+// TracingConfig defines how the spans of requests are exported.
+// Exporter is either empty (no tracing), "otlp", which posts spans to the
+// OTLP/HTTP Endpoint, or "file", which appends them to File. SampleRate is
+// the share of requests traced unless the caller decided.
+type TracingConfig struct {
+	Exporter   string  `envconfig:"exporter"`
+	Endpoint   string  `envconfig:"endpoint"`
+	File       string  `envconfig:"file" default:"traces.jsonl"`
+	SampleRate float64 `envconfig:"sample_rate" default:"0.1"`
+}
+
+// LogConfig defines the output of the logs. Level is one of the levels of
+// logrus, e.g. "debug" or "info" (default); Format is "text" (default) or
+// "json".
+type LogConfig struct {
+	Level  string `envconfig:"level" default:"info"`
+	Format string `envconfig:"format" default:"text"`
+}
+
+// RateLimitConfig defines the number of requests per second each user, or
+// client IP if the request is not authenticated, may send on average, along
+// with the number of requests they may send at once. Reads and writes are
+// limited separately, zero rate disables the limit. Limits are kept per
+// instance.
+type RateLimitConfig struct {
+	ReadRate   float64 `envconfig:"read_rate" default:"10"`
+	ReadBurst  int     `envconfig:"read_burst" default:"50"`
+	WriteRate  float64 `envconfig:"write_rate" default:"2"`
+	WriteBurst int     `envconfig:"write_burst" default:"20"`
 }
 
 // Config describes the relevant settings from environment variables.
 type Config struct {
-	Database DatabaseConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Storage   StorageConfig
+	Cache     CacheConfig
+	Limits    LimitsConfig
+	Catalog   CatalogConfig
+	Inventory InventoryConfig
+	Tracing   TracingConfig
+	Log       LogConfig
+	RateLimit RateLimitConfig
 }
 
 // NewConfig returns a Config which is populated by environment variables.
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bugimetal/shoppingcart/storage/mysql"
+	"github.com/bugimetal/shoppingcart/storage/postgres"
+	"github.com/bugimetal/shoppingcart/storage/sqlite"
+	"github.com/bugimetal/shoppingcart/storage/sqlstore"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Backoff between attempts to connect to the database
+const (
+	initialConnectBackoff = 500 * time.Millisecond
+	maxConnectBackoff     = 10 * time.Second
+)
+
+// errUnknownDriver is returned for drivers which are not supported
+var errUnknownDriver = errors.New("unknown database driver")
+
+// connectDatabase connects to the database and configures the connection
+// pool. While the database is not reachable, connecting is retried with
+// exponential backoff until config.ConnectTimeout passes.
+func connectDatabase(config DatabaseConfig) (*sqlstore.DB, error) {
+	deadline := time.Now().Add(config.ConnectTimeout)
+	backoff := initialConnectBackoff
+
+	for {
+		db, err := openDatabase(config)
+		if err == nil {
+			db.SetQueryTimeout(config.QueryTimeout)
+			db.SetPool(sqlstore.PoolConfig{
+				MaxOpenConns:    config.MaxOpenConns,
+				MaxIdleConns:    config.MaxIdleConns,
+				ConnMaxLifetime: config.ConnMaxLifetime,
+				ConnMaxIdleTime: config.ConnMaxIdleTime,
+			})
+			return db, nil
+		}
+
+		// Configuration errors don't go away by waiting
+		if errors.Is(err, errUnknownDriver) || errors.Is(err, sqlstore.ErrInvalidTLSConfig) {
+			return nil, err
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, err
+		}
+
+		logrus.Warnf("Can't connect to database, retrying in %s: %v", backoff, err)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+}
+
+// openDatabase connects to the database of the configured driver.
+// For SQLite the database name is the path of the database file.
+func openDatabase(config DatabaseConfig) (*sqlstore.DB, error) {
+	tls := sqlstore.TLSConfig{
+		Mode:     config.TLSMode,
+		CAFile:   config.TLSCA,
+		CertFile: config.TLSCert,
+		KeyFile:  config.TLSKey,
+	}
+
+	switch config.Driver {
+	case "mysql":
+		return mysql.New(mysql.Config{
+			User:     config.User,
+			Password: config.Password,
+			Host:     config.Host,
+			Port:     config.Port,
+			Database: config.Database,
+			TLS:      tls,
+			Params:   config.Params,
+		})
+	case "postgres":
+		return postgres.New(postgres.Config{
+			User:     config.User,
+			Password: config.Password,
+			Host:     config.Host,
+			Port:     config.Port,
+			Database: config.Database,
+			TLS:      tls,
+			Params:   config.Params,
+		})
+	case "sqlite":
+		return sqlite.New(config.Database)
+	}
+
+	return &sqlstore.DB{}, fmt.Errorf("%w %q", errUnknownDriver, config.Driver)
+}
@@ -12,6 +12,7 @@
 //
 //     Produces:
 //     - application/json
+//     - application/problem+json
 //
 //     Security:
 //     - basic
@@ -27,69 +28,205 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/bugimetal/shoppingcart/catalog"
 	"github.com/bugimetal/shoppingcart/handler"
+	"github.com/bugimetal/shoppingcart/health"
+	"github.com/bugimetal/shoppingcart/internal/logging"
 	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	"github.com/bugimetal/shoppingcart/inventory"
+	"github.com/bugimetal/shoppingcart/migrations"
+	"github.com/bugimetal/shoppingcart/ratelimit"
+	"github.com/bugimetal/shoppingcart/rpc"
 	"github.com/bugimetal/shoppingcart/service"
-	"github.com/bugimetal/shoppingcart/storage/mysql"
+	"github.com/bugimetal/shoppingcart/storage/cache"
+	"github.com/bugimetal/shoppingcart/storage/eventsourced"
+	"github.com/bugimetal/shoppingcart/tracing"
+
+	"github.com/sirupsen/logrus"
 )
 
 var (
-	bind = flag.String("bind", ":8080", "The socket to bind the HTTP server")
+	bind     = flag.String("bind", ":8080", "The socket to bind the HTTP server")
+	grpcBind = flag.String("grpc-bind", ":9090", "The socket to bind the gRPC server, empty to disable it")
+	migrate  = flag.Bool("migrate", false, "Apply pending schema migrations before serving")
 )
 
 func main() {
 	flag.Parse()
 
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(flag.Args()[1:]); err != nil {
+			logrus.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// Fatal exits right away, so it is only called once run returned and
+	// its deferred calls, which close the database and flush traces, ran.
+	if err := run(); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// run serves the HTTP and gRPC APIs until a termination signal is received
+// or one of the servers fails
+func run() error {
 	config, err := NewConfig()
 	if err != nil {
-		log.Fatalf("Can't read the config: %v", err)
+		return fmt.Errorf("can't read the config: %w", err)
+	}
+
+	if err := logging.Setup(config.Log.Level, config.Log.Format); err != nil {
+		return fmt.Errorf("can't set up logging: %w", err)
+	}
+
+	flushTraces, err := tracing.Setup(tracing.Config{
+		Exporter:   config.Tracing.Exporter,
+		Endpoint:   config.Tracing.Endpoint,
+		File:       config.Tracing.File,
+		SampleRate: config.Tracing.SampleRate,
+	})
+	if err != nil {
+		return fmt.Errorf("can't set up tracing: %w", err)
 	}
+	defer flushTraces()
 
-	storage, err := mysql.New(
-		config.Database.User,
-		config.Database.Password,
-		config.Database.Host,
-		config.Database.Database,
-		config.Database.Port,
-	)
+	storage, err := connectDatabase(config.Database)
+	if err != nil {
+		return fmt.Errorf("can't connect to database: %w", err)
+	}
 	defer storage.Close()
 
+	if err := storage.RegisterPoolMetrics(); err != nil {
+		return fmt.Errorf("can't export database metrics: %w", err)
+	}
+
+	migrator, err := migrations.New(storage.DB(), config.Database.Driver)
 	if err != nil {
-		log.Fatalf("Can't connect to database: %v", err)
+		return fmt.Errorf("can't load migrations: %w", err)
+	}
+
+	if err := checkSchema(context.Background(), migrator, *migrate); err != nil {
+		return fmt.Errorf("can't serve: %w", err)
+	}
+
+	var cartStorage service.ShoppingCartStorage
+	switch config.Storage.Backend {
+	case "sql", "mysql":
+		cartStorage = storage
+	case "eventsourced":
+		cartStorage = eventsourced.New(storage.EventLog(), eventsourced.Config{
+			Members:          storage,
+			SnapshotInterval: config.Storage.SnapshotInterval,
+		})
+	default:
+		return fmt.Errorf("unknown storage backend %q", config.Storage.Backend)
+	}
+
+	var memberStorage service.CartMemberStorage = storage
+	if config.Cache.Size > 0 {
+		cachedStorage := cache.New(cartStorage, cache.NewLRU(config.Cache.Size), cache.Config{
+			Members:     storage,
+			TTL:         config.Cache.TTL,
+			LoadTimeout: config.Cache.LoadTimeout,
+		})
+		cartStorage, memberStorage = cachedStorage, cachedStorage
+	}
+
+	var productCatalog service.ProductCatalog
+	if config.Catalog.File != "" {
+		if productCatalog, err = catalog.LoadFile(config.Catalog.File); err != nil {
+			return fmt.Errorf("unable to load product catalog: %w", err)
+		}
+	}
+
+	var inventoryService service.InventoryService
+	switch config.Inventory.Backend {
+	case "":
+	case "local":
+		inventoryService = inventory.NewLocal(config.Inventory.Stock, config.Inventory.ReservationTTL)
+	case "http":
+		inventoryService = inventory.NewClient(inventory.ClientConfig{
+			BaseURL: config.Inventory.URL,
+			Timeout: config.Inventory.Timeout,
+		})
+	default:
+		return fmt.Errorf("unknown inventory backend %q", config.Inventory.Backend)
 	}
 
 	// Service covers the high-level business logic.
 	services := service.New(service.Dependencies{
-		ShoppingCartStorage: storage,
+		ShoppingCartStorage: cartStorage,
+		CartMemberStorage:   memberStorage,
+		SnapshotStorage:     storage,
+		HistoryStorage:      storage,
+		WishlistStorage:     storage,
+		InventoryService:    inventoryService,
+		ProductCatalog:      productCatalog,
+		Limits: service.Limits{
+			MaxLineQuantity:    config.Limits.MaxLineQuantity,
+			MaxLines:           config.Limits.MaxLines,
+			MaxTotalQuantity:   config.Limits.MaxTotalQuantity,
+			ProductMaxQuantity: config.Limits.ProductMaxQuantity,
+		},
 	})
 
 	// Initializing external authorisation service
 	authService := auth.New()
 
-	h := handler.New(handler.Services{
+	// Readiness depends on the database and the auth service
+	checker := health.New(config.Server.ReadinessTimeout)
+	checker.Register("database", storage.Ping)
+	checker.Register("auth", authService.Ping)
+
+	handlerServices := handler.Services{
 		ShoppingCart: services.ShoppingCart,
+		Wishlist:     services.Wishlist,
 		Auth:         authService,
-	})
+		Health:       checker,
+		RateLimiter: ratelimit.New(ratelimit.NewMemory(), ratelimit.Config{
+			Read:  ratelimit.Limit{Rate: config.RateLimit.ReadRate, Burst: config.RateLimit.ReadBurst},
+			Write: ratelimit.Limit{Rate: config.RateLimit.WriteRate, Burst: config.RateLimit.WriteBurst},
+		}),
+	}
+
+	h := handler.New(handlerServices)
 
 	httpServer := &http.Server{
 		Addr:    *bind,
-		Handler: h,
+		Handler: handler.TimeoutMiddleware(h, config.Server.RequestTimeout),
 	}
 
 	// Start the HTTP server.
 	httpServerErrorChan := make(chan error)
 	go func() {
-		fmt.Printf("HTTP server listening on %s\n", *bind)
+		logrus.Infof("HTTP server listening on %s", *bind)
 		httpServerErrorChan <- httpServer.ListenAndServe()
 	}()
 
+	// Start the gRPC server.
+	grpcServer := rpc.New(handlerServices)
+	grpcServerErrorChan := make(chan error)
+	if *grpcBind != "" {
+		go func() {
+			lis, err := net.Listen("tcp", *grpcBind)
+			if err != nil {
+				grpcServerErrorChan <- err
+				return
+			}
+
+			logrus.Infof("gRPC server listening on %s", *grpcBind)
+			grpcServerErrorChan <- grpcServer.Serve(lis)
+		}()
+	}
+
 	// Set up the signal channel.
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
@@ -97,16 +234,28 @@ func main() {
 	select {
 	// If the HTTP server returned an error, exit here.
 	case err := <-httpServerErrorChan:
-		log.Printf("HTTP server error: %s", err)
+		logrus.Errorf("HTTP server error: %s", err)
+	// If the gRPC server returned an error, exit here.
+	case err := <-grpcServerErrorChan:
+		logrus.Errorf("gRPC server error: %s", err)
 	// If a termination signal was received, shutdown the server.
 	case sig := <-signalChan:
-		log.Printf("Signal received: %s", sig)
+		logrus.Infof("Signal received: %s", sig)
+
+		// Fail readiness first, so load balancers drain the traffic before
+		// the servers stop accepting requests.
+		checker.ShutDown()
+		time.Sleep(config.Server.ShutdownDelay)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	grpcServer.GracefulStop()
+
 	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Fatalf("HTTP Server graceful shutdown failed with an error: %s\n", err)
+		return fmt.Errorf("HTTP server graceful shutdown failed with an error: %w", err)
 	}
+
+	return nil
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bugimetal/shoppingcart/migrations"
+
+	"github.com/sirupsen/logrus"
+)
+
+const migrateUsage = `Usage: shoppingcart migrate <command>
+
+Commands:
+  up            Apply all pending migrations
+  down          Revert the latest applied migration
+  status        List migrations and whether they are applied
+  create NAME   Create an empty migration for every database driver
+`
+
+// runMigrate runs the migrate subcommand with args, the arguments after "migrate"
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := flags.String("dir", "./migrations", "The migrations directory of the source tree, used by create")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), migrateUsage+"\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	switch flags.Arg(0) {
+	case "create":
+		if flags.NArg() != 2 {
+			flags.Usage()
+			return errors.New("create requires the name of the migration")
+		}
+
+		paths, err := migrations.Create(*dir, flags.Arg(1), time.Now())
+		for _, path := range paths {
+			fmt.Printf("Created %s\n", path)
+		}
+		return err
+	case "up", "down", "status":
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown migrate command %q", flags.Arg(0))
+	}
+
+	config, err := NewConfig()
+	if err != nil {
+		return fmt.Errorf("can't read the config: %w", err)
+	}
+
+	storage, err := connectDatabase(config.Database)
+	if err != nil {
+		return fmt.Errorf("can't connect to database: %w", err)
+	}
+	defer storage.Close()
+
+	migrator, err := migrations.New(storage.DB(), config.Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch flags.Arg(0) {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("Applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("The schema is up to date")
+		}
+		return err
+	case "down":
+		migration, err := migrator.Down(ctx)
+		if err == nil {
+			fmt.Printf("Reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MIGRATION\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d_%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+
+	return w.Flush()
+}
+
+// checkSchema applies the pending migrations if migrate is set, and fails
+// if any migration is still pending, as the service can't work on an
+// outdated schema
+func checkSchema(ctx context.Context, migrator *migrations.Migrator, migrate bool) error {
+	if migrate {
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			logrus.Infof("Applied migration %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("the database schema is outdated, %d migrations are pending starting with %d_%s: run `shoppingcart migrate up` or start with -migrate",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+
+	return nil
+}
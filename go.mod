@@ -1,12 +1,17 @@
 module github.com/bugimetal/shoppingcart
 
-go 1.14
+go 1.16
 
 require (
 	contrib.go.opencensus.io/exporter/prometheus v0.1.0
+	github.com/go-sql-driver/mysql v1.4.1
+	github.com/golang/protobuf v1.3.5
 	github.com/jinzhu/gorm v1.9.12
 	github.com/julienschmidt/httprouter v1.3.0
 	github.com/kelseyhightower/envconfig v1.4.0
+	github.com/lib/pq v1.1.1
+	github.com/mattn/go-sqlite3 v2.0.1+incompatible
 	github.com/sirupsen/logrus v1.5.0
 	go.opencensus.io v0.22.3
+	google.golang.org/grpc v1.29.1
 )
@@ -1,64 +1,234 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/internal/requestid"
+)
 
-	"github.com/sirupsen/logrus"
+// These errors are returned by the handler itself when the request can't be parsed
+var (
+	ErrMalformedBody    = errors.New("request body is malformed")
+	ErrInvalidParameter = errors.New("parameter is not valid")
 )
 
+// StatusClientClosedRequest is reported when the client went away before the
+// request was handled. It is not sent to the client, but shows in logs and
+// metrics.
+const StatusClientClosedRequest = 499
+
+// problemTypeBase prefixes error codes to build the problem type URI
+const problemTypeBase = "urn:shoppingcart:problem:"
+
 // ErrorStatusCodes maps commonly returned errors to HTTP status codes
 var ErrorStatusCodes = map[error]int{
+	shoppingcart.ErrValidation: http.StatusBadRequest,
+	ErrMalformedBody:           http.StatusBadRequest,
+	ErrInvalidParameter:        http.StatusBadRequest,
+	ErrBodyTooLarge:            http.StatusRequestEntityTooLarge,
+	ErrUnknownField:            http.StatusBadRequest,
+	ErrInvalidValue:            http.StatusBadRequest,
+	ErrOutOfRange:              http.StatusBadRequest,
+
+	// Request
+	context.Canceled:                  StatusClientClosedRequest,
+	context.DeadlineExceeded:          http.StatusGatewayTimeout,
+	shoppingcart.ErrStorageTimeout:    http.StatusServiceUnavailable,
+	shoppingcart.ErrRateLimitExceeded: http.StatusTooManyRequests,
+	shoppingcart.ErrNotSupported:      http.StatusNotImplemented,
+
 	// Shopping cart
+	shoppingcart.ErrUserNotSet:     http.StatusBadRequest,
 	shoppingcart.ErrCartNotFound:   http.StatusNotFound,
 	shoppingcart.ErrCartHasNoItems: http.StatusBadRequest,
 	shoppingcart.ErrNoPermission:   http.StatusUnauthorized,
+	shoppingcart.ErrForbidden:      http.StatusForbidden,
 
 	// Shopping cart item
 	shoppingcart.ErrCartItemNoProductSet:  http.StatusBadRequest,
 	shoppingcart.ErrCartItemNoQuantitySet: http.StatusBadRequest,
 	shoppingcart.ErrCartItemNotFound:      http.StatusNotFound,
 	shoppingcart.ErrCartItemAlreadyExists: http.StatusBadRequest,
+	shoppingcart.ErrQuantityLimitExceeded: http.StatusUnprocessableEntity,
+	shoppingcart.ErrOutOfStock:            http.StatusConflict,
+	shoppingcart.ErrSnapshotNotFound:      http.StatusNotFound,
+
+	// Wishlist
+	shoppingcart.ErrWishlistNotFound:     http.StatusNotFound,
+	shoppingcart.ErrWishlistNameNotSet:   http.StatusBadRequest,
+	shoppingcart.ErrWishlistItemNotFound: http.StatusNotFound,
+
+	// Cart member
+	shoppingcart.ErrMemberNotFound:      http.StatusNotFound,
+	shoppingcart.ErrMemberAlreadyExists: http.StatusConflict,
+	shoppingcart.ErrInvalidRole:         http.StatusBadRequest,
 }
 
-// errorResponse represents error response structure
-// swagger:response errorResponse
-type errorResponse struct {
-	Resource *errorResource `json:"error"`
+// ErrorCodes maps commonly returned errors to stable, machine-readable codes.
+// Codes are part of the API and must not change once released.
+var ErrorCodes = map[error]string{
+	shoppingcart.ErrValidation: "validation_failed",
+	ErrMalformedBody:           "malformed_body",
+	ErrInvalidParameter:        "invalid_parameter",
+	ErrBodyTooLarge:            "body_too_large",
+	ErrUnknownField:            "unknown_field",
+	ErrInvalidValue:            "invalid_value",
+	ErrOutOfRange:              "out_of_range",
+
+	// Request
+	context.Canceled:                  "request_canceled",
+	context.DeadlineExceeded:          "request_timeout",
+	shoppingcart.ErrStorageTimeout:    "storage_timeout",
+	shoppingcart.ErrRateLimitExceeded: "rate_limited",
+	shoppingcart.ErrNotSupported:      "not_supported",
+
+	// Shopping cart
+	shoppingcart.ErrUserNotSet:     "user_not_set",
+	shoppingcart.ErrCartNotFound:   "cart_not_found",
+	shoppingcart.ErrCartHasNoItems: "cart_has_no_items",
+	shoppingcart.ErrNoPermission:   "no_permission",
+	shoppingcart.ErrForbidden:      "forbidden",
+
+	// Shopping cart item
+	shoppingcart.ErrCartItemNoProductSet:  "product_not_set",
+	shoppingcart.ErrCartItemNoQuantitySet: "quantity_not_set",
+	shoppingcart.ErrCartItemNotFound:      "cart_item_not_found",
+	shoppingcart.ErrCartItemAlreadyExists: "cart_item_already_exists",
+	shoppingcart.ErrQuantityLimitExceeded: "quantity_limit_exceeded",
+	shoppingcart.ErrOutOfStock:            "out_of_stock",
+	shoppingcart.ErrSnapshotNotFound:      "snapshot_not_found",
+
+	// Wishlist
+	shoppingcart.ErrWishlistNotFound:     "wishlist_not_found",
+	shoppingcart.ErrWishlistNameNotSet:   "wishlist_name_not_set",
+	shoppingcart.ErrWishlistItemNotFound: "wishlist_item_not_found",
+
+	// Cart member
+	shoppingcart.ErrMemberNotFound:      "member_not_found",
+	shoppingcart.ErrMemberAlreadyExists: "member_already_exists",
+	shoppingcart.ErrInvalidRole:         "invalid_role",
 }
 
-type errorResource struct {
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+// internalErrorCode is reported for errors which are not known to the handler
+const internalErrorCode = "internal_error"
+
+// problem represents an error response as described by RFC 7807
+// swagger:response problem
+type problem struct {
+	// in: body
+	Body problemResource
 }
 
-func newErrorResponse(err error) *errorResponse {
-	return &errorResponse{Resource: &errorResource{Code: statusCode(err), Message: err.Error()}}
+type problemResource struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      string         `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []fieldProblem `json:"errors,omitempty"`
+
+	// Limit, ProductID and Max describe the exceeded quantity limit
+	Limit     string `json:"limit,omitempty"`
+	ProductID int64  `json:"product_id,omitempty"`
+	Max       uint64 `json:"max,omitempty"`
 }
 
-func (er *errorResource) Error() string {
-	s := strings.SplitAfter(er.Message, ": ")
-	msg := s[len(s)-1]
+// fieldProblem describes a single invalid field of the request
+type fieldProblem struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+func newProblem(r *http.Request, err error) *problemResource {
+	known := knownError(err)
+	if known == nil {
+		return &problemResource{
+			Type:      "about:blank",
+			Title:     http.StatusText(http.StatusInternalServerError),
+			Status:    http.StatusInternalServerError,
+			Instance:  r.URL.Path,
+			Code:      internalErrorCode,
+			RequestID: requestid.FromContext(r.Context()),
+		}
+	}
+
+	p := &problemResource{
+		Type:      problemTypeBase + ErrorCodes[known],
+		Title:     known.Error(),
+		Status:    ErrorStatusCodes[known],
+		Detail:    err.Error(),
+		Instance:  r.URL.Path,
+		Code:      ErrorCodes[known],
+		RequestID: requestid.FromContext(r.Context()),
+	}
+
+	var validationErrs shoppingcart.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		for _, fieldErr := range validationErrs {
+			code := internalErrorCode
+			if knownFieldErr := knownError(fieldErr.Err); knownFieldErr != nil {
+				code = ErrorCodes[knownFieldErr]
+			}
+
+			p.Errors = append(p.Errors, fieldProblem{
+				Field:  fieldErr.Field,
+				Code:   code,
+				Detail: fieldErr.Err.Error(),
+			})
+		}
+	}
 
-	return msg
+	var limitErr *shoppingcart.QuantityLimitError
+	if errors.As(err, &limitErr) {
+		p.Limit = limitErr.Limit
+		p.ProductID = limitErr.ProductID
+		p.Max = limitErr.Max
+	}
+
+	return p
 }
 
-// statusCode returns the HTTP status code that is appropriate for the specified error.
-func statusCode(err error) int {
-	if statusCode, ok := ErrorStatusCodes[err]; ok {
-		return statusCode
+// knownError returns the error listed in ErrorStatusCodes that err matches,
+// following wrapped errors. It returns nil if there is no such error.
+func knownError(err error) error {
+	var validationErrs shoppingcart.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return shoppingcart.ErrValidation
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		for known := range ErrorStatusCodes {
+			if e == known {
+				return known
+			}
+		}
 	}
 
-	return http.StatusInternalServerError
+	for known := range ErrorStatusCodes {
+		if errors.Is(err, known) {
+			return known
+		}
+	}
+
+	return nil
 }
 
+// Error responds with the problem details of err
 func (handler *Handler) Error(w http.ResponseWriter, r *http.Request, err error) {
-	w.WriteHeader(statusCode(err))
+	p := newProblem(r, err)
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
 
-	if err := json.NewEncoder(w).Encode(newErrorResponse(err)); err != nil {
-		logrus.Errorf("unable to decode struct to json: %s", err)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		logging.FromContext(r.Context()).Errorf("unable to decode struct to json: %s", err)
 	}
 }
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestHandler_Error(t *testing.T) {
+	handler := &Handler{}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{
+			name:       "known error",
+			err:        shoppingcart.ErrCartNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "cart_not_found",
+			wantDetail: shoppingcart.ErrCartNotFound.Error(),
+		},
+		{
+			name:       "wrapped error",
+			err:        fmt.Errorf("loading cart 1: %w", shoppingcart.ErrCartNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "cart_not_found",
+			wantDetail: "loading cart 1: shopping cart not found",
+		},
+		{
+			name:       "client went away",
+			err:        fmt.Errorf("loading cart 1: %w", context.Canceled),
+			wantStatus: StatusClientClosedRequest,
+			wantCode:   "request_canceled",
+			wantDetail: "loading cart 1: context canceled",
+		},
+		{
+			name:       "request timed out",
+			err:        context.DeadlineExceeded,
+			wantStatus: http.StatusGatewayTimeout,
+			wantCode:   "request_timeout",
+			wantDetail: context.DeadlineExceeded.Error(),
+		},
+		{
+			name:       "storage timed out",
+			err:        shoppingcart.ErrStorageTimeout,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "storage_timeout",
+			wantDetail: shoppingcart.ErrStorageTimeout.Error(),
+		},
+		{
+			name:       "unknown error",
+			err:        errors.New("dial tcp 127.0.0.1:3306: connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodGet, "/v1/shoppingcart/1", nil)
+
+			handler.Error(w, r, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatus, w.Code)
+			}
+
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Fatalf("Expected content type application/problem+json, got %s", contentType)
+			}
+
+			var p problemResource
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("Can't decode response: %v", err)
+			}
+
+			if p.Code != tt.wantCode || p.Status != tt.wantStatus || p.Detail != tt.wantDetail {
+				t.Fatalf("Unexpected problem %+v", p)
+			}
+
+			if p.Instance != "/v1/shoppingcart/1" {
+				t.Fatalf("Expected instance %s, got %s", "/v1/shoppingcart/1", p.Instance)
+			}
+		})
+	}
+}
+
+func TestHandler_Error_quantityLimit(t *testing.T) {
+	handler := &Handler{}
+
+	w := httptest.NewRecorder()
+	r := newRequest(http.MethodPost, "/v1/shoppingcart/1/item", nil)
+
+	handler.Error(w, r, &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitLineQuantity, ProductID: 5, Max: 2})
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected HTTP status code %d, but got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+
+	var p problemResource
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("Can't decode response: %v", err)
+	}
+
+	if p.Code != "quantity_limit_exceeded" || p.Limit != shoppingcart.LimitLineQuantity || p.ProductID != 5 || p.Max != 2 {
+		t.Fatalf("Unexpected problem %+v", p)
+	}
+}
+
+func TestHandler_Error_validation(t *testing.T) {
+	handler := &Handler{
+		shoppingCartService: &shoppingcart_mock.MockShoppingCartService{},
+		authService:         auth.New(),
+	}
+
+	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
+
+	t.Run("every invalid field is reported", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest(http.MethodPost, "/shoppingcart/1/item", addProductRequest{})
+		r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+		handler.authMiddleware(handler.addProduct)(w, r, []httprouter.Param{{Key: "id", Value: "1"}})
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected HTTP status code %d, but got %d", http.StatusBadRequest, w.Code)
+		}
+
+		var p problemResource
+		if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+			t.Fatalf("Can't decode response: %v", err)
+		}
+
+		want := []fieldProblem{
+			{Field: "product_id", Code: "product_not_set", Detail: shoppingcart.ErrCartItemNoProductSet.Error()},
+			{Field: "quantity", Code: "quantity_not_set", Detail: shoppingcart.ErrCartItemNoQuantitySet.Error()},
+		}
+		if p.Code != "validation_failed" || fmt.Sprint(p.Errors) != fmt.Sprint(want) {
+			t.Fatalf("Unexpected problem %+v", p)
+		}
+	})
+
+	t.Run("malformed body does not leak decoder errors", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/shoppingcart/1/item", strings.NewReader(`{"product_id": `))
+		r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+		handler.authMiddleware(handler.addProduct)(w, r, []httprouter.Param{{Key: "id", Value: "1"}})
+
+		var p problemResource
+		if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+			t.Fatalf("Can't decode response: %v", err)
+		}
+
+		if p.Code != "malformed_body" || p.Detail != ErrMalformedBody.Error() {
+			t.Fatalf("Unexpected problem %+v", p)
+		}
+	})
+
+	t.Run("invalid path parameter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest(http.MethodGet, "/shoppingcart/abc", nil)
+		r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+		handler.authMiddleware(handler.getShoppingCart)(w, r, []httprouter.Param{{Key: "id", Value: "abc"}})
+
+		var p problemResource
+		if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+			t.Fatalf("Can't decode response: %v", err)
+		}
+
+		if w.Code != http.StatusBadRequest || len(p.Errors) != 1 || p.Errors[0].Field != "id" {
+			t.Fatalf("Unexpected problem %+v", p)
+		}
+	})
+}
@@ -8,12 +8,16 @@ import (
 	"time"
 
 	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/health"
+	"github.com/bugimetal/shoppingcart/internal/logging"
 	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	"github.com/bugimetal/shoppingcart/ratelimit"
 
 	"contrib.go.opencensus.io/exporter/prometheus"
 	"github.com/julienschmidt/httprouter"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
 	"go.opencensus.io/stats/view"
 )
 
@@ -30,9 +34,40 @@ type ShoppingCartService interface {
 	Create(context.Context, *shoppingcart.ShoppingCart) error
 	Get(ctx context.Context, shoppingCartID int64, userID int64) (shoppingcart.ShoppingCart, error)
 	Empty(ctx context.Context, shoppingCartID, userID int64) error
+	Checkout(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.ShoppingCart, error)
 
 	AddProduct(ctx context.Context, item *shoppingcart.ShoppingCartItem, userID int64) error
 	RemoveProduct(ctx context.Context, shoppingCartID, productID, userID int64) error
+	RemoveItem(ctx context.Context, shoppingCartID, itemID, userID int64) error
+
+	SaveForLater(ctx context.Context, shoppingCartID, itemID, userID int64) (shoppingcart.ShoppingCartItem, error)
+	MoveToCart(ctx context.Context, shoppingCartID, itemID, userID int64) (shoppingcart.ShoppingCartItem, error)
+
+	Invite(ctx context.Context, shoppingCartID, userID int64, member *shoppingcart.CartMember) error
+	AcceptInvitation(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.CartMember, error)
+	RevokeMember(ctx context.Context, shoppingCartID, userID, memberUserID int64) error
+
+	History(ctx context.Context, shoppingCartID, userID int64, limit, offset int) ([]shoppingcart.HistoryEntry, error)
+	AdminHistory(ctx context.Context, shoppingCartID int64, limit, offset int) ([]shoppingcart.HistoryEntry, error)
+
+	Snapshot(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.Snapshot, error)
+	ListSnapshots(ctx context.Context, shoppingCartID, userID int64) ([]shoppingcart.Snapshot, error)
+	GetSnapshot(ctx context.Context, shoppingCartID, snapshotID, userID int64) (shoppingcart.Snapshot, error)
+	Restore(ctx context.Context, shoppingCartID, snapshotID, userID int64) (shoppingcart.ShoppingCart, error)
+}
+
+// WishlistService provides an interface to the service that deals with operations
+// on wishlists and wishlist items.
+type WishlistService interface {
+	Create(context.Context, *shoppingcart.Wishlist) error
+	Get(ctx context.Context, wishlistID, userID int64) (shoppingcart.Wishlist, error)
+	List(ctx context.Context, userID int64) ([]shoppingcart.Wishlist, error)
+	Rename(ctx context.Context, wishlistID, userID int64, name string) (shoppingcart.Wishlist, error)
+	Delete(ctx context.Context, wishlistID, userID int64) error
+
+	AddProduct(ctx context.Context, item *shoppingcart.WishlistItem, userID int64) error
+	RemoveItem(ctx context.Context, wishlistID, itemID, userID int64) error
+	MoveToCart(ctx context.Context, wishlistID, itemID, shoppingCartID, userID int64) (shoppingcart.ShoppingCartItem, error)
 }
 
 // AuthService provides an interface to the service that deals with user authentication.
@@ -41,16 +76,23 @@ type AuthService interface {
 }
 
 // Services describe the external services that the Handler relies on.
+// Health checks the readiness of the service, which is always ready if nil.
 type Services struct {
 	ShoppingCart ShoppingCartService
+	Wishlist     WishlistService
 	Auth         AuthService
+	Health       *health.Checker
+	RateLimiter  RateLimiter
 }
 
 // Handler provides an generic interface for handling HTTP requests.
 type Handler struct {
 	http                http.Handler
 	shoppingCartService ShoppingCartService
+	wishlistService     WishlistService
 	authService         AuthService
+	health              *health.Checker
+	rateLimiter         RateLimiter
 }
 
 func init() {
@@ -79,26 +121,57 @@ func init() {
 func New(services Services) *Handler {
 	handler := &Handler{
 		shoppingCartService: services.ShoppingCart,
+		wishlistService:     services.Wishlist,
 		authService:         services.Auth,
+		health:              services.Health,
+		rateLimiter:         services.RateLimiter,
 	}
 
 	// Set up a custom HTTP router and install the routes on it.
-	router := httprouter.New()
+	router := routes{httprouter.New()}
 
 	router.Handler("GET", "/metrics", exporter)
 	router.GET("/health-check", healthCheck)
+	router.GET("/livez", livez)
+	router.GET("/readyz", handler.readyz)
 
 	router.POST("/v1/shoppingcart", handler.authMiddleware(handler.createShoppingCart))
 	router.GET("/v1/shoppingcart/:id", handler.authMiddleware(handler.getShoppingCart))
 	router.DELETE("/v1/shoppingcart/:id/item", handler.authMiddleware(handler.emptyCart))
+	router.POST("/v1/shoppingcart/:id/checkout", handler.authMiddleware(handler.checkout))
+	router.GET("/v1/shoppingcart/:id/history", handler.authMiddleware(handler.getHistory))
+	router.POST("/v1/shoppingcart/:id/snapshot", handler.authMiddleware(handler.createSnapshot))
+	router.GET("/v1/shoppingcart/:id/snapshot", handler.authMiddleware(handler.listSnapshots))
+	router.GET("/v1/shoppingcart/:id/snapshot/:snapshot_id", handler.authMiddleware(handler.getSnapshot))
+	router.POST("/v1/shoppingcart/:id/snapshot/:snapshot_id/restore", handler.authMiddleware(handler.restoreSnapshot))
 
 	router.POST("/v1/shoppingcart/:id/item", handler.authMiddleware(handler.addProduct))
 	router.DELETE("/v1/shoppingcart/:id/item/:product_id", handler.authMiddleware(handler.removeProduct))
+	router.DELETE("/v1/shoppingcart/:id/line/:item_id", handler.authMiddleware(handler.removeItem))
+	router.POST("/v1/shoppingcart/:id/line/:item_id/save-for-later", handler.authMiddleware(handler.saveForLater))
+	router.POST("/v1/shoppingcart/:id/line/:item_id/move-to-cart", handler.authMiddleware(handler.moveToCart))
+
+	router.POST("/v1/shoppingcart/:id/member", handler.authMiddleware(handler.inviteMember))
+	router.POST("/v1/shoppingcart/:id/member/accept", handler.authMiddleware(handler.acceptInvitation))
+	router.DELETE("/v1/shoppingcart/:id/member/:user_id", handler.authMiddleware(handler.revokeMember))
+
+	router.POST("/v1/wishlist", handler.authMiddleware(handler.createWishlist))
+	router.GET("/v1/wishlist", handler.authMiddleware(handler.listWishlists))
+	router.GET("/v1/wishlist/:id", handler.authMiddleware(handler.getWishlist))
+	router.PATCH("/v1/wishlist/:id", handler.authMiddleware(handler.renameWishlist))
+	router.DELETE("/v1/wishlist/:id", handler.authMiddleware(handler.deleteWishlist))
+	router.POST("/v1/wishlist/:id/item", handler.authMiddleware(handler.addWishlistProduct))
+	router.DELETE("/v1/wishlist/:id/item/:item_id", handler.authMiddleware(handler.removeWishlistItem))
+	router.POST("/v1/wishlist/:id/item/:item_id/move-to-cart", handler.authMiddleware(handler.moveWishlistItemToCart))
 
 	// Running swagger API documentation
 	router.ServeFiles("/swagger/*filepath", http.Dir("./swagger/"))
 
-	handler.http = &ochttp.Handler{Handler: router}
+	// Traces are continued from the W3C traceparent header of the request
+	handler.http = &ochttp.Handler{
+		Handler:     loggingMiddleware(router),
+		Propagation: &tracecontext.HTTPFormat{},
+	}
 
 	return handler
 }
@@ -109,6 +182,23 @@ func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	handler.http.ServeHTTP(w, r)
 }
 
+// TimeoutMiddleware bounds the handling of every request to timeout: the
+// context of the request ends once it expires, along with the storage calls
+// of the request, and the request fails with 504 Gateway Timeout. Zero
+// disables the timeout.
+func TimeoutMiddleware(next http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // authUser returns the authenticated user.
 func (handler *Handler) authUser(r *http.Request) (auth.User, error) {
 	user, ok := r.Context().Value(userKey).(auth.User)
@@ -119,33 +209,26 @@ func (handler *Handler) authUser(r *http.Request) (auth.User, error) {
 	return user, nil
 }
 
-// authMiddleware authenticates user using auth service
+// authMiddleware authenticates user using auth service and limits the rate of
+// requests of the user
 func (handler *Handler) authMiddleware(next httprouter.Handle) httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		// Using Basic Auth just to save development time.
-		if r.Header.Get("Authorization") == "" {
-			handler.Error(w, r, shoppingcart.ErrNoPermission)
-			return
+		user, err := ParseBasicAuth(r.Header.Get("Authorization"))
+		if err == nil {
+			user, err = handler.authService.Authenticate(r.Context(), user)
 		}
-
-		authHeader := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
-
-		if len(authHeader) != 2 || authHeader[0] != "Basic" {
-			handler.Error(w, r, shoppingcart.ErrNoPermission)
+		if err != nil {
+			// Requests failing authentication are limited by client IP
+			if handler.rateLimit(w, r, ipClient(r)) {
+				handler.Error(w, r, shoppingcart.ErrNoPermission)
+			}
 			return
 		}
 
-		payload, _ := base64.StdEncoding.DecodeString(authHeader[1])
-		pair := strings.SplitN(string(payload), ":", 2)
-
-		user := auth.User{
-			Name:     pair[0],
-			Password: []byte(pair[1]),
-		}
+		logging.AddFields(r.Context(), logrus.Fields{"user_id": user.ID})
 
-		user, err := handler.authService.Authenticate(r.Context(), user)
-		if err != nil {
-			handler.Error(w, r, shoppingcart.ErrNoPermission)
+		if !handler.rateLimit(w, r, ratelimit.UserClient(user.ID)) {
 			return
 		}
 
@@ -154,8 +237,32 @@ func (handler *Handler) authMiddleware(next httprouter.Handle) httprouter.Handle
 	}
 }
 
+// ParseBasicAuth extracts user credentials from the value of an Authorization header.
+func ParseBasicAuth(header string) (auth.User, error) {
+	authHeader := strings.SplitN(header, " ", 2)
+	if len(authHeader) != 2 || authHeader[0] != "Basic" {
+		return auth.User{}, shoppingcart.ErrNoPermission
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(authHeader[1])
+	if err != nil {
+		return auth.User{}, shoppingcart.ErrNoPermission
+	}
+
+	pair := strings.SplitN(string(payload), ":", 2)
+	if len(pair) != 2 {
+		return auth.User{}, shoppingcart.ErrNoPermission
+	}
+
+	return auth.User{
+		Name:     pair[0],
+		Password: []byte(pair[1]),
+	}, nil
+}
+
+// healthCheck is kept for existing probes, it reports liveness like /livez.
 func healthCheck(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	if _, err := w.Write([]byte("OK")); err != nil {
-		logrus.Println(err)
+		logging.FromContext(r.Context()).Errorf("Unable to respond with health check %s", err)
 	}
 }
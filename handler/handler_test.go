@@ -2,9 +2,14 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
 )
 
 // newRequest returns a new http request. If a body has been specified, it will
@@ -24,3 +29,67 @@ func newRequest(method, path string, body interface{}) *http.Request {
 
 	return r
 }
+
+// testAuth authenticates the users of the auth mock and, in addition, the
+// viewer and the invitee of the shared cart of the storage mock and an admin.
+type testAuth struct{}
+
+func (testAuth) Authenticate(ctx context.Context, user auth.User) (auth.User, error) {
+	user, err := auth.New().Authenticate(ctx, user)
+	if err != nil {
+		return user, err
+	}
+
+	switch user.Name {
+	case "viewer":
+		user.ID = 4
+	case "invitee":
+		user.ID = 5
+	case "admin":
+		user.ID = 6
+		user.Admin = true
+	}
+
+	return user, nil
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantDeadline bool
+	}{
+		{name: "timeout", timeout: time.Minute, wantDeadline: true},
+		{name: "no timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctx context.Context
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx = r.Context()
+			})
+
+			TimeoutMiddleware(next, tt.timeout).ServeHTTP(httptest.NewRecorder(), newRequest(http.MethodGet, "/v1/shoppingcart/1", nil))
+
+			deadline, ok := ctx.Deadline()
+			if ok != tt.wantDeadline {
+				t.Fatalf("Expected deadline to be set %t, but got %t", tt.wantDeadline, ok)
+			}
+			if ok && time.Until(deadline) > tt.timeout {
+				t.Fatalf("Expected deadline within %s, but got %s", tt.timeout, deadline)
+			}
+		})
+	}
+
+	// The context of the request ends once the timeout expires
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		(&Handler{}).Error(w, r, r.Context().Err())
+	})
+	w := httptest.NewRecorder()
+	TimeoutMiddleware(next, time.Millisecond).ServeHTTP(w, newRequest(http.MethodGet, "/v1/shoppingcart/1", nil))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("Expected HTTP status code %d, but got %d", http.StatusGatewayTimeout, w.Code)
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bugimetal/shoppingcart/health"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// livez reports that the process is alive. It doesn't check any dependency,
+// so a database outage doesn't restart the service.
+func livez(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(health.Report{Status: health.StatusUp}); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with liveness %s", err)
+	}
+}
+
+// readyz reports whether the service is ready to serve requests, with the
+// result of every check. It fails with 503 Service Unavailable if a dependency
+// is down or the service is shutting down.
+func (handler *Handler) readyz(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	report := health.Report{Status: health.StatusUp}
+	if handler.health != nil {
+		report = handler.health.Check(r.Context())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Up() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with readiness %s", err)
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart/health"
+)
+
+func TestHandler_readyz(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		checks         map[string]health.Check
+		shutDown       bool
+		wantStatusCode int
+		wantStatus     string
+	}{
+		{
+			name:           "ready",
+			checks:         map[string]health.Check{"database": up, "auth": up},
+			wantStatusCode: http.StatusOK,
+			wantStatus:     health.StatusUp,
+		},
+		{
+			name:           "database down",
+			checks:         map[string]health.Check{"database": down, "auth": up},
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantStatus:     health.StatusDown,
+		},
+		{
+			name:           "shutting down",
+			checks:         map[string]health.Check{"database": up},
+			shutDown:       true,
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantStatus:     health.StatusShuttingDown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.New(0)
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+			if tt.shutDown {
+				checker.ShutDown()
+			}
+			handler := New(Services{Health: checker})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+
+			var report health.Report
+			if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+				t.Fatalf("Unable to decode report: %s", err)
+			}
+			if report.Status != tt.wantStatus {
+				t.Fatalf("Expected status %q, but got %q", tt.wantStatus, report.Status)
+			}
+			if !tt.shutDown && len(report.Checks) != len(tt.checks) {
+				t.Fatalf("Expected %d checks, but got %d", len(tt.checks), len(report.Checks))
+			}
+		})
+	}
+
+	// Liveness doesn't depend on the checks
+	checker := health.New(0)
+	checker.Register("database", down)
+	w := httptest.NewRecorder()
+	New(Services{Health: checker}).ServeHTTP(w, newRequest(http.MethodGet, "/livez", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d, but got %d", http.StatusOK, w.Code)
+	}
+}
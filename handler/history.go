@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// DefaultHistoryLimit and MaxHistoryLimit bound the page size of the history
+	DefaultHistoryLimit = 50
+	MaxHistoryLimit     = 100
+)
+
+// history lists the audit trail of a shopping cart
+// swagger:response History
+type history struct {
+	// in: body
+	Body []shoppingcart.HistoryEntry
+}
+
+// swagger:operation GET /v1/shoppingcart/{id}/history ShoppingCart getHistory
+// ---
+// summary: Retrieves the audit trail of the shopping cart, newest entries first
+// description: Only the owner of the shopping cart and admins may read the history.
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: limit
+//   in: query
+//   description: number of entries to return, at most 100
+//   type: integer
+//   default: 50
+// - name: offset
+//   in: query
+//   description: number of entries to skip
+//   type: integer
+//   default: 0
+// responses:
+//   "200":
+//     "$ref": "#/responses/History"
+//   "400":
+//     "$ref": "#/responses/problem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "403":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) getHistory(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	limit, err := intQuery(r, "limit", DefaultHistoryLimit, 1, MaxHistoryLimit)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	offset, err := intQuery(r, "offset", 0, 0, -1)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	var entries []shoppingcart.HistoryEntry
+	if user.Admin {
+		entries, err = handler.shoppingCartService.AdminHistory(r.Context(), ID, limit, offset)
+	} else {
+		entries, err = handler.shoppingCartService.History(r.Context(), ID, user.ID, limit, offset)
+	}
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with history %s", err)
+	}
+}
+
+// intQuery parses the named query parameter as int, falling back to def if
+// it is not set. Values below min or above max are rejected, a negative max
+// means there is no upper bound.
+func intQuery(r *http.Request, name string, def, min, max int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, shoppingcart.ValidationErrors{{Field: name, Err: ErrInvalidParameter}}
+	}
+
+	if value < min || (max >= 0 && value > max) {
+		return 0, shoppingcart.ValidationErrors{{Field: name, Err: ErrOutOfRange}}
+	}
+
+	return value, nil
+}
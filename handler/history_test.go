@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestHandler_getHistory(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+		HistoryStorage:      storageMock,
+	})
+
+	handler := &Handler{
+		shoppingCartService: services.ShoppingCart,
+		authService:         testAuth{},
+	}
+
+	tests := []struct {
+		name           string
+		user           string
+		shoppingCartID string
+		query          string
+		wantStatusCode int
+		wantEntries    int
+	}{
+		{
+			name:           "owner",
+			user:           "test",
+			wantStatusCode: http.StatusOK,
+			wantEntries:    3,
+		},
+		{
+			name:           "owner with page",
+			user:           "test",
+			query:          "?limit=1&offset=1",
+			wantStatusCode: http.StatusOK,
+			wantEntries:    1,
+		},
+		{
+			name:           "admin",
+			user:           "admin",
+			wantStatusCode: http.StatusOK,
+			wantEntries:    3,
+		},
+		{
+			name:           "admin reading a missing cart",
+			user:           "admin",
+			shoppingCartID: "2",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "viewer",
+			user:           "viewer",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "other user",
+			user:           "hacker",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "limit too large",
+			user:           "test",
+			query:          "?limit=1000",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid offset",
+			user:           "test",
+			query:          "?offset=abc",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shoppingCartID := tt.shoppingCartID
+			if shoppingCartID == "" {
+				shoppingCartID = "1"
+			}
+			creds := base64.StdEncoding.EncodeToString([]byte(tt.user + ":" + tt.user))
+
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodGet, "/v1/shoppingcart/"+shoppingCartID+"/history"+tt.query, nil)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(handler.getHistory)(w, r, httprouter.Params{{Key: "id", Value: shoppingCartID}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var entries []shoppingcart.HistoryEntry
+			if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+				t.Fatalf("Unable to decode history: %s", err)
+			}
+			if len(entries) != tt.wantEntries {
+				t.Fatalf("Expected %d history entries, but got %d", tt.wantEntries, len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/internal/requestid"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// loggingMiddleware propagates the X-Request-ID header of the request, or
+// assigns a new ID if it is missing or invalid, and makes it available through the request context along
+// with a logger of the request. Every request is logged once it is handled,
+// with its status and latency.
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(requestid.Header)
+		if !requestid.Valid(requestID) {
+			requestID = requestid.New()
+		}
+		w.Header().Set(requestid.Header, requestID)
+
+		fields := logrus.Fields{
+			"request_id": requestID,
+			"method":     r.Method,
+			"path":       r.URL.Path,
+		}
+		if span := trace.FromContext(r.Context()); span != nil && span.SpanContext().IsSampled() {
+			fields["trace_id"] = span.SpanContext().TraceID.String()
+		}
+
+		ctx := requestid.NewContext(r.Context(), requestID)
+		ctx = logging.NewContext(ctx, logrus.WithFields(fields))
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		logging.FromContext(ctx).WithFields(logrus.Fields{
+			"status":     recorder.status,
+			"latency_ms": time.Since(start).Milliseconds(),
+		}).Info("Handled request")
+	})
+}
+
+// statusRecorder records the status code of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// routes registers handlers on the router with the route and the shopping
+// cart or wishlist of the path added to the logger of every request.
+type routes struct {
+	*httprouter.Router
+}
+
+func (router routes) GET(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodGet, path, withRoute(path, handle))
+}
+
+func (router routes) POST(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPost, path, withRoute(path, handle))
+}
+
+func (router routes) PATCH(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPatch, path, withRoute(path, handle))
+}
+
+func (router routes) DELETE(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodDelete, path, withRoute(path, handle))
+}
+
+// withRoute adds the route to the logger of the request, along with the ID of
+// the shopping cart or wishlist it works on
+func withRoute(path string, next httprouter.Handle) httprouter.Handle {
+	idField := ""
+	switch {
+	case strings.HasPrefix(path, "/v1/shoppingcart/:id"):
+		idField = "cart_id"
+	case strings.HasPrefix(path, "/v1/wishlist/:id"):
+		idField = "wishlist_id"
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		fields := logrus.Fields{"route": path}
+		if idField != "" {
+			if id, err := strconv.ParseInt(ps.ByName("id"), 10, 64); err == nil {
+				fields[idField] = id
+			}
+		}
+		logging.AddFields(r.Context(), fields)
+
+		next(w, r, ps)
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/requestid"
+	"github.com/bugimetal/shoppingcart/service"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	hook := test.NewGlobal()
+
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+	})
+	handler := New(Services{
+		ShoppingCart: services.ShoppingCart,
+		Wishlist:     services.Wishlist,
+		Auth:         auth.New(),
+	})
+
+	tests := []struct {
+		name           string
+		user           string
+		requestID      string
+		wantNewID      bool
+		wantStatusCode int
+		wantFields     logrus.Fields
+	}{
+		{
+			name:           "request ID propagated",
+			user:           "test",
+			requestID:      "abc",
+			wantStatusCode: http.StatusOK,
+			wantFields: logrus.Fields{
+				"request_id": "abc",
+				"route":      "/v1/shoppingcart/:id",
+				"cart_id":    int64(1),
+				"user_id":    int64(1),
+				"status":     http.StatusOK,
+			},
+		},
+		{
+			name:           "request ID too long replaced",
+			user:           "test",
+			requestID:      strings.Repeat("a", requestid.MaxLength+1),
+			wantNewID:      true,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "request ID with invalid characters replaced",
+			user:           "test",
+			requestID:      "abc\" level=error msg=\"forged",
+			wantNewID:      true,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "request ID assigned",
+			user:           "hacker",
+			wantStatusCode: http.StatusNotFound,
+			wantFields: logrus.Fields{
+				"route":   "/v1/shoppingcart/:id",
+				"cart_id": int64(1),
+				"user_id": int64(2),
+				"status":  http.StatusNotFound,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook.Reset()
+
+			r := newRequest(http.MethodGet, "/v1/shoppingcart/1", nil)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(tt.user+":password"))))
+			if tt.requestID != "" {
+				r.Header.Set(requestid.Header, tt.requestID)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+
+			requestID := w.Header().Get(requestid.Header)
+			if !requestid.Valid(requestID) || (tt.requestID != "" && !tt.wantNewID && requestID != tt.requestID) {
+				t.Fatalf("Expected request ID %q, but got %q", tt.requestID, requestID)
+			}
+			if tt.wantNewID && requestID == tt.requestID {
+				t.Fatalf("Expected request ID %q to be replaced", tt.requestID)
+			}
+
+			entry := hook.LastEntry()
+			if entry == nil || entry.Message != "Handled request" {
+				t.Fatalf("Expected the request to be logged, but got %v", entry)
+			}
+			if entry.Data["request_id"] != requestID {
+				t.Fatalf("Expected request ID %q to be logged, but got %v", requestID, entry.Data["request_id"])
+			}
+			if _, ok := entry.Data["latency_ms"]; !ok {
+				t.Fatal("Expected latency to be logged")
+			}
+			for name, value := range tt.wantFields {
+				if entry.Data[name] != value {
+					t.Fatalf("Expected field %s %v, but got %v", name, value, entry.Data[name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// swagger:operation POST /v1/shoppingcart/{id}/member CartMember inviteMember
+// ---
+// summary: Invites a user to the shopping cart
+// description: Only the owner of the shopping cart may invite. Editors can modify the cart, viewers can only read it. The invited user gets access after accepting the invitation.
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: member
+//   in: body
+//   description: invited user and role
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/inviteMemberRequest"
+// responses:
+//   "201":
+//     "$ref": "#/responses/CartMember"
+//   "400":
+//     "$ref": "#/responses/problem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "403":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "409":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) inviteMember(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req inviteMemberRequest
+	if err := decodeRequest(r, &req); err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	shoppingCartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	member := shoppingcart.CartMember{
+		UserID: req.UserID,
+		Role:   req.Role,
+	}
+
+	if err := handler.shoppingCartService.Invite(r.Context(), shoppingCartID, user.ID, &member); err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to invite user %d to shopping cart %d: %s", req.UserID, shoppingCartID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(member); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart member %s", err)
+	}
+}
+
+// swagger:operation POST /v1/shoppingcart/{id}/member/accept CartMember acceptInvitation
+// ---
+// summary: Accepts the invitation of the authenticated user to the shopping cart
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/CartMember"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) acceptInvitation(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	shoppingCartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	member, err := handler.shoppingCartService.AcceptInvitation(r.Context(), shoppingCartID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to accept invitation of user %d to shopping cart %d: %s", user.ID, shoppingCartID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(member); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart member %s", err)
+	}
+}
+
+// swagger:operation DELETE /v1/shoppingcart/{id}/member/{user_id} CartMember revokeMember
+// ---
+// summary: Removes a member or a pending invitation from the shopping cart
+// description: The owner may remove anyone, members may only remove themselves to leave the cart or decline the invitation.
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: user_id
+//   in: path
+//   description: user id of the member
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "204":
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "403":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) revokeMember(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	shoppingCartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	memberUserID, err := int64Param(ps, "user_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	if err := handler.shoppingCartService.RevokeMember(r.Context(), shoppingCartID, user.ID, memberUserID); err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to remove member %d from shopping cart %d: %s", memberUserID, shoppingCartID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
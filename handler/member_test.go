@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func newMemberHandler() *Handler {
+	storageMock := &shoppingcart_mock.MockStorage{}
+
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+		CartMemberStorage:   storageMock,
+		WishlistStorage:     storageMock,
+	})
+
+	return &Handler{
+		shoppingCartService: services.ShoppingCart,
+		wishlistService:     services.Wishlist,
+		authService:         testAuth{},
+	}
+}
+
+func TestHandler_inviteMember(t *testing.T) {
+	handler := newMemberHandler()
+
+	tests := []struct {
+		name           string
+		user           string
+		body           interface{}
+		wantStatusCode int
+	}{
+		{
+			name:           "owner invites editor",
+			user:           "test",
+			body:           inviteMemberRequest{UserID: 6, Role: shoppingcart.RoleEditor},
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "owner invites existing member",
+			user:           "test",
+			body:           inviteMemberRequest{UserID: 4, Role: shoppingcart.RoleEditor},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "owner invites owner",
+			user:           "test",
+			body:           inviteMemberRequest{UserID: 6, Role: shoppingcart.RoleOwner},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "viewer invites",
+			user:           "viewer",
+			body:           inviteMemberRequest{UserID: 6, Role: shoppingcart.RoleViewer},
+			wantStatusCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := base64.StdEncoding.EncodeToString([]byte(tt.user + ":" + tt.user))
+
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodPost, "/v1/shoppingcart/1/member", tt.body)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(handler.inviteMember)(w, r, httprouter.Params{{Key: "id", Value: "1"}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_viewerAddProduct(t *testing.T) {
+	handler := newMemberHandler()
+	creds := base64.StdEncoding.EncodeToString([]byte(`viewer:viewer`))
+
+	w := httptest.NewRecorder()
+	r := newRequest(http.MethodPost, "/v1/shoppingcart/1/item", addProductRequest{ProductID: 9, Quantity: 1})
+	r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+	handler.authMiddleware(handler.addProduct)(w, r, httprouter.Params{{Key: "id", Value: "1"}})
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected HTTP status code %d, but got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestHandler_acceptInvitation(t *testing.T) {
+	handler := newMemberHandler()
+
+	tests := []struct {
+		name           string
+		user           string
+		wantStatusCode int
+	}{
+		{
+			name:           "invited user accepts",
+			user:           "invitee",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "user without invitation accepts",
+			user:           "hacker",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := base64.StdEncoding.EncodeToString([]byte(tt.user + ":" + tt.user))
+
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodPost, "/v1/shoppingcart/1/member/accept", nil)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(handler.acceptInvitation)(w, r, httprouter.Params{{Key: "id", Value: "1"}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_revokeMember(t *testing.T) {
+	handler := newMemberHandler()
+
+	tests := []struct {
+		name           string
+		user           string
+		memberUserID   string
+		wantStatusCode int
+	}{
+		{
+			name:           "owner revokes viewer",
+			user:           "test",
+			memberUserID:   "4",
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "viewer leaves",
+			user:           "viewer",
+			memberUserID:   "4",
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "viewer revokes editor",
+			user:           "viewer",
+			memberUserID:   "3",
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "invalid user id",
+			user:           "test",
+			memberUserID:   "abc",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := base64.StdEncoding.EncodeToString([]byte(tt.user + ":" + tt.user))
+
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodDelete, "/v1/shoppingcart/1/member/"+tt.memberUserID, nil)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(handler.revokeMember)(w, r, httprouter.Params{{Key: "id", Value: "1"}, {Key: "user_id", Value: tt.memberUserID}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
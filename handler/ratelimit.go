@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/ratelimit"
+)
+
+// RateLimiter provides an interface to the limiter of requests per client.
+type RateLimiter interface {
+	Allow(ctx context.Context, client, kind string) (ratelimit.Result, error)
+}
+
+// rateLimit takes a token from the bucket of the client for the request and
+// reports the state of the bucket in the RateLimit-* headers. It fails with
+// 429 Too Many Requests once the bucket is empty, and returns whether the
+// request may be handled. Requests are let through if the limiter fails.
+func (handler *Handler) rateLimit(w http.ResponseWriter, r *http.Request, client string) bool {
+	if handler.rateLimiter == nil {
+		return true
+	}
+
+	kind := ratelimit.Write
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		kind = ratelimit.Read
+	}
+
+	result, err := handler.rateLimiter.Allow(r.Context(), client, kind)
+	if err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to check the rate limit of %s: %s", client, err)
+		return true
+	}
+
+	if result.Limit > 0 {
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", ceilSeconds(result.Reset))
+	}
+
+	if !result.Allowed {
+		w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+		handler.Error(w, r, shoppingcart.ErrRateLimitExceeded)
+		return false
+	}
+
+	return true
+}
+
+// ipClient returns the rate limited client of the remote address of the
+// request. Forwarding headers are not trusted, since any client can set them.
+func ipClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// ceilSeconds formats a duration in whole seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/ratelimit"
+	"github.com/bugimetal/shoppingcart/service"
+)
+
+func TestRateLimit(t *testing.T) {
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+	})
+	handler := New(Services{
+		ShoppingCart: services.ShoppingCart,
+		Wishlist:     services.Wishlist,
+		Auth:         auth.New(),
+		RateLimiter: ratelimit.New(ratelimit.NewMemory(), ratelimit.Config{
+			Read: ratelimit.Limit{Rate: 0.5, Burst: 1},
+		}),
+	})
+
+	// Requests are sent in order and share the buckets of their clients
+	tests := []struct {
+		name           string
+		method         string
+		user           string
+		wantStatusCode int
+		wantRemaining  string
+		wantRetryAfter string
+	}{
+		{
+			name:           "first read",
+			method:         http.MethodGet,
+			user:           "test",
+			wantStatusCode: http.StatusOK,
+			wantRemaining:  "0",
+		},
+		{
+			name:           "read over the limit",
+			method:         http.MethodGet,
+			user:           "test",
+			wantStatusCode: http.StatusTooManyRequests,
+			wantRemaining:  "0",
+			wantRetryAfter: "2",
+		},
+		{
+			name:           "read of another user",
+			method:         http.MethodGet,
+			user:           "hacker",
+			wantStatusCode: http.StatusNotFound,
+			wantRemaining:  "0",
+		},
+		{
+			name:           "writes not limited",
+			method:         http.MethodDelete,
+			user:           "test",
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "first unauthenticated read",
+			method:         http.MethodGet,
+			wantStatusCode: http.StatusUnauthorized,
+			wantRemaining:  "0",
+		},
+		{
+			name:           "unauthenticated read over the limit",
+			method:         http.MethodGet,
+			wantStatusCode: http.StatusTooManyRequests,
+			wantRemaining:  "0",
+			wantRetryAfter: "2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/v1/shoppingcart/1"
+			if tt.method == http.MethodDelete {
+				path += "/item"
+			}
+
+			r := newRequest(tt.method, path, nil)
+			if tt.user != "" {
+				r.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(tt.user+":password"))))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+
+			if remaining := w.Header().Get("RateLimit-Remaining"); remaining != tt.wantRemaining {
+				t.Fatalf("Expected RateLimit-Remaining %q, but got %q", tt.wantRemaining, remaining)
+			}
+
+			if retryAfter := w.Header().Get("Retry-After"); retryAfter != tt.wantRetryAfter {
+				t.Fatalf("Expected Retry-After %q, but got %q", tt.wantRetryAfter, retryAfter)
+			}
+
+			if tt.wantStatusCode == http.StatusTooManyRequests {
+				var problem problemResource
+				if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+					t.Fatalf("Unable to decode response: %v", err)
+				}
+				if problem.Code != "rate_limited" {
+					t.Fatalf("Expected code %q, but got %q", "rate_limited", problem.Code)
+				}
+			}
+		})
+	}
+}
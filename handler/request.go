@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// These errors are returned for request payloads which can't be accepted
+var (
+	ErrBodyTooLarge = errors.New("request body is too large")
+	ErrUnknownField = errors.New("field is not allowed")
+	ErrInvalidValue = errors.New("value has invalid type or format")
+	ErrOutOfRange   = errors.New("value is out of range")
+)
+
+const (
+	// maxRequestBodySize limits the size of request payloads in bytes
+	maxRequestBodySize = 64 << 10
+
+	// maxQuantity is the largest quantity accepted in a single request
+	maxQuantity = 10000
+
+	// maxVariantIDLength limits the length of variant IDs
+	maxVariantIDLength = 64
+
+	// maxAttributes, maxAttributeKeyLength and maxAttributeValueLength limit
+	// the custom attributes of a line
+	maxAttributes           = 10
+	maxAttributeKeyLength   = 64
+	maxAttributeValueLength = 255
+
+	// maxWishlistNameLength limits the length of wishlist names
+	maxWishlistNameLength = 100
+)
+
+// addProductRequest describes the payload of addProduct
+// swagger:model addProductRequest
+type addProductRequest struct {
+	ProductID  int64             `json:"product_id"`
+	VariantID  string            `json:"variant_id"`
+	Attributes map[string]string `json:"attributes"`
+	Quantity   uint64            `json:"quantity"`
+}
+
+// validate checks the request for values the service can't accept
+func (req *addProductRequest) validate() error {
+	var errs shoppingcart.ValidationErrors
+
+	switch {
+	case req.ProductID == 0:
+		errs = append(errs, shoppingcart.FieldError{Field: "product_id", Err: shoppingcart.ErrCartItemNoProductSet})
+	case req.ProductID < 0:
+		errs = append(errs, shoppingcart.FieldError{Field: "product_id", Err: ErrOutOfRange})
+	}
+
+	if len(req.VariantID) > maxVariantIDLength {
+		errs = append(errs, shoppingcart.FieldError{Field: "variant_id", Err: ErrOutOfRange})
+	}
+
+	if !validAttributes(req.Attributes) {
+		errs = append(errs, shoppingcart.FieldError{Field: "attributes", Err: ErrOutOfRange})
+	}
+
+	switch {
+	case req.Quantity == 0:
+		errs = append(errs, shoppingcart.FieldError{Field: "quantity", Err: shoppingcart.ErrCartItemNoQuantitySet})
+	case req.Quantity > maxQuantity:
+		errs = append(errs, shoppingcart.FieldError{Field: "quantity", Err: ErrOutOfRange})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// cartItem converts the request to a shopping cart item of the given cart
+func (req *addProductRequest) cartItem(shoppingCartID int64) shoppingcart.ShoppingCartItem {
+	return shoppingcart.ShoppingCartItem{
+		ShoppingCartID: shoppingCartID,
+		ProductID:      req.ProductID,
+		VariantID:      req.VariantID,
+		Attributes:     req.Attributes,
+		Quantity:       req.Quantity,
+	}
+}
+
+// wishlistItem converts the request to an item of the given wishlist
+func (req *addProductRequest) wishlistItem(wishlistID int64) shoppingcart.WishlistItem {
+	return shoppingcart.WishlistItem{
+		WishlistID: wishlistID,
+		ProductID:  req.ProductID,
+		VariantID:  req.VariantID,
+		Attributes: req.Attributes,
+		Quantity:   req.Quantity,
+	}
+}
+
+// validAttributes checks the number of attributes and their size
+func validAttributes(attributes map[string]string) bool {
+	if len(attributes) > maxAttributes {
+		return false
+	}
+
+	for key, value := range attributes {
+		if key == "" || len(key) > maxAttributeKeyLength || len(value) > maxAttributeValueLength {
+			return false
+		}
+	}
+
+	return true
+}
+
+// wishlistRequest describes the payload of createWishlist and renameWishlist
+// swagger:model wishlistRequest
+type wishlistRequest struct {
+	Name string `json:"name"`
+}
+
+// validate checks the request for values the service can't accept
+func (req *wishlistRequest) validate() error {
+	switch {
+	case strings.TrimSpace(req.Name) == "":
+		return shoppingcart.ValidationErrors{{Field: "name", Err: shoppingcart.ErrWishlistNameNotSet}}
+	case len(req.Name) > maxWishlistNameLength:
+		return shoppingcart.ValidationErrors{{Field: "name", Err: ErrOutOfRange}}
+	}
+
+	return nil
+}
+
+// moveToCartRequest describes the payload of moveWishlistItemToCart
+// swagger:model moveToCartRequest
+type moveToCartRequest struct {
+	ShoppingCartID int64 `json:"shoppingcart_id"`
+}
+
+// validate checks the request for values the service can't accept
+func (req *moveToCartRequest) validate() error {
+	if req.ShoppingCartID <= 0 {
+		return shoppingcart.ValidationErrors{{Field: "shoppingcart_id", Err: ErrOutOfRange}}
+	}
+
+	return nil
+}
+
+// inviteMemberRequest describes the payload of inviteMember
+// swagger:model inviteMemberRequest
+type inviteMemberRequest struct {
+	UserID int64  `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// validate checks the request for values the service can't accept
+func (req *inviteMemberRequest) validate() error {
+	var errs shoppingcart.ValidationErrors
+
+	switch {
+	case req.UserID == 0:
+		errs = append(errs, shoppingcart.FieldError{Field: "user_id", Err: shoppingcart.ErrUserNotSet})
+	case req.UserID < 0:
+		errs = append(errs, shoppingcart.FieldError{Field: "user_id", Err: ErrOutOfRange})
+	}
+
+	if req.Role != shoppingcart.RoleEditor && req.Role != shoppingcart.RoleViewer {
+		errs = append(errs, shoppingcart.FieldError{Field: "role", Err: shoppingcart.ErrInvalidRole})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// request is implemented by request payloads
+type request interface {
+	validate() error
+}
+
+// decodeRequest decodes the JSON object in the request body into the struct
+// pointed to by req and validates it. Unknown fields, values which don't fit
+// the field type and values rejected by validate are reported together as
+// shoppingcart.ValidationErrors.
+func decodeRequest(r *http.Request, req request) error {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+	if err != nil {
+		return ErrMalformedBody
+	}
+
+	if len(body) > maxRequestBodySize {
+		return ErrBodyTooLarge
+	}
+
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil || payload == nil {
+		return ErrMalformedBody
+	}
+
+	fields := jsonFields(req)
+
+	var errs shoppingcart.ValidationErrors
+	invalid := make(map[string]bool)
+	for name, value := range payload {
+		field, ok := fields[name]
+		if !ok {
+			errs = append(errs, shoppingcart.FieldError{Field: name, Err: ErrUnknownField})
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(field.Addr().Interface()); err != nil {
+			errs = append(errs, shoppingcart.FieldError{Field: name, Err: ErrInvalidValue})
+			invalid[name] = true
+		}
+	}
+
+	// Fields which could not be decoded are already reported, their zero
+	// values must not be reported again.
+	var validationErrs shoppingcart.ValidationErrors
+	if err := req.validate(); errors.As(err, &validationErrs) {
+		for _, fieldErr := range validationErrs {
+			if !invalid[fieldErr.Field] {
+				errs = append(errs, fieldErr)
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+		return errs
+	}
+
+	return nil
+}
+
+// jsonFields maps the JSON names of the fields of the struct pointed to by v to the fields
+func jsonFields(v interface{}) map[string]reflect.Value {
+	value := reflect.ValueOf(v).Elem()
+	fields := make(map[string]reflect.Value, value.NumField())
+
+	for i := 0; i < value.NumField(); i++ {
+		name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields[name] = value.Field(i)
+	}
+
+	return fields
+}
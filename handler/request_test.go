@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+func TestDecodeRequest_addProduct(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		want       addProductRequest
+		wantErr    error
+		wantFields map[string]error
+	}{
+		{
+			name: "valid payload",
+			body: `{"product_id": 5, "quantity": 2}`,
+			want: addProductRequest{ProductID: 5, Quantity: 2},
+		},
+		{
+			name: "variant and attributes",
+			body: `{"product_id": 5, "variant_id": "red-xl", "attributes": {"engraving": "For Anna"}, "quantity": 1}`,
+			want: addProductRequest{ProductID: 5, VariantID: "red-xl", Attributes: map[string]string{"engraving": "For Anna"}, Quantity: 1},
+		},
+		{
+			name: "oversized variant and attributes",
+			body: `{"product_id": 5, "variant_id": "` + strings.Repeat("x", maxVariantIDLength+1) + `", "attributes": {"gift_message": "` + strings.Repeat("x", maxAttributeValueLength+1) + `"}, "quantity": 1}`,
+			wantFields: map[string]error{
+				"variant_id": ErrOutOfRange,
+				"attributes": ErrOutOfRange,
+			},
+		},
+		{
+			name: "attributes must be strings",
+			body: `{"product_id": 5, "attributes": {"count": 2}, "quantity": 1}`,
+			wantFields: map[string]error{
+				"attributes": ErrInvalidValue,
+			},
+		},
+		{
+			name:    "not an object",
+			body:    `[{"product_id": 5}]`,
+			wantErr: ErrMalformedBody,
+		},
+		{
+			name:    "body too large",
+			body:    `{"product_id": 5, "quantity": 2` + strings.Repeat(" ", maxRequestBodySize) + `}`,
+			wantErr: ErrBodyTooLarge,
+		},
+		{
+			name: "client supplied fields",
+			body: `{"id": 10, "created_at": "2020-04-18T13:36:00Z", "product_id": 5, "quantity": 2}`,
+			wantFields: map[string]error{
+				"id":         ErrUnknownField,
+				"created_at": ErrUnknownField,
+			},
+		},
+		{
+			name: "negative and overflowing numbers",
+			body: `{"product_id": 99999999999999999999, "quantity": -1}`,
+			wantFields: map[string]error{
+				"product_id": ErrInvalidValue,
+				"quantity":   ErrInvalidValue,
+			},
+		},
+		{
+			name: "out of range values",
+			body: `{"product_id": -5, "quantity": 10001}`,
+			wantFields: map[string]error{
+				"product_id": ErrOutOfRange,
+				"quantity":   ErrOutOfRange,
+			},
+		},
+		{
+			name: "every invalid field is reported",
+			body: `{"product_id": "5", "shoppingcart_id": 1}`,
+			wantFields: map[string]error{
+				"product_id":      ErrInvalidValue,
+				"quantity":        shoppingcart.ErrCartItemNoQuantitySet,
+				"shoppingcart_id": ErrUnknownField,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/shoppingcart/1/item", strings.NewReader(tt.body))
+
+			var req addProductRequest
+			err := decodeRequest(r, &req)
+
+			if tt.wantErr != nil {
+				if err != tt.wantErr {
+					t.Fatalf("decodeRequest() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("decodeRequest() unexpected error = %v", err)
+				}
+				if !reflect.DeepEqual(req, tt.want) {
+					t.Fatalf("decodeRequest() got = %v, want %v", req, tt.want)
+				}
+				return
+			}
+
+			var errs shoppingcart.ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("decodeRequest() error = %v, want validation errors", err)
+			}
+
+			got := make(map[string]error)
+			for _, fieldErr := range errs {
+				got[fieldErr.Field] = fieldErr.Err
+			}
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Fatalf("decodeRequest() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"strconv"
 
 	"github.com/bugimetal/shoppingcart"
-	"github.com/sirupsen/logrus"
+	"github.com/bugimetal/shoppingcart/internal/logging"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -19,9 +19,9 @@ import (
 //   "201":
 //     "$ref": "#/responses/ShoppingCart"
 //   "401":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "500":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 func (handler *Handler) createShoppingCart(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	user, err := handler.authUser(r)
 	if err != nil {
@@ -35,13 +35,13 @@ func (handler *Handler) createShoppingCart(w http.ResponseWriter, r *http.Reques
 
 	if err := handler.shoppingCartService.Create(r.Context(), &cart); err != nil {
 		handler.Error(w, r, err)
-		logrus.Errorf("Unable to create shopping cart for user %d: %s", user.ID, err)
+		logging.FromContext(r.Context()).Errorf("Unable to create shopping cart for user %d: %s", user.ID, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(cart); err != nil {
-		logrus.Errorf("Unable to respond with cart %s", err)
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart %s", err)
 	}
 }
 
@@ -60,13 +60,13 @@ func (handler *Handler) createShoppingCart(w http.ResponseWriter, r *http.Reques
 //   "200":
 //     "$ref": "#/responses/ShoppingCart"
 //   "401":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "404":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "500":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 func (handler *Handler) getShoppingCart(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	ID, err := strconv.ParseInt(ps.ByName("id"), 10, 64)
+	ID, err := int64Param(ps, "id")
 	if err != nil {
 		handler.Error(w, r, err)
 		return
@@ -86,7 +86,7 @@ func (handler *Handler) getShoppingCart(w http.ResponseWriter, r *http.Request,
 
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(cart); err != nil {
-		logrus.Errorf("Unable to respond with cart %s", err)
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart %s", err)
 	}
 }
 
@@ -104,13 +104,13 @@ func (handler *Handler) getShoppingCart(w http.ResponseWriter, r *http.Request,
 // responses:
 //   "204":
 //   "401":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "404":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "500":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 func (handler *Handler) emptyCart(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	ID, err := strconv.ParseInt(ps.ByName("id"), 10, 64)
+	ID, err := int64Param(ps, "id")
 	if err != nil {
 		handler.Error(w, r, err)
 		return
@@ -124,13 +124,64 @@ func (handler *Handler) emptyCart(w http.ResponseWriter, r *http.Request, ps htt
 
 	if err := handler.shoppingCartService.Empty(r.Context(), ID, user.ID); err != nil {
 		handler.Error(w, r, err)
-		logrus.Errorf("Unable to add empty shopping cart %d: %s", ID, err)
+		logging.FromContext(r.Context()).Errorf("Unable to add empty shopping cart %d: %s", ID, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// swagger:operation POST /v1/shoppingcart/{id}/checkout ShoppingCart checkout
+// ---
+// summary: Checks out the shopping cart
+// description: Reserved stock of the cart items is converted into sold stock and the cart is emptied.
+//   If shopping cart has no items, error will be returned
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/ShoppingCart"
+//   "400":
+//     "$ref": "#/responses/problem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "409":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) checkout(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	cart, err := handler.shoppingCartService.Checkout(r.Context(), ID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to check out shopping cart %d: %s", ID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(cart); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart %s", err)
+	}
+}
+
 // swagger:operation POST /v1/shoppingcart/{id}/item ShoppingCartItem addProduct
 // ---
 // summary: add product to existing shopping cart
@@ -147,24 +198,32 @@ func (handler *Handler) emptyCart(w http.ResponseWriter, r *http.Request, ps htt
 //   description: shopping cart item
 //   required: true
 //   schema:
-//     "$ref": "#/definitions/ShoppingCartItem"
+//     "$ref": "#/definitions/addProductRequest"
 // responses:
 //   "201":
 //     "$ref": "#/responses/ShoppingCartItem"
+//   "400":
+//     "$ref": "#/responses/problem"
 //   "401":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "404":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
+//   "409":
+//     "$ref": "#/responses/problem"
+//   "413":
+//     "$ref": "#/responses/problem"
+//   "422":
+//     "$ref": "#/responses/problem"
 //   "500":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 func (handler *Handler) addProduct(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	var cartItem shoppingcart.ShoppingCartItem
-	if err := json.NewDecoder(r.Body).Decode(&cartItem); err != nil {
+	var req addProductRequest
+	if err := decodeRequest(r, &req); err != nil {
 		handler.Error(w, r, err)
 		return
 	}
 
-	cartID, err := strconv.ParseInt(ps.ByName("id"), 10, 64)
+	cartID, err := int64Param(ps, "id")
 	if err != nil {
 		handler.Error(w, r, err)
 		return
@@ -176,24 +235,24 @@ func (handler *Handler) addProduct(w http.ResponseWriter, r *http.Request, ps ht
 		return
 	}
 
-	cartItem.ShoppingCartID = cartID
+	cartItem := req.cartItem(cartID)
 
 	if err := handler.shoppingCartService.AddProduct(r.Context(), &cartItem, user.ID); err != nil {
 		handler.Error(w, r, err)
-		logrus.Errorf("Unable to add shopping cart item %v: %s", cartItem, err)
+		logging.FromContext(r.Context()).Errorf("Unable to add shopping cart item %v: %s", cartItem, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(cartItem); err != nil {
-		logrus.Errorf("Unable to respond with cart item %s", err)
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart item %s", err)
 	}
 }
 
 // swagger:operation DELETE /v1/shoppingcart/{id}/item/{product_id} ShoppingCartItem removeProduct
 // ---
 // summary: removes product from existing shopping cart
-// description:
+// description: All lines of the product are removed, regardless of variant and attributes
 // parameters:
 // - name: id
 //   in: path
@@ -210,19 +269,19 @@ func (handler *Handler) addProduct(w http.ResponseWriter, r *http.Request, ps ht
 // responses:
 //   "204":
 //   "401":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "404":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 //   "500":
-//     "$ref": "#/responses/errorResponse"
+//     "$ref": "#/responses/problem"
 func (handler *Handler) removeProduct(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	cartID, err := strconv.ParseInt(ps.ByName("id"), 10, 64)
+	cartID, err := int64Param(ps, "id")
 	if err != nil {
 		handler.Error(w, r, err)
 		return
 	}
 
-	productID, err := strconv.ParseInt(ps.ByName("product_id"), 10, 64)
+	productID, err := int64Param(ps, "product_id")
 	if err != nil {
 		handler.Error(w, r, err)
 		return
@@ -236,9 +295,194 @@ func (handler *Handler) removeProduct(w http.ResponseWriter, r *http.Request, ps
 
 	if err := handler.shoppingCartService.RemoveProduct(r.Context(), cartID, productID, user.ID); err != nil {
 		handler.Error(w, r, err)
-		logrus.Errorf("Unable to remove shopping cart item (%d:%d): %s", cartID, productID, err)
+		logging.FromContext(r.Context()).Errorf("Unable to remove shopping cart item (%d:%d): %s", cartID, productID, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// swagger:operation DELETE /v1/shoppingcart/{id}/line/{item_id} ShoppingCartItem removeItem
+// ---
+// summary: removes a single line from existing shopping cart
+// description: Unlike removeProduct, other variants of the product stay in the cart
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: item_id
+//   in: path
+//   description: shopping cart item id to delete
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "204":
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) removeItem(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	cartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	itemID, err := int64Param(ps, "item_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	if err := handler.shoppingCartService.RemoveItem(r.Context(), cartID, itemID, user.ID); err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to remove shopping cart line (%d:%d): %s", cartID, itemID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// swagger:operation POST /v1/shoppingcart/{id}/line/{item_id}/save-for-later ShoppingCartItem saveForLater
+// ---
+// summary: moves a line of the shopping cart to the items saved for later
+// description: Quantity and attributes are kept. If the same line is already saved, quantities are merged.
+//   Saved items are not part of limits and checkout, their stock is not reserved.
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: item_id
+//   in: path
+//   description: shopping cart item id to save
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/ShoppingCartItem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) saveForLater(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	cartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	itemID, err := int64Param(ps, "item_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	cartItem, err := handler.shoppingCartService.SaveForLater(r.Context(), cartID, itemID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to save shopping cart line (%d:%d) for later: %s", cartID, itemID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(cartItem); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart item %s", err)
+	}
+}
+
+// swagger:operation POST /v1/shoppingcart/{id}/line/{item_id}/move-to-cart ShoppingCartItem moveToCart
+// ---
+// summary: moves a line saved for later back to the shopping cart
+// description: Quantity and attributes are kept. If the same line is already in the cart, quantities are merged.
+//   Limits and stock are checked as when adding a product.
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: item_id
+//   in: path
+//   description: saved shopping cart item id to move
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/ShoppingCartItem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "409":
+//     "$ref": "#/responses/problem"
+//   "422":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) moveToCart(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	cartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	itemID, err := int64Param(ps, "item_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	cartItem, err := handler.shoppingCartService.MoveToCart(r.Context(), cartID, itemID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to move shopping cart line (%d:%d) to cart: %s", cartID, itemID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(cartItem); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart item %s", err)
+	}
+}
+
+// int64Param parses the named path parameter as int64
+func int64Param(ps httprouter.Params, name string) (int64, error) {
+	value, err := strconv.ParseInt(ps.ByName(name), 10, 64)
+	if err != nil {
+		return 0, shoppingcart.ValidationErrors{{Field: name, Err: ErrInvalidParameter}}
+	}
+
+	return value, nil
+}
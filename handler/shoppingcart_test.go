@@ -102,8 +102,8 @@ func TestHandler_addProduct(t *testing.T) {
 		authService:         auth.New(),
 	}
 
-	newProductCreate := shoppingcart.ShoppingCartItem{ProductID: 5, Quantity: 1}
-	existingProductUpdate := shoppingcart.ShoppingCartItem{ProductID: 2, Quantity: 1}
+	newProductCreate := addProductRequest{ProductID: 5, Quantity: 1}
+	existingProductUpdate := addProductRequest{ProductID: 2, Quantity: 1}
 
 	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
 
@@ -151,7 +151,7 @@ func TestHandler_addProduct(t *testing.T) {
 			t.Fatalf("Can't decode response: %v", err)
 		}
 
-		existingProduct, err := existingCart.GetProduct(existingProductUpdate.ProductID)
+		existingProduct, err := existingCart.GetProduct(shoppingcart.ShoppingCartItem{ProductID: existingProductUpdate.ProductID})
 		if err != nil {
 			t.Fatalf("Product with ID %d not found in %v", existingProductUpdate.ProductID, existingCart.Items)
 		}
@@ -182,3 +182,149 @@ func TestHandler_addProduct(t *testing.T) {
 		}
 	})
 }
+
+func TestHandler_checkout(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+	})
+
+	handler := &Handler{
+		shoppingCartService: services.ShoppingCart,
+		authService:         auth.New(),
+	}
+
+	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
+
+	t.Run("checkout shopping cart successful", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest(http.MethodPost, "/shoppingcart/1/checkout", nil)
+		r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+		handler.authMiddleware(handler.checkout)(w, r, []httprouter.Param{{Key: "id", Value: "1"}})
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected HTTP status code %d, but got %d", http.StatusOK, w.Code)
+		}
+
+		var cart shoppingcart.ShoppingCart
+		if err := json.NewDecoder(w.Body).Decode(&cart); err != nil {
+			t.Fatalf("Can't decode response: %v", err)
+		}
+
+		if len(cart.Items) != 4 {
+			t.Fatalf("Expected %d checked out items, got %d", 4, len(cart.Items))
+		}
+	})
+}
+
+func TestHandler_removeItem(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+	})
+
+	handler := &Handler{
+		shoppingCartService: services.ShoppingCart,
+		authService:         auth.New(),
+	}
+
+	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
+
+	tests := []struct {
+		name           string
+		itemID         string
+		wantStatusCode int
+	}{
+		{
+			name:           "remove variant line",
+			itemID:         "3",
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "remove unknown line",
+			itemID:         "99",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "invalid line id",
+			itemID:         "abc",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodDelete, "/shoppingcart/1/line/"+tt.itemID, nil)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(handler.removeItem)(w, r, []httprouter.Param{{Key: "id", Value: "1"}, {Key: "item_id", Value: tt.itemID}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_savedItems(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+	})
+
+	handler := &Handler{
+		shoppingCartService: services.ShoppingCart,
+		authService:         auth.New(),
+	}
+
+	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
+
+	tests := []struct {
+		name           string
+		handle         httprouter.Handle
+		itemID         string
+		wantStatusCode int
+	}{
+		{
+			name:           "save line for later",
+			handle:         handler.saveForLater,
+			itemID:         "1",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "save line which is already saved",
+			handle:         handler.saveForLater,
+			itemID:         "5",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "move saved line to cart",
+			handle:         handler.moveToCart,
+			itemID:         "5",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "move active line to cart",
+			handle:         handler.moveToCart,
+			itemID:         "1",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodPost, "/shoppingcart/1/line/"+tt.itemID, nil)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(tt.handle)(w, r, []httprouter.Param{{Key: "id", Value: "1"}, {Key: "item_id", Value: tt.itemID}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// snapshots lists the snapshots of a shopping cart
+// swagger:response Snapshots
+type snapshots struct {
+	// in: body
+	Body []shoppingcart.Snapshot
+}
+
+// swagger:operation POST /v1/shoppingcart/{id}/snapshot Snapshot createSnapshot
+// ---
+// summary: Takes a snapshot of the items of the shopping cart
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "201":
+//     "$ref": "#/responses/Snapshot"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "403":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) createSnapshot(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	shoppingCartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	snapshot, err := handler.shoppingCartService.Snapshot(r.Context(), shoppingCartID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to take snapshot of shopping cart %d: %s", shoppingCartID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with snapshot %s", err)
+	}
+}
+
+// swagger:operation GET /v1/shoppingcart/{id}/snapshot Snapshot listSnapshots
+// ---
+// summary: Lists the snapshots of the shopping cart, newest first, without items
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/Snapshots"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) listSnapshots(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	shoppingCartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	snapshots, err := handler.shoppingCartService.ListSnapshots(r.Context(), shoppingCartID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(snapshots); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with snapshots %s", err)
+	}
+}
+
+// swagger:operation GET /v1/shoppingcart/{id}/snapshot/{snapshot_id} Snapshot getSnapshot
+// ---
+// summary: Retrieves a snapshot of the shopping cart along with items
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: snapshot_id
+//   in: path
+//   description: snapshot id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/Snapshot"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) getSnapshot(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	shoppingCartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	snapshotID, err := int64Param(ps, "snapshot_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	snapshot, err := handler.shoppingCartService.GetSnapshot(r.Context(), shoppingCartID, snapshotID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with snapshot %s", err)
+	}
+}
+
+// swagger:operation POST /v1/shoppingcart/{id}/snapshot/{snapshot_id}/restore Snapshot restoreSnapshot
+// ---
+// summary: Replaces the items of the shopping cart with the items of the snapshot
+// description: The current items are kept in a new snapshot first. Items are added as with addProduct, so limits and stock apply.
+// parameters:
+// - name: id
+//   in: path
+//   description: shopping cart id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: snapshot_id
+//   in: path
+//   description: snapshot id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/ShoppingCart"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "403":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "409":
+//     "$ref": "#/responses/problem"
+//   "422":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) restoreSnapshot(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	shoppingCartID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	snapshotID, err := int64Param(ps, "snapshot_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	cart, err := handler.shoppingCartService.Restore(r.Context(), shoppingCartID, snapshotID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to restore shopping cart %d to snapshot %d: %s", shoppingCartID, snapshotID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(cart); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with shopping cart %s", err)
+	}
+}
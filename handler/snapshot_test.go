@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestHandler_snapshots(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+		SnapshotStorage:     storageMock,
+	})
+
+	handler := &Handler{
+		shoppingCartService: services.ShoppingCart,
+		authService:         testAuth{},
+	}
+
+	tests := []struct {
+		name           string
+		user           string
+		method         string
+		handle         httprouter.Handle
+		snapshotID     string
+		wantStatusCode int
+	}{
+		{
+			name:           "create snapshot",
+			user:           "test",
+			method:         http.MethodPost,
+			handle:         handler.createSnapshot,
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "create snapshot as viewer",
+			user:           "viewer",
+			method:         http.MethodPost,
+			handle:         handler.createSnapshot,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "list snapshots as viewer",
+			user:           "viewer",
+			method:         http.MethodGet,
+			handle:         handler.listSnapshots,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "list snapshots of other user",
+			user:           "hacker",
+			method:         http.MethodGet,
+			handle:         handler.listSnapshots,
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "get snapshot",
+			user:           "test",
+			method:         http.MethodGet,
+			handle:         handler.getSnapshot,
+			snapshotID:     "1",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "get unknown snapshot",
+			user:           "test",
+			method:         http.MethodGet,
+			handle:         handler.getSnapshot,
+			snapshotID:     "2",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "restore snapshot",
+			user:           "test",
+			method:         http.MethodPost,
+			handle:         handler.restoreSnapshot,
+			snapshotID:     "1",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "restore snapshot as viewer",
+			user:           "viewer",
+			method:         http.MethodPost,
+			handle:         handler.restoreSnapshot,
+			snapshotID:     "1",
+			wantStatusCode: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := base64.StdEncoding.EncodeToString([]byte(tt.user + ":" + tt.user))
+
+			w := httptest.NewRecorder()
+			r := newRequest(tt.method, "/v1/shoppingcart/1/snapshot", nil)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(tt.handle)(w, r, httprouter.Params{{Key: "id", Value: "1"}, {Key: "snapshot_id", Value: tt.snapshotID}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// wishlists lists the wishlists of the user
+// swagger:response Wishlists
+type wishlists struct {
+	// in: body
+	Body []shoppingcart.Wishlist
+}
+
+// swagger:operation POST /v1/wishlist Wishlist createWishlist
+// ---
+// summary: Creates a named wishlist of the user
+// description:
+// parameters:
+// - name: wishlist
+//   in: body
+//   description: wishlist
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/wishlistRequest"
+// responses:
+//   "201":
+//     "$ref": "#/responses/Wishlist"
+//   "400":
+//     "$ref": "#/responses/problem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) createWishlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req wishlistRequest
+	if err := decodeRequest(r, &req); err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	wishlist := shoppingcart.Wishlist{
+		UserID: user.ID,
+		Name:   req.Name,
+	}
+
+	if err := handler.wishlistService.Create(r.Context(), &wishlist); err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to create wishlist for user %d: %s", user.ID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(wishlist); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with wishlist %s", err)
+	}
+}
+
+// swagger:operation GET /v1/wishlist Wishlist listWishlists
+// ---
+// summary: Lists the wishlists of the user, without items
+// description:
+// responses:
+//   "200":
+//     "$ref": "#/responses/Wishlists"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) listWishlists(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	wishlists, err := handler.wishlistService.List(r.Context(), user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(wishlists); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with wishlists %s", err)
+	}
+}
+
+// swagger:operation GET /v1/wishlist/{id} Wishlist getWishlist
+// ---
+// summary: Retrieves wishlist along with wishlist items
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: wishlist id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "200":
+//     "$ref": "#/responses/Wishlist"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) getWishlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	wishlist, err := handler.wishlistService.Get(r.Context(), ID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(wishlist); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with wishlist %s", err)
+	}
+}
+
+// swagger:operation PATCH /v1/wishlist/{id} Wishlist renameWishlist
+// ---
+// summary: Renames wishlist
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: wishlist id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: wishlist
+//   in: body
+//   description: wishlist
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/wishlistRequest"
+// responses:
+//   "200":
+//     "$ref": "#/responses/Wishlist"
+//   "400":
+//     "$ref": "#/responses/problem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) renameWishlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req wishlistRequest
+	if err := decodeRequest(r, &req); err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	ID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	wishlist, err := handler.wishlistService.Rename(r.Context(), ID, user.ID, req.Name)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to rename wishlist %d: %s", ID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(wishlist); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with wishlist %s", err)
+	}
+}
+
+// swagger:operation DELETE /v1/wishlist/{id} Wishlist deleteWishlist
+// ---
+// summary: Removes wishlist along with wishlist items
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: wishlist id
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "204":
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) deleteWishlist(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	if err := handler.wishlistService.Delete(r.Context(), ID, user.ID); err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to delete wishlist %d: %s", ID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// swagger:operation POST /v1/wishlist/{id}/item WishlistItem addWishlistProduct
+// ---
+// summary: add product to existing wishlist
+// description: If the same product, variant and attributes are already in the wishlist, quantity will be updated
+// parameters:
+// - name: id
+//   in: path
+//   description: wishlist id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: item
+//   in: body
+//   description: wishlist item
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/addProductRequest"
+// responses:
+//   "201":
+//     "$ref": "#/responses/WishlistItem"
+//   "400":
+//     "$ref": "#/responses/problem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "413":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) addWishlistProduct(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req addProductRequest
+	if err := decodeRequest(r, &req); err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	wishlistID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	wishlistItem := req.wishlistItem(wishlistID)
+
+	if err := handler.wishlistService.AddProduct(r.Context(), &wishlistItem, user.ID); err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to add wishlist item %v: %s", wishlistItem, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(wishlistItem); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with wishlist item %s", err)
+	}
+}
+
+// swagger:operation DELETE /v1/wishlist/{id}/item/{item_id} WishlistItem removeWishlistItem
+// ---
+// summary: removes a single line from existing wishlist
+// description:
+// parameters:
+// - name: id
+//   in: path
+//   description: wishlist id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: item_id
+//   in: path
+//   description: wishlist item id to delete
+//   required: true
+//   type: integer
+//   format: int64
+// responses:
+//   "204":
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) removeWishlistItem(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	wishlistID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	itemID, err := int64Param(ps, "item_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	if err := handler.wishlistService.RemoveItem(r.Context(), wishlistID, itemID, user.ID); err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to remove wishlist item (%d:%d): %s", wishlistID, itemID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// swagger:operation POST /v1/wishlist/{id}/item/{item_id}/move-to-cart WishlistItem moveWishlistItemToCart
+// ---
+// summary: moves a line of the wishlist to an existing shopping cart
+// description: The line is added to the shopping cart as with addProduct and removed from the wishlist
+// parameters:
+// - name: id
+//   in: path
+//   description: wishlist id
+//   required: true
+//   type: integer
+//   format: int64
+// - name: item_id
+//   in: path
+//   description: wishlist item id to move
+//   required: true
+//   type: integer
+//   format: int64
+// - name: cart
+//   in: body
+//   description: target shopping cart
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/moveToCartRequest"
+// responses:
+//   "201":
+//     "$ref": "#/responses/ShoppingCartItem"
+//   "400":
+//     "$ref": "#/responses/problem"
+//   "401":
+//     "$ref": "#/responses/problem"
+//   "404":
+//     "$ref": "#/responses/problem"
+//   "409":
+//     "$ref": "#/responses/problem"
+//   "422":
+//     "$ref": "#/responses/problem"
+//   "500":
+//     "$ref": "#/responses/problem"
+func (handler *Handler) moveWishlistItemToCart(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var req moveToCartRequest
+	if err := decodeRequest(r, &req); err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	wishlistID, err := int64Param(ps, "id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	itemID, err := int64Param(ps, "item_id")
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	user, err := handler.authUser(r)
+	if err != nil {
+		handler.Error(w, r, err)
+		return
+	}
+
+	cartItem, err := handler.wishlistService.MoveToCart(r.Context(), wishlistID, itemID, req.ShoppingCartID, user.ID)
+	if err != nil {
+		handler.Error(w, r, err)
+		logging.FromContext(r.Context()).Errorf("Unable to move wishlist item (%d:%d) to shopping cart %d: %s", wishlistID, itemID, req.ShoppingCartID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(cartItem); err != nil {
+		logging.FromContext(r.Context()).Errorf("Unable to respond with cart item %s", err)
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func newWishlistHandler() *Handler {
+	storageMock := &shoppingcart_mock.MockStorage{}
+
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+		WishlistStorage:     storageMock,
+	})
+
+	return &Handler{
+		shoppingCartService: services.ShoppingCart,
+		wishlistService:     services.Wishlist,
+		authService:         auth.New(),
+	}
+}
+
+func TestHandler_createWishlist(t *testing.T) {
+	handler := newWishlistHandler()
+	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		wantStatusCode int
+	}{
+		{
+			name:           "create wishlist successful",
+			body:           wishlistRequest{Name: "Birthday"},
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "create wishlist without name",
+			body:           wishlistRequest{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "create wishlist with items",
+			body:           map[string]interface{}{"name": "Birthday", "items": []int{1}},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodPost, "/v1/wishlist", tt.body)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(handler.createWishlist)(w, r, nil)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_listWishlists(t *testing.T) {
+	handler := newWishlistHandler()
+	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
+
+	w := httptest.NewRecorder()
+	r := newRequest(http.MethodGet, "/v1/wishlist", nil)
+	r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+	handler.authMiddleware(handler.listWishlists)(w, r, nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status code %d, but got %d", http.StatusOK, w.Code)
+	}
+
+	var wishlists []shoppingcart.Wishlist
+	if err := json.NewDecoder(w.Body).Decode(&wishlists); err != nil {
+		t.Fatalf("Can't decode response: %v", err)
+	}
+
+	if len(wishlists) != 2 {
+		t.Fatalf("Expected %d wishlists, got %d", 2, len(wishlists))
+	}
+}
+
+func TestHandler_renameWishlist(t *testing.T) {
+	handler := newWishlistHandler()
+
+	tests := []struct {
+		name           string
+		creds          string
+		wishlistID     string
+		wantStatusCode int
+	}{
+		{
+			name:           "rename wishlist successful",
+			creds:          "test:test",
+			wishlistID:     "1",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "rename wishlist which doesn't belong to this user",
+			creds:          "hacker:password",
+			wishlistID:     "1",
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodPatch, "/v1/wishlist/"+tt.wishlistID, wishlistRequest{Name: "Wedding"})
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(tt.creds))))
+
+			handler.authMiddleware(handler.renameWishlist)(w, r, []httprouter.Param{{Key: "id", Value: tt.wishlistID}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_moveWishlistItemToCart(t *testing.T) {
+	handler := newWishlistHandler()
+	creds := base64.StdEncoding.EncodeToString([]byte(`test:test`))
+
+	tests := []struct {
+		name           string
+		itemID         string
+		body           interface{}
+		wantStatusCode int
+	}{
+		{
+			name:           "move item successful",
+			itemID:         "2",
+			body:           moveToCartRequest{ShoppingCartID: 1},
+			wantStatusCode: http.StatusCreated,
+		},
+		{
+			name:           "move item to cart of another user",
+			itemID:         "2",
+			body:           moveToCartRequest{ShoppingCartID: 2},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "move item without cart",
+			itemID:         "2",
+			body:           moveToCartRequest{},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "move unknown item",
+			itemID:         "9",
+			body:           moveToCartRequest{ShoppingCartID: 1},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := newRequest(http.MethodPost, "/v1/wishlist/1/item/"+tt.itemID+"/move-to-cart", tt.body)
+			r.Header.Set("Authorization", fmt.Sprintf("Basic %s", creds))
+
+			handler.authMiddleware(handler.moveWishlistItemToCart)(w, r, []httprouter.Param{{Key: "id", Value: "1"}, {Key: "item_id", Value: tt.itemID}})
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("Expected HTTP status code %d, but got %d", tt.wantStatusCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package health reports whether the service can serve requests, by checking
+// the dependencies it relies on.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout limits the duration of a single check.
+const DefaultTimeout = 2 * time.Second
+
+// Statuses of reports and checks
+const (
+	StatusUp           = "up"
+	StatusDown         = "down"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check returns an error if the dependency can't be used.
+type Check func(ctx context.Context) error
+
+// Result is the outcome of a single check.
+type Result struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of the checks, by the name of the dependency.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+// Up reports whether the service is ready to serve requests.
+func (report Report) Up() bool {
+	return report.Status == StatusUp
+}
+
+// Checker runs the checks of the registered dependencies. Once the service
+// starts to shut down it reports it is not ready, so load balancers stop
+// sending requests before the servers stop.
+type Checker struct {
+	timeout time.Duration
+
+	mu           sync.RWMutex
+	checks       map[string]Check
+	shuttingDown bool
+}
+
+// New returns a new Checker, which limits every check to timeout or to
+// DefaultTimeout if zero.
+func New(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Register adds the check of a dependency, replacing the check registered
+// under the same name.
+func (checker *Checker) Register(name string, check Check) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	checker.checks[name] = check
+}
+
+// ShutDown makes the checker report that the service is shutting down.
+func (checker *Checker) ShutDown() {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	checker.shuttingDown = true
+}
+
+// Check runs all checks concurrently. The service is up if every check
+// succeeds within the timeout and it is not shutting down.
+func (checker *Checker) Check(ctx context.Context) Report {
+	checker.mu.RLock()
+	shuttingDown := checker.shuttingDown
+	names := make([]string, 0, len(checker.checks))
+	for name := range checker.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = checker.checks[name]
+	}
+	checker.mu.RUnlock()
+
+	if shuttingDown {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = checker.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// run runs a single check within the timeout
+func (checker *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checker.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := make(chan error, 1)
+	go func() {
+		err <- check(ctx)
+	}()
+
+	// Checks which ignore the context must not hold up the report
+	result := Result{Status: StatusUp}
+	select {
+	case e := <-err:
+		if e != nil {
+			result.Status, result.Error = StatusDown, e.Error()
+		}
+	case <-ctx.Done():
+		result.Status, result.Error = StatusDown, ctx.Err().Error()
+	}
+	result.DurationMS = time.Since(start).Milliseconds()
+
+	return result
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChecker_Check(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	hanging := func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name         string
+		checks       map[string]Check
+		shutDown     bool
+		wantStatus   string
+		wantStatuses map[string]string
+	}{
+		{
+			name:       "no checks",
+			wantStatus: StatusUp,
+		},
+		{
+			name:         "all up",
+			checks:       map[string]Check{"database": up, "auth": up},
+			wantStatus:   StatusUp,
+			wantStatuses: map[string]string{"database": StatusUp, "auth": StatusUp},
+		},
+		{
+			name:         "one down",
+			checks:       map[string]Check{"database": down, "auth": up},
+			wantStatus:   StatusDown,
+			wantStatuses: map[string]string{"database": StatusDown, "auth": StatusUp},
+		},
+		{
+			name:         "timeout",
+			checks:       map[string]Check{"database": hanging},
+			wantStatus:   StatusDown,
+			wantStatuses: map[string]string{"database": StatusDown},
+		},
+		{
+			name:       "shutting down",
+			checks:     map[string]Check{"database": up},
+			shutDown:   true,
+			wantStatus: StatusShuttingDown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := New(10 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+			if tt.shutDown {
+				checker.ShutDown()
+			}
+
+			report := checker.Check(context.Background())
+
+			if report.Status != tt.wantStatus {
+				t.Fatalf("Expected status %q, but got %q", tt.wantStatus, report.Status)
+			}
+			if report.Up() != (tt.wantStatus == StatusUp) {
+				t.Fatalf("Expected up %t, but got %t", tt.wantStatus == StatusUp, report.Up())
+			}
+			if len(report.Checks) != len(tt.wantStatuses) {
+				t.Fatalf("Expected %d checks, but got %d", len(tt.wantStatuses), len(report.Checks))
+			}
+			for name, status := range tt.wantStatuses {
+				result := report.Checks[name]
+				if result.Status != status {
+					t.Fatalf("Expected status of %s %q, but got %q", name, status, result.Status)
+				}
+				if (result.Error != "") != (status == StatusDown) {
+					t.Fatalf("Expected error of %s only if down, but got %q", name, result.Error)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package shoppingcart
+
+import "time"
+
+// Actions recorded in the history of a shopping cart
+const (
+	ActionCreate       = "create"
+	ActionAdd          = "add"
+	ActionRemove       = "remove"
+	ActionEmpty        = "empty"
+	ActionCheckout     = "checkout"
+	ActionSaveForLater = "save_for_later"
+	ActionMoveToCart   = "move_to_cart"
+)
+
+// HistoryEntry is an immutable audit record of a single change of a shopping
+// cart. Entries of line changes carry the quantity of the active line before
+// and after the change, so a line saved for later ends up with quantity 0.
+// swagger:response HistoryEntry
+type HistoryEntry struct {
+	ID             int64     `json:"id"`
+	ShoppingCartID int64     `json:"shoppingcart_id" gorm:"column:shoppingcart_id"`
+	Action         string    `json:"action"`
+	ActorID        int64     `json:"actor_id"`
+	ItemID         int64     `json:"item_id,omitempty"`
+	ProductID      int64     `json:"product_id,omitempty"`
+	VariantID      string    `json:"variant_id,omitempty"`
+	QuantityBefore uint64    `json:"quantity_before"`
+	QuantityAfter  uint64    `json:"quantity_after"`
+	RequestID      string    `json:"request_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName specifies storage table name
+func (HistoryEntry) TableName() string {
+	return "shoppingcart_history"
+}
+
+// LineChange returns the history entry of a change of the quantity of item
+func LineChange(action string, actorID int64, item ShoppingCartItem, before, after uint64) HistoryEntry {
+	return HistoryEntry{
+		ShoppingCartID: item.ShoppingCartID,
+		Action:         action,
+		ActorID:        actorID,
+		ItemID:         item.ID,
+		ProductID:      item.ProductID,
+		VariantID:      item.VariantID,
+		QuantityBefore: before,
+		QuantityAfter:  after,
+	}
+}
@@ -0,0 +1,14 @@
+package shoppingcart
+
+import "testing"
+
+func TestLineChange(t *testing.T) {
+	item := ShoppingCartItem{ID: 3, ShoppingCartID: 1, ProductID: 2, VariantID: "M", Quantity: 5}
+
+	entry := LineChange(ActionAdd, 4, item, 2, 5)
+
+	want := HistoryEntry{ShoppingCartID: 1, Action: ActionAdd, ActorID: 4, ItemID: 3, ProductID: 2, VariantID: "M", QuantityBefore: 2, QuantityAfter: 5}
+	if entry != want {
+		t.Fatalf("LineChange() = %+v, want %+v", entry, want)
+	}
+}
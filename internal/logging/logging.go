@@ -0,0 +1,88 @@
+// Package logging carries the logger of the request being served through a
+// context, and configures the output of the logs.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bugimetal/shoppingcart/internal/requestid"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats of the logs
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type key int
+
+var loggerKey key
+
+// logger holds the entry of a request. Fields are added to it while the
+// request is handled, so they are part of every later log of the request.
+type logger struct {
+	mu    sync.Mutex
+	entry *logrus.Entry
+}
+
+// Setup configures the level and format of the standard logger.
+func Setup(level, format string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "", FormatText:
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	case FormatJSON:
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+
+	logrus.SetLevel(lvl)
+
+	return nil
+}
+
+// NewContext returns a copy of ctx carrying entry as the logger of the request.
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey, &logger{entry: entry})
+}
+
+// FromContext returns the logger of the request served within ctx. Outside
+// of requests it is the standard logger, with the request ID if ctx has one.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if l, ok := ctx.Value(loggerKey).(*logger); ok {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		return l.entry
+	}
+
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	if requestID := requestid.FromContext(ctx); requestID != "" {
+		entry = entry.WithField("request_id", requestID)
+	}
+
+	return entry
+}
+
+// AddFields adds fields to the logger of the request served within ctx. It
+// does nothing outside of requests.
+func AddFields(ctx context.Context, fields logrus.Fields) {
+	l, ok := ctx.Value(loggerKey).(*logger)
+	if !ok {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entry = l.entry.WithFields(fields)
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart/internal/requestid"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFromContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        func() context.Context
+		wantFields logrus.Fields
+	}{
+		{
+			name:       "no request",
+			ctx:        context.Background,
+			wantFields: logrus.Fields{},
+		},
+		{
+			name: "request ID only",
+			ctx: func() context.Context {
+				return requestid.NewContext(context.Background(), "abc")
+			},
+			wantFields: logrus.Fields{"request_id": "abc"},
+		},
+		{
+			name: "fields added while handling the request",
+			ctx: func() context.Context {
+				ctx := NewContext(context.Background(), logrus.WithField("request_id", "abc"))
+
+				// Fields added in a derived context are seen by the parent
+				AddFields(context.WithValue(ctx, key(1), nil), logrus.Fields{"user_id": int64(1)})
+				AddFields(ctx, logrus.Fields{"cart_id": int64(2)})
+				return ctx
+			},
+			wantFields: logrus.Fields{"request_id": "abc", "user_id": int64(1), "cart_id": int64(2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := FromContext(tt.ctx()).Data
+
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("Expected fields %v, but got %v", tt.wantFields, fields)
+			}
+			for name, value := range tt.wantFields {
+				if fields[name] != value {
+					t.Fatalf("Expected field %s %v, but got %v", name, value, fields[name])
+				}
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	defer logrus.SetLevel(logrus.GetLevel())
+	defer logrus.SetFormatter(logrus.StandardLogger().Formatter)
+
+	tests := []struct {
+		name    string
+		level   string
+		format  string
+		wantErr bool
+	}{
+		{name: "text", level: "info", format: FormatText},
+		{name: "json", level: "debug", format: FormatJSON},
+		{name: "default format", level: "warn"},
+		{name: "unknown level", level: "verbose", wantErr: true},
+		{name: "unknown format", level: "info", format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Setup(tt.level, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %t, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
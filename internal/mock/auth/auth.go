@@ -14,6 +14,7 @@ var (
 // AuthService describes an interface to Authenticate users
 type AuthService interface {
 	Authenticate(context.Context, User) (User, error)
+	Ping(context.Context) error
 }
 
 type Auth struct {
@@ -24,6 +25,9 @@ type User struct {
 	ID       int64  `json:"id"`
 	Name     string `json:"name"`
 	Password []byte `json:"password"`
+
+	// Admin grants access to the data of all users, e.g. for customer support
+	Admin bool `json:"admin"`
 }
 
 // Validate validates user for required params
@@ -59,3 +63,9 @@ func (a *Auth) Authenticate(ctx context.Context, user User) (User, error) {
 
 	return user, nil
 }
+
+// Ping checks that the service is reachable. Users are authenticated in
+// process, so it always is.
+func (a *Auth) Ping(ctx context.Context) error {
+	return nil
+}
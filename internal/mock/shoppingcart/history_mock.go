@@ -0,0 +1,38 @@
+package shoppingcart
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// history returns the audit trail of cart 1, newest entries first
+func history() []shoppingcart.HistoryEntry {
+	return []shoppingcart.HistoryEntry{
+		{ID: 3, ShoppingCartID: 1, Action: shoppingcart.ActionAdd, ActorID: 3, ItemID: 2, ProductID: 2, QuantityBefore: 4, QuantityAfter: 10},
+		{ID: 2, ShoppingCartID: 1, Action: shoppingcart.ActionAdd, ActorID: 1, ItemID: 2, ProductID: 2, QuantityAfter: 4},
+		{ID: 1, ShoppingCartID: 1, Action: shoppingcart.ActionCreate, ActorID: 1},
+	}
+}
+
+func (db *MockStorage) AddHistoryEntries(ctx context.Context, entries ...shoppingcart.HistoryEntry) error {
+	return nil
+}
+
+func (db *MockStorage) ListHistory(ctx context.Context, shoppingCartID int64, limit, offset int) ([]shoppingcart.HistoryEntry, error) {
+	if shoppingCartID != 1 {
+		return nil, shoppingcart.ErrCartNotFound
+	}
+
+	entries := []shoppingcart.HistoryEntry{}
+	if offset >= len(history()) {
+		return entries, nil
+	}
+
+	entries = history()[offset:]
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,44 @@
+package shoppingcart
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// cartMembers returns the members of cart 1: user 3 is an editor, user 4 a
+// viewer and user 5 is invited as an editor but didn't accept yet
+func cartMembers() []shoppingcart.CartMember {
+	acceptedAt := time.Date(2020, 4, 18, 13, 36, 0, 0, time.UTC)
+
+	return []shoppingcart.CartMember{
+		{ShoppingCartID: 1, UserID: 3, Role: shoppingcart.RoleEditor, InvitedBy: 1, AcceptedAt: &acceptedAt},
+		{ShoppingCartID: 1, UserID: 4, Role: shoppingcart.RoleViewer, InvitedBy: 1, AcceptedAt: &acceptedAt},
+		{ShoppingCartID: 1, UserID: 5, Role: shoppingcart.RoleEditor, InvitedBy: 1},
+	}
+}
+
+func (db *MockStorage) AddMember(ctx context.Context, member *shoppingcart.CartMember) error {
+	return nil
+}
+
+func (db *MockStorage) GetMember(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.CartMember, error) {
+	if shoppingCartID == 1 {
+		for _, member := range cartMembers() {
+			if member.UserID == userID {
+				return member, nil
+			}
+		}
+	}
+
+	return shoppingcart.CartMember{}, shoppingcart.ErrMemberNotFound
+}
+
+func (db *MockStorage) UpdateMember(ctx context.Context, member *shoppingcart.CartMember) error {
+	return nil
+}
+
+func (db *MockStorage) RemoveMember(ctx context.Context, shoppingCartID, userID int64) error {
+	return nil
+}
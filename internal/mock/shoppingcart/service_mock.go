@@ -2,6 +2,7 @@ package shoppingcart
 
 import (
 	"context"
+	"time"
 
 	"github.com/bugimetal/shoppingcart"
 )
@@ -31,6 +32,15 @@ func (service *MockShoppingCartService) Empty(ctx context.Context, shoppingCartI
 	return nil
 }
 
+func (service *MockShoppingCartService) Checkout(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.ShoppingCart, error) {
+	cart, err := service.Get(ctx, shoppingCartID, userID)
+	if err != nil {
+		return cart, err
+	}
+
+	return cart, shoppingcart.ErrCartHasNoItems
+}
+
 func (service *MockShoppingCartService) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem, userID int64) error {
 	if err := cartItem.Validate(); err != nil {
 		return err
@@ -42,3 +52,57 @@ func (service *MockShoppingCartService) AddProduct(ctx context.Context, cartItem
 func (service *MockShoppingCartService) RemoveProduct(ctx context.Context, shoppingCartID, productID, userID int64) error {
 	return nil
 }
+
+func (service *MockShoppingCartService) RemoveItem(ctx context.Context, shoppingCartID, itemID, userID int64) error {
+	return nil
+}
+
+func (service *MockShoppingCartService) SaveForLater(ctx context.Context, shoppingCartID, itemID, userID int64) (shoppingcart.ShoppingCartItem, error) {
+	return shoppingcart.ShoppingCartItem{ID: itemID, ShoppingCartID: shoppingCartID, List: shoppingcart.ListSaved}, nil
+}
+
+func (service *MockShoppingCartService) MoveToCart(ctx context.Context, shoppingCartID, itemID, userID int64) (shoppingcart.ShoppingCartItem, error) {
+	return shoppingcart.ShoppingCartItem{ID: itemID, ShoppingCartID: shoppingCartID, List: shoppingcart.ListCart}, nil
+}
+
+func (service *MockShoppingCartService) Invite(ctx context.Context, shoppingCartID, userID int64, member *shoppingcart.CartMember) error {
+	member.ShoppingCartID = shoppingCartID
+	member.InvitedBy = userID
+	return nil
+}
+
+func (service *MockShoppingCartService) AcceptInvitation(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.CartMember, error) {
+	acceptedAt := time.Now()
+	return shoppingcart.CartMember{ShoppingCartID: shoppingCartID, UserID: userID, Role: shoppingcart.RoleEditor, AcceptedAt: &acceptedAt}, nil
+}
+
+func (service *MockShoppingCartService) RevokeMember(ctx context.Context, shoppingCartID, userID, memberUserID int64) error {
+	return nil
+}
+
+func (service *MockShoppingCartService) History(ctx context.Context, shoppingCartID, userID int64, limit, offset int) ([]shoppingcart.HistoryEntry, error) {
+	if userID == hackerUserID {
+		return nil, shoppingcart.ErrCartNotFound
+	}
+	return service.AdminHistory(ctx, shoppingCartID, limit, offset)
+}
+
+func (service *MockShoppingCartService) Snapshot(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.Snapshot, error) {
+	return shoppingcart.Snapshot{ID: 1, ShoppingCartID: shoppingCartID, Reason: shoppingcart.SnapshotManual, CreatedBy: userID}, nil
+}
+
+func (service *MockShoppingCartService) ListSnapshots(ctx context.Context, shoppingCartID, userID int64) ([]shoppingcart.Snapshot, error) {
+	return []shoppingcart.Snapshot{}, nil
+}
+
+func (service *MockShoppingCartService) GetSnapshot(ctx context.Context, shoppingCartID, snapshotID, userID int64) (shoppingcart.Snapshot, error) {
+	return shoppingcart.Snapshot{ID: snapshotID, ShoppingCartID: shoppingCartID}, nil
+}
+
+func (service *MockShoppingCartService) Restore(ctx context.Context, shoppingCartID, snapshotID, userID int64) (shoppingcart.ShoppingCart, error) {
+	return service.Get(ctx, shoppingCartID, userID)
+}
+
+func (service *MockShoppingCartService) AdminHistory(ctx context.Context, shoppingCartID int64, limit, offset int) ([]shoppingcart.HistoryEntry, error) {
+	return []shoppingcart.HistoryEntry{}, nil
+}
@@ -0,0 +1,47 @@
+package shoppingcart
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// snapshot returns snapshot 1 of cart 1, taken before the cart was emptied
+func snapshot() shoppingcart.Snapshot {
+	return shoppingcart.Snapshot{
+		ID:             1,
+		ShoppingCartID: 1,
+		Reason:         shoppingcart.SnapshotEmpty,
+		CreatedBy:      1,
+		CreatedAt:      time.Date(2020, 4, 18, 13, 36, 0, 0, time.UTC),
+		Items: []shoppingcart.SnapshotItem{
+			{ID: 1, SnapshotID: 1, ProductID: 7, Quantity: 2},
+			{ID: 2, SnapshotID: 1, ProductID: 3, VariantID: "M", Quantity: 1},
+		},
+	}
+}
+
+func (db *MockStorage) CreateSnapshot(ctx context.Context, snapshot *shoppingcart.Snapshot) error {
+	snapshot.ID = 2
+	snapshot.CreatedAt = time.Now()
+	return nil
+}
+
+func (db *MockStorage) GetSnapshot(ctx context.Context, shoppingCartID, snapshotID int64) (shoppingcart.Snapshot, error) {
+	if shoppingCartID == 1 && snapshotID == 1 {
+		return snapshot(), nil
+	}
+
+	return shoppingcart.Snapshot{}, shoppingcart.ErrSnapshotNotFound
+}
+
+func (db *MockStorage) ListSnapshots(ctx context.Context, shoppingCartID int64) ([]shoppingcart.Snapshot, error) {
+	if shoppingCartID != 1 {
+		return []shoppingcart.Snapshot{}, nil
+	}
+
+	s := snapshot()
+	s.Items = nil
+	return []shoppingcart.Snapshot{s}, nil
+}
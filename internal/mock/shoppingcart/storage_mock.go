@@ -13,14 +13,22 @@ func (db *MockStorage) Create(ctx context.Context, cart *shoppingcart.ShoppingCa
 }
 
 func (db *MockStorage) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
-	if ID == 1 && userID == 1 {
+	// Cart 1 is owned by user 1 and shared with the accepted members 3 and 4
+	if ID == 1 && (userID == 1 || userID == 3 || userID == 4) {
 		return shoppingcart.ShoppingCart{
 			ID:     1,
 			UserID: 1,
 			Items: []shoppingcart.ShoppingCartItem{
-				{ProductID: 1, Quantity: 1},
-				{ProductID: 2, Quantity: 10},
+				{ID: 1, ShoppingCartID: 1, ProductID: 1, Quantity: 1},
+				{ID: 2, ShoppingCartID: 1, ProductID: 2, Quantity: 10},
+				{ID: 3, ShoppingCartID: 1, ProductID: 3, VariantID: "M", Quantity: 1},
+				{ID: 4, ShoppingCartID: 1, ProductID: 3, VariantID: "L", Quantity: 2},
 			},
+			SavedItems: []shoppingcart.ShoppingCartItem{
+				{ID: 5, ShoppingCartID: 1, ProductID: 4, Quantity: 1, List: shoppingcart.ListSaved},
+				{ID: 6, ShoppingCartID: 1, ProductID: 2, Quantity: 3, List: shoppingcart.ListSaved},
+			},
+			Members: cartMembers(),
 		}, nil
 	}
 
@@ -42,3 +50,11 @@ func (db *MockStorage) UpdateProduct(ctx context.Context, cartItem *shoppingcart
 func (db *MockStorage) RemoveProduct(ctx context.Context, shoppingCartID, productID int64) error {
 	return nil
 }
+
+func (db *MockStorage) RemoveItem(ctx context.Context, shoppingCartID, itemID int64) error {
+	return nil
+}
+
+func (db *MockStorage) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,60 @@
+package shoppingcart
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+func (db *MockStorage) CreateWishlist(ctx context.Context, wishlist *shoppingcart.Wishlist) error {
+	wishlist.ID = 3
+	return nil
+}
+
+func (db *MockStorage) GetWishlist(ctx context.Context, wishlistID, userID int64) (shoppingcart.Wishlist, error) {
+	if wishlistID == 1 && userID == 1 {
+		return shoppingcart.Wishlist{
+			ID:     1,
+			UserID: 1,
+			Name:   "Birthday",
+			Items: []shoppingcart.WishlistItem{
+				{ID: 1, WishlistID: 1, ProductID: 7, Quantity: 1},
+				{ID: 2, WishlistID: 1, ProductID: 2, Quantity: 1},
+				{ID: 3, WishlistID: 1, ProductID: 3, VariantID: "M", Quantity: 1},
+			},
+		}, nil
+	}
+
+	return shoppingcart.Wishlist{}, shoppingcart.ErrWishlistNotFound
+}
+
+func (db *MockStorage) ListWishlists(ctx context.Context, userID int64) ([]shoppingcart.Wishlist, error) {
+	if userID == 1 {
+		return []shoppingcart.Wishlist{
+			{ID: 1, UserID: 1, Name: "Birthday"},
+			{ID: 2, UserID: 1, Name: "Christmas"},
+		}, nil
+	}
+
+	return []shoppingcart.Wishlist{}, nil
+}
+
+func (db *MockStorage) UpdateWishlist(ctx context.Context, wishlist *shoppingcart.Wishlist) error {
+	return nil
+}
+
+func (db *MockStorage) DeleteWishlist(ctx context.Context, wishlistID int64) error {
+	return nil
+}
+
+func (db *MockStorage) AddWishlistItem(ctx context.Context, wishlistItem *shoppingcart.WishlistItem) error {
+	return nil
+}
+
+func (db *MockStorage) UpdateWishlistItem(ctx context.Context, wishlistItem *shoppingcart.WishlistItem) error {
+	return nil
+}
+
+func (db *MockStorage) RemoveWishlistItem(ctx context.Context, wishlistID, itemID int64) error {
+	return nil
+}
@@ -0,0 +1,59 @@
+// Package requestid carries the ID of the request being served through a context.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header is the HTTP header used to pass request IDs between services.
+const Header = "X-Request-ID"
+
+// MaxLength is the maximum length of a request ID, as it is stored along with
+// the changes made by the request.
+const MaxLength = 64
+
+type key int
+
+var requestIDKey key
+
+// New generates a new random request ID.
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// Valid reports whether requestID may be used as passed by a client: it must
+// not be empty, exceed MaxLength or hold characters other than letters,
+// digits, '.', '_' and '-', so it can't forge log lines or headers.
+func Valid(requestID string) bool {
+	if requestID == "" || len(requestID) > MaxLength {
+		return false
+	}
+
+	for _, c := range requestID {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// FromContext returns the request ID stored in ctx, if any.
+func FromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
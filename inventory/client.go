@@ -0,0 +1,142 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// DefaultTimeout limits the duration of requests to the stock service.
+const DefaultTimeout = 2 * time.Second
+
+// ClientConfig describes how to reach the stock service.
+type ClientConfig struct {
+	// BaseURL is the address of the stock service, e.g. http://stock:8080
+	BaseURL string
+	// Timeout limits the duration of a single request.
+	Timeout time.Duration
+	// HTTPClient is used to perform requests, http.DefaultClient if not set.
+	HTTPClient *http.Client
+}
+
+// Client reserves stock through the HTTP API of the stock service:
+//
+//	PUT    /v1/reservations/{cart_id}/{product_id}?variant_id={variant_id}  {"quantity": 2}
+//	DELETE /v1/reservations/{cart_id}/{product_id}?variant_id={variant_id}
+//	DELETE /v1/reservations/{cart_id}
+//	POST   /v1/reservations/{cart_id}/extend
+//	POST   /v1/reservations/{cart_id}/commit
+//
+// The variant_id parameter is omitted for products without variants. The stock
+// service responds with 409 Conflict when there is not enough stock.
+type Client struct {
+	baseURL    string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// NewClient returns a new Client.
+func NewClient(config ClientConfig) *Client {
+	client := &Client{
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		timeout:    config.Timeout,
+		httpClient: config.HTTPClient,
+	}
+
+	if client.timeout == 0 {
+		client.timeout = DefaultTimeout
+	}
+	if client.httpClient == nil {
+		client.httpClient = http.DefaultClient
+	}
+
+	return client
+}
+
+// Reserve sets the reserved quantity of the product variant for the cart
+func (client *Client) Reserve(ctx context.Context, shoppingCartID, productID int64, variantID string, quantity uint64) error {
+	body := struct {
+		Quantity uint64 `json:"quantity"`
+	}{Quantity: quantity}
+
+	return client.do(ctx, http.MethodPut, reservationPath(shoppingCartID, productID, variantID), body)
+}
+
+// Release removes the reservation of the product variant for the cart
+func (client *Client) Release(ctx context.Context, shoppingCartID, productID int64, variantID string) error {
+	return client.do(ctx, http.MethodDelete, reservationPath(shoppingCartID, productID, variantID), nil)
+}
+
+// reservationPath returns the path of the reservation of the product variant
+func reservationPath(shoppingCartID, productID int64, variantID string) string {
+	path := fmt.Sprintf("/v1/reservations/%d/%d", shoppingCartID, productID)
+	if variantID != "" {
+		path += "?variant_id=" + url.QueryEscape(variantID)
+	}
+
+	return path
+}
+
+// ReleaseAll removes all reservations of the cart
+func (client *Client) ReleaseAll(ctx context.Context, shoppingCartID int64) error {
+	return client.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/reservations/%d", shoppingCartID), nil)
+}
+
+// Extend prolongs all reservations of the cart
+func (client *Client) Extend(ctx context.Context, shoppingCartID int64) error {
+	return client.do(ctx, http.MethodPost, fmt.Sprintf("/v1/reservations/%d/extend", shoppingCartID), nil)
+}
+
+// Commit converts the reservations of the cart into sold stock
+func (client *Client) Commit(ctx context.Context, shoppingCartID int64) error {
+	return client.do(ctx, http.MethodPost, fmt.Sprintf("/v1/reservations/%d/commit", shoppingCartID), nil)
+}
+
+func (client *Client) do(ctx context.Context, method, path string, in interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, client.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return shoppingcart.ErrOutOfStock
+	case resp.StatusCode == http.StatusNotFound && method == http.MethodDelete:
+		// Nothing to release
+		return nil
+	case resp.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("stock service responded to %s %s with status %d", method, path, resp.StatusCode)
+	}
+
+	return nil
+}
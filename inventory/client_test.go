@@ -0,0 +1,81 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+func TestClient(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+
+		if r.Method == http.MethodPut {
+			var body struct {
+				Quantity uint64 `json:"quantity"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("Can't decode request: %v", err)
+			}
+
+			if body.Quantity > 5 {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		}
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{BaseURL: server.URL})
+	ctx := context.Background()
+
+	if err := client.Reserve(ctx, 1, 2, "", 3); err != nil {
+		t.Fatalf("Reserve() unexpected error = %v", err)
+	}
+
+	if err := client.Reserve(ctx, 1, 2, "L", 6); err != shoppingcart.ErrOutOfStock {
+		t.Fatalf("Reserve() error = %v, want %v", err, shoppingcart.ErrOutOfStock)
+	}
+
+	if err := client.Release(ctx, 1, 2, "L"); err != nil {
+		t.Fatalf("Release() unexpected error = %v", err)
+	}
+
+	if err := client.ReleaseAll(ctx, 1); err != nil {
+		t.Fatalf("ReleaseAll() unexpected error = %v", err)
+	}
+
+	if err := client.Extend(ctx, 1); err != nil {
+		t.Fatalf("Extend() unexpected error = %v", err)
+	}
+
+	if err := client.Commit(ctx, 1); err != nil {
+		t.Fatalf("Commit() unexpected error = %v", err)
+	}
+
+	want := []string{
+		"PUT /v1/reservations/1/2",
+		"PUT /v1/reservations/1/2?variant_id=L",
+		"DELETE /v1/reservations/1/2?variant_id=L",
+		"DELETE /v1/reservations/1",
+		"POST /v1/reservations/1/extend",
+		"POST /v1/reservations/1/commit",
+	}
+	for i := range want {
+		if i >= len(requests) || requests[i] != want[i] {
+			t.Fatalf("Requests = %v, want %v", requests, want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package inventory provides implementations of the inventory service, which
+// keeps soft reservations of products placed in shopping carts.
+package inventory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// DefaultReservationTTL is how long a reservation is held without activity.
+const DefaultReservationTTL = 30 * time.Minute
+
+// unit identifies a stock keeping unit, i.e. a variant of a product.
+// Products without variants use the empty variant ID.
+type unit struct {
+	productID int64
+	variantID string
+}
+
+type reservation struct {
+	quantity  uint64
+	expiresAt time.Time
+}
+
+// Local keeps stock levels and reservations in memory. It is meant for
+// single-node deployments and development. Stock is kept per product variant,
+// variants without a stock level are not tracked and can always be reserved.
+type Local struct {
+	mu           sync.Mutex
+	ttl          time.Duration
+	stock        map[unit]uint64
+	reservations map[int64]map[unit]reservation // shopping cart ID -> variant -> reservation
+	now          func() time.Time
+}
+
+// NewLocal returns a new Local inventory with the given stock levels of
+// products without variants. Variants are stocked with SetStock.
+func NewLocal(stock map[int64]uint64, ttl time.Duration) *Local {
+	if ttl == 0 {
+		ttl = DefaultReservationTTL
+	}
+
+	local := &Local{
+		ttl:          ttl,
+		stock:        make(map[unit]uint64, len(stock)),
+		reservations: make(map[int64]map[unit]reservation),
+		now:          time.Now,
+	}
+
+	for productID, quantity := range stock {
+		local.stock[unit{productID: productID}] = quantity
+	}
+
+	return local
+}
+
+// SetStock sets the stock level of the product variant
+func (local *Local) SetStock(productID int64, variantID string, quantity uint64) {
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	local.stock[unit{productID: productID, variantID: variantID}] = quantity
+}
+
+// Available returns the quantity of the product variant which is not reserved by any cart
+func (local *Local) Available(productID int64, variantID string) (uint64, bool) {
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	return local.available(unit{productID: productID, variantID: variantID}, 0)
+}
+
+// Reserve sets the reserved quantity of the product variant for the cart
+func (local *Local) Reserve(ctx context.Context, shoppingCartID, productID int64, variantID string, quantity uint64) error {
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	local.expire()
+
+	u := unit{productID: productID, variantID: variantID}
+	if available, tracked := local.available(u, shoppingCartID); tracked && quantity > available {
+		return shoppingcart.ErrOutOfStock
+	}
+
+	if local.reservations[shoppingCartID] == nil {
+		local.reservations[shoppingCartID] = make(map[unit]reservation)
+	}
+
+	local.reservations[shoppingCartID][u] = reservation{
+		quantity:  quantity,
+		expiresAt: local.now().Add(local.ttl),
+	}
+
+	return nil
+}
+
+// Release removes the reservation of the product variant for the cart
+func (local *Local) Release(ctx context.Context, shoppingCartID, productID int64, variantID string) error {
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	delete(local.reservations[shoppingCartID], unit{productID: productID, variantID: variantID})
+	if len(local.reservations[shoppingCartID]) == 0 {
+		delete(local.reservations, shoppingCartID)
+	}
+
+	return nil
+}
+
+// ReleaseAll removes all reservations of the cart
+func (local *Local) ReleaseAll(ctx context.Context, shoppingCartID int64) error {
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	delete(local.reservations, shoppingCartID)
+
+	return nil
+}
+
+// Extend prolongs all reservations of the cart. Reservations which already
+// expired are not restored.
+func (local *Local) Extend(ctx context.Context, shoppingCartID int64) error {
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	now := local.now()
+	for u, r := range local.reservations[shoppingCartID] {
+		if r.expiresAt.Before(now) {
+			continue
+		}
+
+		r.expiresAt = now.Add(local.ttl)
+		local.reservations[shoppingCartID][u] = r
+	}
+
+	return nil
+}
+
+// Commit deducts the reserved quantities of the cart from the stock levels
+// and removes the reservations. Expired reservations are not committed.
+// Stock which was lowered below the reservation since drops to zero.
+func (local *Local) Commit(ctx context.Context, shoppingCartID int64) error {
+	local.mu.Lock()
+	defer local.mu.Unlock()
+
+	local.expire()
+
+	for u, r := range local.reservations[shoppingCartID] {
+		stock, tracked := local.stock[u]
+		if !tracked {
+			continue
+		}
+
+		if r.quantity > stock {
+			local.stock[u] = 0
+		} else {
+			local.stock[u] = stock - r.quantity
+		}
+	}
+
+	delete(local.reservations, shoppingCartID)
+
+	return nil
+}
+
+// expire drops reservations which were not extended in time
+func (local *Local) expire() {
+	now := local.now()
+	for shoppingCartID, reservations := range local.reservations {
+		for u, r := range reservations {
+			if r.expiresAt.Before(now) {
+				delete(reservations, u)
+			}
+		}
+
+		if len(reservations) == 0 {
+			delete(local.reservations, shoppingCartID)
+		}
+	}
+}
+
+// available returns the stock of the variant which is not held by active
+// reservations of carts other than exceptCartID. It reports false if the
+// variant is not tracked.
+func (local *Local) available(u unit, exceptCartID int64) (uint64, bool) {
+	stock, tracked := local.stock[u]
+	if !tracked {
+		return 0, false
+	}
+
+	now := local.now()
+	for shoppingCartID, reservations := range local.reservations {
+		r, ok := reservations[u]
+		if !ok || shoppingCartID == exceptCartID || r.expiresAt.Before(now) {
+			continue
+		}
+
+		if r.quantity >= stock {
+			return 0, true
+		}
+		stock -= r.quantity
+	}
+
+	return stock, true
+}
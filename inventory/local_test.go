@@ -0,0 +1,123 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+func TestLocal_Reserve(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocal(map[int64]uint64{1: 5}, time.Minute)
+
+	if err := local.Reserve(ctx, 1, 1, "", 3); err != nil {
+		t.Fatalf("Reserve() unexpected error = %v", err)
+	}
+
+	t.Run("stock reserved by another cart", func(t *testing.T) {
+		if err := local.Reserve(ctx, 2, 1, "", 3); err != shoppingcart.ErrOutOfStock {
+			t.Fatalf("Reserve() error = %v, want %v", err, shoppingcart.ErrOutOfStock)
+		}
+	})
+
+	t.Run("own reservation can be increased", func(t *testing.T) {
+		if err := local.Reserve(ctx, 1, 1, "", 5); err != nil {
+			t.Fatalf("Reserve() unexpected error = %v", err)
+		}
+	})
+
+	t.Run("untracked product", func(t *testing.T) {
+		if err := local.Reserve(ctx, 2, 7, "", 100); err != nil {
+			t.Fatalf("Reserve() unexpected error = %v", err)
+		}
+	})
+
+	t.Run("variants are stocked separately", func(t *testing.T) {
+		local.SetStock(1, "M", 2)
+
+		if err := local.Reserve(ctx, 2, 1, "M", 2); err != nil {
+			t.Fatalf("Reserve() unexpected error = %v", err)
+		}
+
+		if err := local.Reserve(ctx, 3, 1, "M", 1); err != shoppingcart.ErrOutOfStock {
+			t.Fatalf("Reserve() error = %v, want %v", err, shoppingcart.ErrOutOfStock)
+		}
+	})
+
+	t.Run("released stock is available again", func(t *testing.T) {
+		if err := local.Release(ctx, 1, 1, ""); err != nil {
+			t.Fatalf("Release() unexpected error = %v", err)
+		}
+
+		if available, _ := local.Available(1, ""); available != 5 {
+			t.Fatalf("Available() = %d, want %d", available, 5)
+		}
+	})
+}
+
+func TestLocal_expiry(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocal(map[int64]uint64{1: 5}, time.Minute)
+
+	now := time.Now()
+	local.now = func() time.Time { return now }
+
+	if err := local.Reserve(ctx, 1, 1, "", 5); err != nil {
+		t.Fatalf("Reserve() unexpected error = %v", err)
+	}
+
+	now = now.Add(50 * time.Second)
+	if err := local.Extend(ctx, 1); err != nil {
+		t.Fatalf("Extend() unexpected error = %v", err)
+	}
+
+	now = now.Add(50 * time.Second)
+	if available, _ := local.Available(1, ""); available != 0 {
+		t.Fatalf("Available() = %d after extending, want %d", available, 0)
+	}
+
+	now = now.Add(time.Minute)
+	if available, _ := local.Available(1, ""); available != 5 {
+		t.Fatalf("Available() = %d after expiry, want %d", available, 5)
+	}
+}
+
+func TestLocal_Commit(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocal(map[int64]uint64{1: 5, 2: 1}, time.Minute)
+
+	_ = local.Reserve(ctx, 1, 1, "", 2)
+	_ = local.Reserve(ctx, 1, 2, "", 1)
+	_ = local.Reserve(ctx, 2, 1, "", 1)
+
+	if err := local.Commit(ctx, 1); err != nil {
+		t.Fatalf("Commit() unexpected error = %v", err)
+	}
+
+	// Cart 2 still holds one unit of product 1
+	if available, _ := local.Available(1, ""); available != 2 {
+		t.Fatalf("Available() = %d, want %d", available, 2)
+	}
+
+	if available, _ := local.Available(2, ""); available != 0 {
+		t.Fatalf("Available() = %d, want %d", available, 0)
+	}
+}
+
+func TestLocal_Commit_lowered(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocal(map[int64]uint64{1: 5}, time.Minute)
+
+	_ = local.Reserve(ctx, 1, 1, "", 4)
+	local.SetStock(1, "", 2)
+
+	if err := local.Commit(ctx, 1); err != nil {
+		t.Fatalf("Commit() unexpected error = %v", err)
+	}
+
+	if available, _ := local.Available(1, ""); available != 0 {
+		t.Fatalf("Available() = %d, want %d", available, 0)
+	}
+}
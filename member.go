@@ -0,0 +1,64 @@
+package shoppingcart
+
+import (
+	"errors"
+	"time"
+)
+
+// These errors can be returned by the service or storage packages when performing
+// operating on CartMember
+var (
+	ErrMemberNotFound      = errors.New("shopping cart member not found")
+	ErrMemberAlreadyExists = errors.New("user is already a member of the shopping cart")
+	ErrInvalidRole         = errors.New("role is not valid")
+)
+
+// Roles of the users of a shopping cart. The owner is the user who created the
+// cart, editors may modify it and viewers may only look at it.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+// CartMember grants a user other than the owner access to a shopping cart.
+// Members are invited by the owner and get access once they accept.
+// swagger:response CartMember
+type CartMember struct {
+	ShoppingCartID int64      `json:"shoppingcart_id" gorm:"column:shoppingcart_id;primary_key;auto_increment:false"`
+	UserID         int64      `json:"user_id" gorm:"primary_key;auto_increment:false"`
+	Role           string     `json:"role"`
+	InvitedBy      int64      `json:"invited_by"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName specifies storage table name
+func (CartMember) TableName() string {
+	return "shoppingcart_member"
+}
+
+// Accepted reports whether the member accepted the invitation
+func (member CartMember) Accepted() bool {
+	return member.AcceptedAt != nil
+}
+
+// Validate validates CartMember. All failed fields are reported at once
+// as ValidationErrors.
+func (member *CartMember) Validate() error {
+	var errs ValidationErrors
+
+	if member.UserID == 0 {
+		errs = append(errs, FieldError{Field: "user_id", Err: ErrUserNotSet})
+	}
+	if member.Role != RoleEditor && member.Role != RoleViewer {
+		errs = append(errs, FieldError{Field: "role", Err: ErrInvalidRole})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package shoppingcart
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShoppingCart_RoleOf(t *testing.T) {
+	acceptedAt := time.Now()
+	cart := ShoppingCart{
+		ID:     1,
+		UserID: 1,
+		Members: []CartMember{
+			{ShoppingCartID: 1, UserID: 3, Role: RoleEditor, AcceptedAt: &acceptedAt},
+			{ShoppingCartID: 1, UserID: 4, Role: RoleViewer, AcceptedAt: &acceptedAt},
+			{ShoppingCartID: 1, UserID: 5, Role: RoleEditor},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		userID      int64
+		wantRole    string
+		wantCanEdit bool
+	}{
+		{
+			name:        "owner",
+			userID:      1,
+			wantRole:    RoleOwner,
+			wantCanEdit: true,
+		},
+		{
+			name:        "editor",
+			userID:      3,
+			wantRole:    RoleEditor,
+			wantCanEdit: true,
+		},
+		{
+			name:     "viewer",
+			userID:   4,
+			wantRole: RoleViewer,
+		},
+		{
+			name:   "invitation not accepted",
+			userID: 5,
+		},
+		{
+			name:   "stranger",
+			userID: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cart.RoleOf(tt.userID); got != tt.wantRole {
+				t.Errorf("RoleOf() = %q, want %q", got, tt.wantRole)
+			}
+
+			if got := cart.CanEdit(tt.userID); got != tt.wantCanEdit {
+				t.Errorf("CanEdit() = %v, want %v", got, tt.wantCanEdit)
+			}
+		})
+	}
+}
+
+func TestCartMember_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		member  CartMember
+		wantErr error
+	}{
+		{
+			name:    "no user",
+			member:  CartMember{Role: RoleEditor},
+			wantErr: ErrUserNotSet,
+		},
+		{
+			name:    "owner role can't be granted",
+			member:  CartMember{UserID: 3, Role: RoleOwner},
+			wantErr: ErrInvalidRole,
+		},
+		{
+			name:   "everything set",
+			member: CartMember{UserID: 3, Role: RoleViewer},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.member.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
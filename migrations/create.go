@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// template is the content of new migrations
+const template = `-- +goose Up
+
+-- +goose Down
+`
+
+var validName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create creates an empty migration called name for every driver in dir,
+// the migrations directory of the source tree, and returns the paths of the
+// created files. The schemas of all drivers are kept equivalent, so each
+// migration has to be written for all of them.
+func Create(dir, name string, now time.Time) ([]string, error) {
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("migration name %q must consist of lowercase letters, digits and underscores", name)
+	}
+
+	drivers := make([]string, 0, len(dialects))
+	for driver := range dialects {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+
+	fileName := fmt.Sprintf("%s_%s.sql", now.UTC().Format("20060102150405"), name)
+
+	var paths []string
+	for _, driver := range drivers {
+		path := filepath.Join(dir, driver, fileName)
+		if _, err := os.Stat(path); err == nil {
+			return paths, fmt.Errorf("migration %s already exists", path)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(template), 0644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
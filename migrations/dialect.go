@@ -0,0 +1,82 @@
+package migrations
+
+import "time"
+
+// lockName identifies the advisory lock held while migrating, so replicas
+// starting at the same time don't apply migrations twice
+const (
+	lockName = "shoppingcart_migrate"
+	lockKey  = 7311290183541602411
+
+	// lockTimeout is how long to wait for the lock, in seconds
+	lockTimeout = 300
+
+	// lockRetryInterval is how often a lock which is only tried is tried again
+	lockRetryInterval = time.Second
+)
+
+// dialect holds the queries on the version table of a database, which are
+// the same as those of goose
+type dialect struct {
+	tableExists   string
+	createTable   string
+	insertVersion string
+	deleteVersion string
+
+	// lock and unlock hold the advisory lock, empty if the database has none.
+	// If tryLock is set, lock returns right away and is retried until
+	// lockTimeout, otherwise it waits for the lock itself.
+	lock       string
+	tryLock    bool
+	lockArgs   []interface{}
+	unlock     string
+	unlockArgs []interface{}
+}
+
+var dialects = map[string]dialect{
+	"mysql": {
+		tableExists: "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'goose_db_version'",
+		createTable: `CREATE TABLE IF NOT EXISTS goose_db_version (
+			id serial NOT NULL,
+			version_id bigint NOT NULL,
+			is_applied boolean NOT NULL,
+			tstamp timestamp NULL default now(),
+			PRIMARY KEY(id)
+		)`,
+		insertVersion: "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?)",
+		deleteVersion: "DELETE FROM goose_db_version WHERE version_id = ?",
+		lock:          "SELECT GET_LOCK(?, ?) = 1",
+		lockArgs:      []interface{}{lockName, lockTimeout},
+		unlock:        "SELECT RELEASE_LOCK(?) = 1",
+		unlockArgs:    []interface{}{lockName},
+	},
+	"postgres": {
+		tableExists: "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'goose_db_version'",
+		createTable: `CREATE TABLE IF NOT EXISTS goose_db_version (
+			id serial NOT NULL,
+			version_id bigint NOT NULL,
+			is_applied boolean NOT NULL,
+			tstamp timestamp NULL default now(),
+			PRIMARY KEY(id)
+		)`,
+		insertVersion: "INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, $2)",
+		deleteVersion: "DELETE FROM goose_db_version WHERE version_id = $1",
+		lock:          "SELECT pg_try_advisory_lock($1)",
+		tryLock:       true,
+		lockArgs:      []interface{}{lockKey},
+		unlock:        "SELECT pg_advisory_unlock($1)",
+		unlockArgs:    []interface{}{lockKey},
+	},
+	// SQLite is used by a single node, whose writes are serialized anyway
+	"sqlite": {
+		tableExists: "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'",
+		createTable: `CREATE TABLE IF NOT EXISTS goose_db_version (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			version_id INTEGER NOT NULL,
+			is_applied INTEGER NOT NULL,
+			tstamp TIMESTAMP DEFAULT (datetime('now'))
+		)`,
+		insertVersion: "INSERT INTO goose_db_version (version_id, is_applied) VALUES (?, ?)",
+		deleteVersion: "DELETE FROM goose_db_version WHERE version_id = ?",
+	},
+}
@@ -0,0 +1,142 @@
+// Package migrations embeds the schema migrations of the supported databases
+// and applies them. Migrations are goose files, and the applied versions are
+// kept in the goose_db_version table, so databases migrated with goose can be
+// migrated further by the service and the other way round.
+package migrations
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// ErrUnknownDriver is returned for databases without migrations
+var ErrUnknownDriver = errors.New("no migrations for database driver")
+
+// Migration is a single change of the schema
+type Migration struct {
+	Version int64
+	Name    string
+
+	// Up and Down are the statements which apply and revert the migration
+	Up   []string
+	Down []string
+
+	// NoTransaction is set for migrations which can't run in a transaction
+	NoTransaction bool
+}
+
+// load parses the migrations of driver, oldest first
+func load(driver string) ([]Migration, error) {
+	names, err := fs.Glob(files, driver+"/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w %q", ErrUnknownDriver, driver)
+	}
+
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		content, err := files.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, err := parse(path.Base(name), string(content))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parse parses a goose migration from the file name, like
+// 20200418133600_init.sql, and the content of the file
+func parse(name, content string) (Migration, error) {
+	prefix := strings.SplitN(name, "_", 2)[0]
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || len(prefix) == len(name) {
+		return Migration{}, fmt.Errorf("migration %s: file name doesn't start with a version", name)
+	}
+
+	migration := Migration{
+		Version: version,
+		Name:    strings.TrimSuffix(strings.TrimPrefix(name, prefix+"_"), ".sql"),
+	}
+
+	var (
+		statements *[]string
+		statement  strings.Builder
+		inBlock    bool
+		hasUp      bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "-- +goose") {
+			switch annotation := strings.TrimSpace(strings.TrimPrefix(trimmed, "-- +goose")); annotation {
+			case "Up":
+				statements, hasUp = &migration.Up, true
+			case "Down":
+				statements = &migration.Down
+			case "StatementBegin":
+				inBlock = true
+			case "StatementEnd":
+				inBlock = false
+				if statements != nil {
+					*statements = append(*statements, statement.String())
+				}
+				statement.Reset()
+			case "NO TRANSACTION":
+				migration.NoTransaction = true
+			default:
+				return Migration{}, fmt.Errorf("migration %s: unknown annotation %q", name, annotation)
+			}
+			continue
+		}
+
+		if statements == nil || (!inBlock && (trimmed == "" || strings.HasPrefix(trimmed, "--"))) {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+
+		// Outside of blocks statements end with a semicolon at the end of a line
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			*statements = append(*statements, statement.String())
+			statement.Reset()
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Migration{}, fmt.Errorf("migration %s: %w", name, err)
+	}
+	if strings.TrimSpace(statement.String()) != "" {
+		return Migration{}, fmt.Errorf("migration %s: statement isn't terminated", name)
+	}
+	if !hasUp {
+		return Migration{}, fmt.Errorf("migration %s: no Up section", name)
+	}
+
+	return migration, nil
+}
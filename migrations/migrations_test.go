@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Migration
+		err      bool
+	}{
+		{
+			name: "20200418133600_init.sql",
+			content: `-- +goose Up
+-- Carts of users
+CREATE TABLE cart (
+    id BIGINT NOT NULL
+);
+CREATE INDEX cart_id ON cart (id);
+
+-- +goose Down
+DROP TABLE cart;
+`,
+			expected: Migration{
+				Version: 20200418133600,
+				Name:    "init",
+				Up:      []string{"CREATE TABLE cart (\n    id BIGINT NOT NULL\n);\n", "CREATE INDEX cart_id ON cart (id);\n"},
+				Down:    []string{"DROP TABLE cart;\n"},
+			},
+		},
+		{
+			name: "20261019120000_trigger.sql",
+			content: `-- +goose NO TRANSACTION
+-- +goose Up
+-- +goose StatementBegin
+CREATE TRIGGER touch AFTER UPDATE ON cart
+BEGIN
+    UPDATE cart SET updated_at = CURRENT_TIMESTAMP;
+END;
+-- +goose StatementEnd
+`,
+			expected: Migration{
+				Version:       20261019120000,
+				Name:          "trigger",
+				Up:            []string{"CREATE TRIGGER touch AFTER UPDATE ON cart\nBEGIN\n    UPDATE cart SET updated_at = CURRENT_TIMESTAMP;\nEND;\n"},
+				NoTransaction: true,
+			},
+		},
+		{
+			name:     "20261019130000_empty.sql",
+			content:  template,
+			expected: Migration{Version: 20261019130000, Name: "empty"},
+		},
+		{name: "init.sql", content: template, err: true},
+		{name: "20261019140000_down_only.sql", content: "-- +goose Down\nDROP TABLE cart;\n", err: true},
+		{name: "20261019150000_unterminated.sql", content: "-- +goose Up\nCREATE TABLE cart (id BIGINT)\n", err: true},
+		{name: "20261019160000_unknown.sql", content: "-- +goose Sideways\n", err: true},
+	}
+
+	for _, test := range tests {
+		migration, err := parse(test.name, test.content)
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected %s to fail, but got %+v", test.name, migration)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unable to parse %s: %s", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(migration, test.expected) {
+			t.Errorf("Expected %s to be %+v, but got %+v", test.name, test.expected, migration)
+		}
+	}
+}
+
+// The schemas of all drivers are changed by the same versions
+func TestLoad(t *testing.T) {
+	var expected []int64
+	for driver := range dialects {
+		migrations, err := load(driver)
+		if err != nil {
+			t.Fatalf("Unable to load migrations of %s: %s", driver, err)
+		}
+
+		var versions []int64
+		for _, migration := range migrations {
+			if len(migration.Up) == 0 || len(migration.Down) == 0 {
+				t.Errorf("Expected migration %d of %s to have Up and Down statements", migration.Version, driver)
+			}
+			versions = append(versions, migration.Version)
+		}
+
+		if expected == nil {
+			expected = versions
+		}
+		if !reflect.DeepEqual(versions, expected) {
+			t.Errorf("Expected versions %v of %s, but got %v", expected, driver, versions)
+		}
+	}
+
+	if _, err := load("oracle"); err == nil {
+		t.Error("Expected unknown driver to fail")
+	}
+}
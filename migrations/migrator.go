@@ -0,0 +1,272 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoApplied is returned by Down when no migration is applied
+var ErrNoApplied = errors.New("no migration is applied")
+
+// Status tells whether a migration is applied to the database
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded migrations of a driver to a database
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// New returns a Migrator for the database of driver, which is either
+// "mysql", "postgres" or "sqlite"
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	dialect, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownDriver, driver)
+	}
+
+	migrations, err := load(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Status returns the status of all migrations, oldest first
+func (migrator *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrator.migrations))
+	for _, migration := range migrator.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the migrations which aren't applied yet, oldest first
+func (migrator *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies the pending migrations, oldest first, and returns them.
+// The advisory lock of the database is held meanwhile, so only one replica
+// migrates and the others find the migrations applied once they get the lock.
+func (migrator *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	unlock, err := migrator.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := migrator.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range pending {
+		if err := migrator.apply(ctx, migration, migration.Up, true); err != nil {
+			return pending[:i], err
+		}
+	}
+
+	return pending, nil
+}
+
+// Down reverts the latest applied migration and returns it
+func (migrator *Migrator) Down(ctx context.Context) (Migration, error) {
+	unlock, err := migrator.lock(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	defer unlock()
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].Applied {
+			migration := statuses[i].Migration
+			return migration, migrator.apply(ctx, migration, migration.Down, false)
+		}
+	}
+
+	return Migration{}, ErrNoApplied
+}
+
+// apply runs the statements of the migration and records whether it's applied
+func (migrator *Migrator) apply(ctx context.Context, migration Migration, statements []string, up bool) error {
+	record := func(exec func(context.Context, string, ...interface{}) (sql.Result, error)) error {
+		if up {
+			_, err := exec(ctx, migrator.dialect.insertVersion, migration.Version, true)
+			return err
+		}
+		_, err := exec(ctx, migrator.dialect.deleteVersion, migration.Version)
+		return err
+	}
+
+	if migration.NoTransaction {
+		for _, statement := range statements {
+			if _, err := migrator.db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return record(migrator.db.ExecContext)
+	}
+
+	tx, err := migrator.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	if err := record(tx.ExecContext); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// applied returns when the applied migrations were applied by version.
+// Rows of the version table are read newest first, as goose used to record
+// reverted migrations with is_applied false instead of deleting their rows.
+func (migrator *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	var tables int
+	if err := migrator.db.QueryRowContext(ctx, migrator.dialect.tableExists).Scan(&tables); err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]time.Time{}
+	if tables == 0 {
+		return applied, nil
+	}
+
+	rows, err := migrator.db.QueryContext(ctx, "SELECT version_id, is_applied, tstamp FROM goose_db_version ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := map[int64]bool{}
+	for rows.Next() {
+		var (
+			version   int64
+			isApplied bool
+			tstamp    sql.NullTime
+		)
+		if err := rows.Scan(&version, &isApplied, &tstamp); err != nil {
+			return nil, err
+		}
+
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+
+		if isApplied {
+			applied[version] = tstamp.Time
+		}
+	}
+
+	return applied, rows.Err()
+}
+
+// createTable creates the version table unless it exists. Like goose, the
+// table starts with the row of version 0.
+func (migrator *Migrator) createTable(ctx context.Context) error {
+	var tables int
+	if err := migrator.db.QueryRowContext(ctx, migrator.dialect.tableExists).Scan(&tables); err != nil {
+		return err
+	}
+	if tables > 0 {
+		return nil
+	}
+
+	if _, err := migrator.db.ExecContext(ctx, migrator.dialect.createTable); err != nil {
+		return err
+	}
+
+	_, err := migrator.db.ExecContext(ctx, migrator.dialect.insertVersion, 0, true)
+	return err
+}
+
+// lock takes the advisory lock of the database on a dedicated connection,
+// as the lock belongs to the session. It waits for the lock until
+// lockTimeout or until ctx is done. The returned function releases it.
+func (migrator *Migrator) lock(ctx context.Context) (func(), error) {
+	if migrator.dialect.lock == "" {
+		return func() {}, nil
+	}
+
+	conn, err := migrator.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout * time.Second)
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, migrator.dialect.lock, migrator.dialect.lockArgs...).Scan(&locked); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+		if !migrator.dialect.tryLock || time.Now().Add(lockRetryInterval).After(deadline) {
+			conn.Close()
+			return nil, errors.New("timed out waiting for the migration lock")
+		}
+
+		select {
+		case <-time.After(lockRetryInterval):
+		case <-ctx.Done():
+			conn.Close()
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		var released bool
+		_ = conn.QueryRowContext(context.Background(), migrator.dialect.unlock, migrator.dialect.unlockArgs...).Scan(&released)
+		conn.Close()
+	}, nil
+}
@@ -0,0 +1,218 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// newTestDB opens an empty SQLite database in a temporary directory
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "shoppingcart.db")+"?_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("Can't open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	migrator, err := New(db, "sqlite")
+	if err != nil {
+		t.Fatalf("Unable to create migrator: %s", err)
+	}
+	total := len(migrator.migrations)
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Unable to list pending migrations: %s", err)
+	}
+	if len(pending) != total {
+		t.Fatalf("Expected %d pending migrations on an empty database, but got %d", total, len(pending))
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Unable to migrate up: %s", err)
+	}
+	if len(applied) != total {
+		t.Fatalf("Expected %d applied migrations, but got %d", total, len(applied))
+	}
+
+	if pending, _ := migrator.Pending(ctx); len(pending) != 0 {
+		t.Fatalf("Expected no pending migrations, but got %d", len(pending))
+	}
+	if applied, _ := migrator.Up(ctx); len(applied) != 0 {
+		t.Fatalf("Expected nothing to apply, but got %d migrations", len(applied))
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Unable to get status: %s", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt.IsZero() {
+			t.Errorf("Expected migration %d to be applied, but got %+v", status.Version, status)
+		}
+	}
+
+	// Every migration can be reverted, latest first
+	for i := total - 1; i >= 0; i-- {
+		reverted, err := migrator.Down(ctx)
+		if err != nil {
+			t.Fatalf("Unable to migrate down: %s", err)
+		}
+		if reverted.Version != migrator.migrations[i].Version {
+			t.Fatalf("Expected migration %d to be reverted, but got %d", migrator.migrations[i].Version, reverted.Version)
+		}
+	}
+
+	if _, err := migrator.Down(ctx); !errors.Is(err, ErrNoApplied) {
+		t.Fatalf("Expected error %v, but got %v", ErrNoApplied, err)
+	}
+
+	if applied, err := migrator.Up(ctx); err != nil || len(applied) != total {
+		t.Fatalf("Expected %d migrations to be applied again, but got %d (%v)", total, len(applied), err)
+	}
+}
+
+// Databases migrated by goose keep being migrated
+func TestMigrator_gooseVersionTable(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	migrator, err := New(db, "sqlite")
+	if err != nil {
+		t.Fatalf("Unable to create migrator: %s", err)
+	}
+
+	if _, err := db.Exec(dialects["sqlite"].createTable); err != nil {
+		t.Fatalf("Unable to create version table: %s", err)
+	}
+
+	// Older goose versions recorded reverted migrations with is_applied false
+	first, second := migrator.migrations[0], migrator.migrations[1]
+	for _, row := range []struct {
+		version int64
+		applied bool
+	}{{0, true}, {first.Version, true}, {second.Version, true}, {second.Version, false}} {
+		if _, err := db.Exec(dialects["sqlite"].insertVersion, row.version, row.applied); err != nil {
+			t.Fatalf("Unable to insert version: %s", err)
+		}
+	}
+	for _, statement := range first.Up {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Unable to apply migration: %s", err)
+		}
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Unable to list pending migrations: %s", err)
+	}
+	if len(pending) != len(migrator.migrations)-1 || pending[0].Version != second.Version {
+		t.Fatalf("Expected migrations from %d to be pending, but got %+v", second.Version, pending)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Unable to migrate up: %s", err)
+	}
+}
+
+func TestNew_unknownDriver(t *testing.T) {
+	if _, err := New(nil, "oracle"); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("Expected error %v, but got %v", ErrUnknownDriver, err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrations")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for driver := range dialects {
+		if err := os.Mkdir(filepath.Join(dir, driver), 0755); err != nil {
+			t.Fatalf("Unable to create directory: %s", err)
+		}
+	}
+
+	now := time.Date(2026, 10, 19, 19, 0, 0, 0, time.UTC)
+	paths, err := Create(dir, "add_coupons", now)
+	if err != nil {
+		t.Fatalf("Unable to create migration: %s", err)
+	}
+	if len(paths) != len(dialects) {
+		t.Fatalf("Expected a migration for each of %d drivers, but got %v", len(dialects), paths)
+	}
+
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Unable to read migration: %s", err)
+		}
+		if _, err := parse(filepath.Base(path), string(content)); err != nil {
+			t.Errorf("Expected created migration to parse, but got %s", err)
+		}
+		if filepath.Base(path) != "20261019190000_add_coupons.sql" {
+			t.Errorf("Unexpected migration file %s", path)
+		}
+	}
+
+	if _, err := Create(dir, "add_coupons", now); err == nil {
+		t.Error("Expected existing migration to fail")
+	}
+	if _, err := Create(dir, "Add coupons", now); err == nil {
+		t.Error("Expected invalid name to fail")
+	}
+}
+
+func TestMigrator_tryLock(t *testing.T) {
+	db := newTestDB(t)
+
+	// The lock is free once the holder is gone
+	if _, err := db.Exec("CREATE TABLE lock_holder (id INTEGER)"); err != nil {
+		t.Fatalf("Unable to create table: %s", err)
+	}
+	if _, err := db.Exec("INSERT INTO lock_holder (id) VALUES (1)"); err != nil {
+		t.Fatalf("Unable to hold lock: %s", err)
+	}
+	migrator := &Migrator{db: db, dialect: dialect{lock: "SELECT COUNT(*) = 0 FROM lock_holder", tryLock: true, unlock: "SELECT 1"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := migrator.lock(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v while the lock is held, but got %v", context.DeadlineExceeded, err)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		db.Exec("DELETE FROM lock_holder")
+	}()
+
+	unlock, err := migrator.lock(context.Background())
+	if err != nil {
+		t.Fatalf("Unable to take the released lock: %s", err)
+	}
+	unlock()
+}
@@ -0,0 +1,10 @@
+-- +goose Up
+
+ALTER TABLE `shoppingcart_item`
+    ADD COLUMN `variant_id` VARCHAR(64) NOT NULL DEFAULT '' AFTER `product_id`,
+    ADD COLUMN `attributes` JSON NULL AFTER `variant_id`;
+
+-- +goose Down
+ALTER TABLE `shoppingcart_item`
+    DROP COLUMN `attributes`,
+    DROP COLUMN `variant_id`;
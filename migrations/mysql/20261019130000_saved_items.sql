@@ -0,0 +1,10 @@
+-- +goose Up
+
+ALTER TABLE `shoppingcart_item`
+    ADD COLUMN `list` VARCHAR(16) NOT NULL DEFAULT 'cart' AFTER `shoppingcart_id`,
+    ADD INDEX `shoppingcart_id_list` (`shoppingcart_id`, `list`);
+
+-- +goose Down
+ALTER TABLE `shoppingcart_item`
+    DROP INDEX `shoppingcart_id_list`,
+    DROP COLUMN `list`;
@@ -0,0 +1,33 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS `wishlist` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT,
+    `user_id` BIGINT NOT NULL,
+    `name` VARCHAR(100) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (`id`),
+    INDEX `user_id_wishlist_id` (`user_id`, `id`)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `wishlist_item` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT,
+    `wishlist_id` BIGINT NOT NULL,
+    `product_id` BIGINT NOT NULL,
+    `variant_id` VARCHAR(64) NOT NULL DEFAULT '',
+    `attributes` JSON NULL,
+    `quantity` INT UNSIGNED NOT NULL DEFAULT 1,
+    `created_at` TIMESTAMP NOT NULL,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (`id`),
+    INDEX `wishlist_id_item_id` (`wishlist_id`, `id`),
+    FOREIGN KEY (`wishlist_id`) REFERENCES wishlist(id)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+-- +goose Down
+DROP TABLE IF EXISTS `wishlist_item`;
+DROP TABLE IF EXISTS `wishlist`;
@@ -0,0 +1,30 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS `shoppingcart_member` (
+    `shoppingcart_id` BIGINT NOT NULL,
+    `user_id` BIGINT NOT NULL,
+    `role` VARCHAR(16) NOT NULL,
+    `invited_by` BIGINT NOT NULL,
+    `accepted_at` TIMESTAMP NULL,
+    `created_at` TIMESTAMP NOT NULL,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP(),
+    PRIMARY KEY (`shoppingcart_id`, `user_id`),
+    INDEX `user_id_cart_id` (`user_id`, `shoppingcart_id`),
+    FOREIGN KEY (`shoppingcart_id`) REFERENCES shoppingcart(id)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+ALTER TABLE `shoppingcart_item`
+    ADD COLUMN `added_by` BIGINT NOT NULL DEFAULT 0 AFTER `quantity`;
+
+-- Lines of existing carts were added by their owners
+UPDATE `shoppingcart_item` i
+    JOIN `shoppingcart` c ON c.`id` = i.`shoppingcart_id`
+    SET i.`added_by` = c.`user_id`;
+
+-- +goose Down
+ALTER TABLE `shoppingcart_item`
+    DROP COLUMN `added_by`;
+
+DROP TABLE IF EXISTS `shoppingcart_member`;
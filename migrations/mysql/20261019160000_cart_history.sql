@@ -0,0 +1,22 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS `shoppingcart_history` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT,
+    `shoppingcart_id` BIGINT NOT NULL,
+    `action` VARCHAR(32) NOT NULL,
+    `actor_id` BIGINT NOT NULL,
+    `item_id` BIGINT NOT NULL DEFAULT 0,
+    `product_id` BIGINT NOT NULL DEFAULT 0,
+    `variant_id` VARCHAR(64) NOT NULL DEFAULT '',
+    `quantity_before` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    `quantity_after` BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    `request_id` VARCHAR(64) NOT NULL DEFAULT '',
+    `created_at` TIMESTAMP NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `shoppingcart_id_id` (`shoppingcart_id`, `id`)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+-- +goose Down
+DROP TABLE IF EXISTS `shoppingcart_history`;
@@ -0,0 +1,31 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS `shoppingcart_snapshot` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT,
+    `shoppingcart_id` BIGINT NOT NULL,
+    `reason` VARCHAR(16) NOT NULL,
+    `created_by` BIGINT NOT NULL,
+    `created_at` TIMESTAMP NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `shoppingcart_id_id` (`shoppingcart_id`, `id`),
+    FOREIGN KEY (`shoppingcart_id`) REFERENCES shoppingcart(id)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `shoppingcart_snapshot_item` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT,
+    `snapshot_id` BIGINT NOT NULL,
+    `product_id` BIGINT NOT NULL,
+    `variant_id` VARCHAR(64) NOT NULL DEFAULT '',
+    `attributes` JSON NULL,
+    `quantity` BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (`id`),
+    FOREIGN KEY (`snapshot_id`) REFERENCES shoppingcart_snapshot(id)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+-- +goose Down
+DROP TABLE IF EXISTS `shoppingcart_snapshot_item`;
+DROP TABLE IF EXISTS `shoppingcart_snapshot`;
@@ -0,0 +1,30 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS `shoppingcart_event` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT,
+    `shoppingcart_id` BIGINT NOT NULL,
+    `version` BIGINT NOT NULL,
+    `type` VARCHAR(32) NOT NULL,
+    `payload` JSON NOT NULL,
+    `created_at` TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `shoppingcart_id_version` (`shoppingcart_id`, `version`),
+    FOREIGN KEY (`shoppingcart_id`) REFERENCES shoppingcart(id)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+CREATE TABLE IF NOT EXISTS `shoppingcart_event_snapshot` (
+    `shoppingcart_id` BIGINT NOT NULL,
+    `version` BIGINT NOT NULL,
+    `state` MEDIUMBLOB NOT NULL,
+    `created_at` TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (`shoppingcart_id`, `version`),
+    FOREIGN KEY (`shoppingcart_id`) REFERENCES shoppingcart(id)
+)
+DEFAULT CHARACTER SET utf8 COLLATE utf8_unicode_ci
+ENGINE=InnoDB;
+
+-- +goose Down
+DROP TABLE IF EXISTS `shoppingcart_event_snapshot`;
+DROP TABLE IF EXISTS `shoppingcart_event`;
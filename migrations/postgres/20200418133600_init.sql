@@ -0,0 +1,25 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS shoppingcart (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_id_cart_id ON shoppingcart (user_id, id);
+
+CREATE TABLE IF NOT EXISTS shoppingcart_item (
+    id BIGSERIAL PRIMARY KEY,
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS shoppingcart_id_item_id ON shoppingcart_item (shoppingcart_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS shoppingcart_item;
+DROP TABLE IF EXISTS shoppingcart;
@@ -0,0 +1,10 @@
+-- +goose Up
+
+ALTER TABLE shoppingcart_item
+    ADD COLUMN variant_id VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN attributes JSONB NULL;
+
+-- +goose Down
+ALTER TABLE shoppingcart_item
+    DROP COLUMN attributes,
+    DROP COLUMN variant_id;
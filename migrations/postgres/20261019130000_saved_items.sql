@@ -0,0 +1,12 @@
+-- +goose Up
+
+ALTER TABLE shoppingcart_item
+    ADD COLUMN list VARCHAR(16) NOT NULL DEFAULT 'cart';
+
+CREATE INDEX shoppingcart_id_list ON shoppingcart_item (shoppingcart_id, list);
+
+-- +goose Down
+DROP INDEX IF EXISTS shoppingcart_id_list;
+
+ALTER TABLE shoppingcart_item
+    DROP COLUMN list;
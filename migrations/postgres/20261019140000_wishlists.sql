@@ -0,0 +1,28 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS wishlist (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_id_wishlist_id ON wishlist (user_id, id);
+
+CREATE TABLE IF NOT EXISTS wishlist_item (
+    id BIGSERIAL PRIMARY KEY,
+    wishlist_id BIGINT NOT NULL REFERENCES wishlist (id),
+    product_id BIGINT NOT NULL,
+    variant_id VARCHAR(64) NOT NULL DEFAULT '',
+    attributes JSONB NULL,
+    quantity BIGINT NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS wishlist_id_item_id ON wishlist_item (wishlist_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS wishlist_item;
+DROP TABLE IF EXISTS wishlist;
@@ -0,0 +1,29 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS shoppingcart_member (
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    user_id BIGINT NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    invited_by BIGINT NOT NULL,
+    accepted_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shoppingcart_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS member_user_id_cart_id ON shoppingcart_member (user_id, shoppingcart_id);
+
+ALTER TABLE shoppingcart_item
+    ADD COLUMN added_by BIGINT NOT NULL DEFAULT 0;
+
+-- Lines of existing carts were added by their owners
+UPDATE shoppingcart_item i
+    SET added_by = c.user_id
+    FROM shoppingcart c
+    WHERE c.id = i.shoppingcart_id;
+
+-- +goose Down
+ALTER TABLE shoppingcart_item
+    DROP COLUMN added_by;
+
+DROP TABLE IF EXISTS shoppingcart_member;
@@ -0,0 +1,20 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS shoppingcart_history (
+    id BIGSERIAL PRIMARY KEY,
+    shoppingcart_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    actor_id BIGINT NOT NULL,
+    item_id BIGINT NOT NULL DEFAULT 0,
+    product_id BIGINT NOT NULL DEFAULT 0,
+    variant_id VARCHAR(64) NOT NULL DEFAULT '',
+    quantity_before BIGINT NOT NULL DEFAULT 0 CHECK (quantity_before >= 0),
+    quantity_after BIGINT NOT NULL DEFAULT 0 CHECK (quantity_after >= 0),
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS history_shoppingcart_id_id ON shoppingcart_history (shoppingcart_id, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS shoppingcart_history;
@@ -0,0 +1,23 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS shoppingcart_event (
+    id BIGSERIAL PRIMARY KEY,
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    version BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT shoppingcart_id_version UNIQUE (shoppingcart_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS shoppingcart_event_snapshot (
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    version BIGINT NOT NULL,
+    state BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (shoppingcart_id, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS shoppingcart_event_snapshot;
+DROP TABLE IF EXISTS shoppingcart_event;
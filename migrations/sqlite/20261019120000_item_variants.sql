@@ -0,0 +1,22 @@
+-- +goose Up
+
+ALTER TABLE shoppingcart_item ADD COLUMN variant_id VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE shoppingcart_item ADD COLUMN attributes TEXT NULL;
+
+-- +goose Down
+-- Columns can't be dropped by SQLite 3.30, so the table is rebuilt
+CREATE TABLE shoppingcart_item_down (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO shoppingcart_item_down (id, shoppingcart_id, product_id, quantity, created_at, updated_at)
+    SELECT id, shoppingcart_id, product_id, quantity, created_at, updated_at FROM shoppingcart_item;
+
+DROP TABLE shoppingcart_item;
+ALTER TABLE shoppingcart_item_down RENAME TO shoppingcart_item;
+CREATE INDEX shoppingcart_id_item_id ON shoppingcart_item (shoppingcart_id, id);
@@ -0,0 +1,25 @@
+-- +goose Up
+
+ALTER TABLE shoppingcart_item ADD COLUMN list VARCHAR(16) NOT NULL DEFAULT 'cart';
+
+CREATE INDEX shoppingcart_id_list ON shoppingcart_item (shoppingcart_id, list);
+
+-- +goose Down
+-- Columns can't be dropped by SQLite 3.30, so the table is rebuilt
+CREATE TABLE shoppingcart_item_down (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    product_id BIGINT NOT NULL,
+    variant_id VARCHAR(64) NOT NULL DEFAULT '',
+    attributes TEXT NULL,
+    quantity BIGINT NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO shoppingcart_item_down (id, shoppingcart_id, product_id, variant_id, attributes, quantity, created_at, updated_at)
+    SELECT id, shoppingcart_id, product_id, variant_id, attributes, quantity, created_at, updated_at FROM shoppingcart_item;
+
+DROP TABLE shoppingcart_item;
+ALTER TABLE shoppingcart_item_down RENAME TO shoppingcart_item;
+CREATE INDEX shoppingcart_id_item_id ON shoppingcart_item (shoppingcart_id, id);
@@ -0,0 +1,44 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS shoppingcart_member (
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    user_id BIGINT NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    invited_by BIGINT NOT NULL,
+    accepted_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (shoppingcart_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS member_user_id_cart_id ON shoppingcart_member (user_id, shoppingcart_id);
+
+ALTER TABLE shoppingcart_item ADD COLUMN added_by BIGINT NOT NULL DEFAULT 0;
+
+-- Lines of existing carts were added by their owners
+UPDATE shoppingcart_item
+    SET added_by = (SELECT user_id FROM shoppingcart WHERE shoppingcart.id = shoppingcart_item.shoppingcart_id);
+
+-- +goose Down
+-- Columns can't be dropped by SQLite 3.30, so the table is rebuilt
+CREATE TABLE shoppingcart_item_down (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    list VARCHAR(16) NOT NULL DEFAULT 'cart',
+    product_id BIGINT NOT NULL,
+    variant_id VARCHAR(64) NOT NULL DEFAULT '',
+    attributes TEXT NULL,
+    quantity BIGINT NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO shoppingcart_item_down (id, shoppingcart_id, list, product_id, variant_id, attributes, quantity, created_at, updated_at)
+    SELECT id, shoppingcart_id, list, product_id, variant_id, attributes, quantity, created_at, updated_at FROM shoppingcart_item;
+
+DROP TABLE shoppingcart_item;
+ALTER TABLE shoppingcart_item_down RENAME TO shoppingcart_item;
+CREATE INDEX shoppingcart_id_item_id ON shoppingcart_item (shoppingcart_id, id);
+CREATE INDEX shoppingcart_id_list ON shoppingcart_item (shoppingcart_id, list);
+
+DROP TABLE IF EXISTS shoppingcart_member;
@@ -0,0 +1,24 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS shoppingcart_snapshot (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    reason VARCHAR(16) NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS snapshot_shoppingcart_id_id ON shoppingcart_snapshot (shoppingcart_id, id);
+
+CREATE TABLE IF NOT EXISTS shoppingcart_snapshot_item (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    snapshot_id BIGINT NOT NULL REFERENCES shoppingcart_snapshot (id),
+    product_id BIGINT NOT NULL,
+    variant_id VARCHAR(64) NOT NULL DEFAULT '',
+    attributes TEXT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity >= 0)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS shoppingcart_snapshot_item;
+DROP TABLE IF EXISTS shoppingcart_snapshot;
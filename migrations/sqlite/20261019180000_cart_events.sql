@@ -0,0 +1,23 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS shoppingcart_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    version BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    payload TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT shoppingcart_id_version UNIQUE (shoppingcart_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS shoppingcart_event_snapshot (
+    shoppingcart_id BIGINT NOT NULL REFERENCES shoppingcart (id),
+    version BIGINT NOT NULL,
+    state BLOB NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (shoppingcart_id, version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS shoppingcart_event_snapshot;
+DROP TABLE IF EXISTS shoppingcart_event;
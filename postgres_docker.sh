@@ -0,0 +1,7 @@
+#!/usr/bin/env sh
+
+docker run -d -p 127.0.0.1:5432:5432 --name shoppingcart_postgres \
+    -e POSTGRES_DB=shoppingcart \
+    -e POSTGRES_USER=shoppingcart \
+    -e POSTGRES_PASSWORD=secret \
+    postgres:12
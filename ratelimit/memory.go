@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often full buckets are removed from Memory
+const sweepInterval = time.Minute
+
+// Memory keeps the buckets of clients in process, so every instance of the
+// service limits clients on its own. Buckets which are full again are removed.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+var _ Store = (*Memory)(nil)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// NewMemory returns a new Memory without buckets
+func NewMemory() *Memory {
+	return &Memory{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take takes a token from the bucket of the key, if there is one
+func (memory *Memory) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	memory.mu.Lock()
+	defer memory.mu.Unlock()
+
+	now := memory.now()
+	memory.sweep(now)
+
+	b, ok := memory.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		memory.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	if b.tokens < 1 {
+		return result(false, b.tokens, limit), nil
+	}
+
+	b.tokens--
+	return result(true, b.tokens, limit), nil
+}
+
+// refill adds the tokens earned since the last update
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*b.limit.Rate)
+	b.updated = now
+}
+
+// sweep removes the buckets which are full again, at most every sweepInterval
+func (memory *Memory) sweep(now time.Time) {
+	if now.Sub(memory.lastSweep) < sweepInterval {
+		return
+	}
+	memory.lastSweep = now
+
+	for key, b := range memory.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(memory.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package ratelimit limits the rate of requests per client with token
+// buckets. Reads and writes have separate limits, so a client writing a lot
+// can still read its carts.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Kinds of requests, which are limited separately
+const (
+	Read  = "read"
+	Write = "write"
+)
+
+// UserClient returns the client of an authenticated user. The HTTP and gRPC
+// APIs share it, so a user is limited across both.
+func UserClient(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// Limit allows Burst requests at once and Rate requests per second on average.
+// A zero Rate disables the limit. A Burst below one, which would refuse every
+// request, allows Rate requests at once, but at least one.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the state of the bucket of a client after taking a token
+type Result struct {
+	// Allowed is set if the request may be served
+	Allowed bool
+	// Limit is the size of the bucket
+	Limit int
+	// Remaining is the number of requests the client may send at once
+	Remaining int
+	// RetryAfter is how long the client has to wait for its next request
+	RetryAfter time.Duration
+	// Reset is how long it takes until the bucket is full again
+	Reset time.Duration
+}
+
+// Store describes an interface to the buckets of clients. Besides the
+// in-process Memory, it can be implemented by a store shared between
+// instances, so clients are limited across them.
+type Store interface {
+	// Take takes a token from the bucket of the key, if there is one
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Config defines the limits of reads and writes
+type Config struct {
+	Read  Limit
+	Write Limit
+}
+
+// Limiter limits the requests of clients, by the kind of request.
+type Limiter struct {
+	store  Store
+	limits map[string]Limit
+}
+
+// New returns a new Limiter keeping the buckets of clients in store
+func New(store Store, config Config) *Limiter {
+	return &Limiter{
+		store:  store,
+		limits: map[string]Limit{Read: config.Read.withBurst(), Write: config.Write.withBurst()},
+	}
+}
+
+// withBurst returns the limit with a burst of at least one request
+func (limit Limit) withBurst() Limit {
+	if limit.Burst < 1 {
+		limit.Burst = int(math.Max(1, math.Ceil(limit.Rate)))
+	}
+
+	return limit
+}
+
+// Allow takes a token from the bucket of the client for a request of kind.
+// Requests of kinds without a limit are always allowed.
+func (limiter *Limiter) Allow(ctx context.Context, client, kind string) (Result, error) {
+	limit := limiter.limits[kind]
+	if limit.Rate <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	return limiter.store.Take(ctx, kind+":"+client, limit)
+}
+
+// result returns the state of a bucket holding tokens
+func result(allowed bool, tokens float64, limit Limit) Result {
+	r := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+
+	if tokens < 1 {
+		r.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return r
+}
+
+// seconds converts seconds to a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
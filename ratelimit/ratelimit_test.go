@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory_Take(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+
+	now := time.Now()
+	memory.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 2, Burst: 3}
+
+	tests := []struct {
+		name  string
+		key   string
+		after time.Duration
+		want  Result
+	}{
+		{
+			name: "first request",
+			key:  "a",
+			want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond},
+		},
+		{
+			name: "burst",
+			key:  "a",
+			want: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: time.Second},
+		},
+		{
+			name: "last token",
+			key:  "a",
+			want: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond, RetryAfter: 500 * time.Millisecond},
+		},
+		{
+			name: "empty bucket",
+			key:  "a",
+			want: Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond, RetryAfter: 500 * time.Millisecond},
+		},
+		{
+			name: "another key",
+			key:  "b",
+			want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond},
+		},
+		{
+			name:  "refilled",
+			key:   "a",
+			after: 500 * time.Millisecond,
+			want:  Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 1500 * time.Millisecond, RetryAfter: 500 * time.Millisecond},
+		},
+		{
+			name:  "refilled up to the burst",
+			key:   "a",
+			after: time.Hour,
+			want:  Result{Allowed: true, Limit: 3, Remaining: 2, Reset: 500 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.after)
+
+			got, err := memory.Take(ctx, tt.key, limit)
+			if err != nil {
+				t.Fatalf("Take() unexpected error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("Expected %+v, but got %+v", tt.want, got)
+			}
+		})
+	}
+
+	// Buckets which are full again are removed
+	if len(memory.buckets) != 1 {
+		t.Fatalf("Expected %d bucket, but got %d", 1, len(memory.buckets))
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	limiter := New(NewMemory(), Config{
+		Read:  Limit{Rate: 1, Burst: 1},
+		Write: Limit{},
+	})
+
+	if result, _ := limiter.Allow(ctx, "user:1", Read); !result.Allowed {
+		t.Fatalf("Expected first read to be allowed")
+	}
+
+	if result, _ := limiter.Allow(ctx, "user:1", Read); result.Allowed {
+		t.Fatalf("Expected second read to be limited")
+	}
+
+	// Reads and writes have separate buckets
+	for i := 0; i < 5; i++ {
+		if result, _ := limiter.Allow(ctx, "user:1", Write); !result.Allowed {
+			t.Fatalf("Expected write %d to be allowed without a limit", i)
+		}
+	}
+}
+
+func TestLimiter_Allow_noBurst(t *testing.T) {
+	ctx := context.Background()
+	limiter := New(NewMemory(), Config{
+		Read:  Limit{Rate: 2},
+		Write: Limit{Rate: 0.5},
+	})
+
+	for i := 0; i < 2; i++ {
+		if result, _ := limiter.Allow(ctx, "user:1", Read); !result.Allowed || result.Limit != 2 {
+			t.Fatalf("Expected read %d to be allowed with limit %d, but got %+v", i, 2, result)
+		}
+	}
+
+	if result, _ := limiter.Allow(ctx, "user:1", Write); !result.Allowed || result.Limit != 1 {
+		t.Fatalf("Expected write to be allowed with limit %d, but got %+v", 1, result)
+	}
+}
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/handler"
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type key int
+
+var userKey key
+
+// authUser returns the authenticated user.
+func authUser(ctx context.Context) (auth.User, error) {
+	user, ok := ctx.Value(userKey).(auth.User)
+	if !ok {
+		return user, shoppingcart.ErrNoPermission
+	}
+
+	return user, nil
+}
+
+// authInterceptor authenticates user using auth service. Credentials are taken
+// from the "authorization" metadata entry, in the same format as the HTTP
+// Authorization header.
+func (server *Server) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("authorization")) == 0 {
+		return nil, Error(shoppingcart.ErrNoPermission)
+	}
+
+	user, err := handler.ParseBasicAuth(md.Get("authorization")[0])
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	user, err = server.authService.Authenticate(ctx, user)
+	if err != nil {
+		return nil, Error(shoppingcart.ErrNoPermission)
+	}
+
+	return next(context.WithValue(ctx, userKey, user), req)
+}
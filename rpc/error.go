@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bugimetal/shoppingcart"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCodes maps commonly returned errors to gRPC status codes
+var ErrorCodes = map[error]codes.Code{
+	shoppingcart.ErrValidation: codes.InvalidArgument,
+
+	// Request
+	context.Canceled:                  codes.Canceled,
+	context.DeadlineExceeded:          codes.DeadlineExceeded,
+	shoppingcart.ErrStorageTimeout:    codes.Unavailable,
+	shoppingcart.ErrRateLimitExceeded: codes.ResourceExhausted,
+	shoppingcart.ErrNotSupported:      codes.Unimplemented,
+
+	// Shopping cart
+	shoppingcart.ErrUserNotSet:     codes.InvalidArgument,
+	shoppingcart.ErrCartNotFound:   codes.NotFound,
+	shoppingcart.ErrCartHasNoItems: codes.FailedPrecondition,
+	shoppingcart.ErrNoPermission:   codes.Unauthenticated,
+	shoppingcart.ErrForbidden:      codes.PermissionDenied,
+
+	// Shopping cart member
+	shoppingcart.ErrMemberNotFound:      codes.NotFound,
+	shoppingcart.ErrMemberAlreadyExists: codes.AlreadyExists,
+	shoppingcart.ErrInvalidRole:         codes.InvalidArgument,
+
+	// Snapshot
+	shoppingcart.ErrSnapshotNotFound: codes.NotFound,
+
+	// Shopping cart item
+	shoppingcart.ErrCartItemNoProductSet:  codes.InvalidArgument,
+	shoppingcart.ErrCartItemNoQuantitySet: codes.InvalidArgument,
+	shoppingcart.ErrCartItemNotFound:      codes.NotFound,
+	shoppingcart.ErrCartItemAlreadyExists: codes.AlreadyExists,
+	shoppingcart.ErrQuantityLimitExceeded: codes.FailedPrecondition,
+	shoppingcart.ErrOutOfStock:            codes.ResourceExhausted,
+}
+
+// statusCode returns the gRPC status code that is appropriate for the specified
+// error, following wrapped errors.
+func statusCode(err error) codes.Code {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		for known, code := range ErrorCodes {
+			if e == known {
+				return code
+			}
+		}
+	}
+
+	for known, code := range ErrorCodes {
+		if errors.Is(err, known) {
+			return code
+		}
+	}
+
+	return codes.Internal
+}
+
+// Error converts err into a gRPC status error.
+func Error(err error) error {
+	return status.Error(statusCode(err), err.Error())
+}
@@ -0,0 +1,4 @@
+// Package pb contains the protocol buffer definitions of the shopping cart gRPC API.
+package pb
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. shoppingcart.proto
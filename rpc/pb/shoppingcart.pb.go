@@ -0,0 +1,2218 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: shoppingcart.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ShoppingCart struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               int64                `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Items                []*ShoppingCartItem  `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	SavedItems           []*ShoppingCartItem  `protobuf:"bytes,6,rep,name=saved_items,json=savedItems,proto3" json:"saved_items,omitempty"`
+	Members              []*CartMember        `protobuf:"bytes,7,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ShoppingCart) Reset()         { *m = ShoppingCart{} }
+func (m *ShoppingCart) String() string { return proto.CompactTextString(m) }
+func (*ShoppingCart) ProtoMessage()    {}
+func (*ShoppingCart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{0}
+}
+
+func (m *ShoppingCart) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShoppingCart.Unmarshal(m, b)
+}
+func (m *ShoppingCart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShoppingCart.Marshal(b, m, deterministic)
+}
+func (m *ShoppingCart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShoppingCart.Merge(m, src)
+}
+func (m *ShoppingCart) XXX_Size() int {
+	return xxx_messageInfo_ShoppingCart.Size(m)
+}
+func (m *ShoppingCart) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShoppingCart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShoppingCart proto.InternalMessageInfo
+
+func (m *ShoppingCart) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ShoppingCart) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *ShoppingCart) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *ShoppingCart) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *ShoppingCart) GetItems() []*ShoppingCartItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *ShoppingCart) GetSavedItems() []*ShoppingCartItem {
+	if m != nil {
+		return m.SavedItems
+	}
+	return nil
+}
+
+func (m *ShoppingCart) GetMembers() []*CartMember {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+type ShoppingCartItem struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShoppingcartId       int64                `protobuf:"varint,2,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	ProductId            int64                `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity             uint64               `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	VariantId            string               `protobuf:"bytes,7,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Attributes           map[string]string    `protobuf:"bytes,8,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	AddedBy              int64                `protobuf:"varint,9,opt,name=added_by,json=addedBy,proto3" json:"added_by,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ShoppingCartItem) Reset()         { *m = ShoppingCartItem{} }
+func (m *ShoppingCartItem) String() string { return proto.CompactTextString(m) }
+func (*ShoppingCartItem) ProtoMessage()    {}
+func (*ShoppingCartItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{1}
+}
+
+func (m *ShoppingCartItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShoppingCartItem.Unmarshal(m, b)
+}
+func (m *ShoppingCartItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShoppingCartItem.Marshal(b, m, deterministic)
+}
+func (m *ShoppingCartItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShoppingCartItem.Merge(m, src)
+}
+func (m *ShoppingCartItem) XXX_Size() int {
+	return xxx_messageInfo_ShoppingCartItem.Size(m)
+}
+func (m *ShoppingCartItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShoppingCartItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShoppingCartItem proto.InternalMessageInfo
+
+func (m *ShoppingCartItem) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ShoppingCartItem) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *ShoppingCartItem) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *ShoppingCartItem) GetQuantity() uint64 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *ShoppingCartItem) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *ShoppingCartItem) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+func (m *ShoppingCartItem) GetVariantId() string {
+	if m != nil {
+		return m.VariantId
+	}
+	return ""
+}
+
+func (m *ShoppingCartItem) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *ShoppingCartItem) GetAddedBy() int64 {
+	if m != nil {
+		return m.AddedBy
+	}
+	return 0
+}
+
+type CartMember struct {
+	ShoppingcartId       int64                `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	UserId               int64                `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role                 string               `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	InvitedBy            int64                `protobuf:"varint,4,opt,name=invited_by,json=invitedBy,proto3" json:"invited_by,omitempty"`
+	AcceptedAt           *timestamp.Timestamp `protobuf:"bytes,5,opt,name=accepted_at,json=acceptedAt,proto3" json:"accepted_at,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt            *timestamp.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CartMember) Reset()         { *m = CartMember{} }
+func (m *CartMember) String() string { return proto.CompactTextString(m) }
+func (*CartMember) ProtoMessage()    {}
+func (*CartMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{2}
+}
+
+func (m *CartMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CartMember.Unmarshal(m, b)
+}
+func (m *CartMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CartMember.Marshal(b, m, deterministic)
+}
+func (m *CartMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CartMember.Merge(m, src)
+}
+func (m *CartMember) XXX_Size() int {
+	return xxx_messageInfo_CartMember.Size(m)
+}
+func (m *CartMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_CartMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CartMember proto.InternalMessageInfo
+
+func (m *CartMember) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *CartMember) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *CartMember) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *CartMember) GetInvitedBy() int64 {
+	if m != nil {
+		return m.InvitedBy
+	}
+	return 0
+}
+
+func (m *CartMember) GetAcceptedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.AcceptedAt
+	}
+	return nil
+}
+
+func (m *CartMember) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *CartMember) GetUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
+type HistoryEntry struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShoppingcartId       int64                `protobuf:"varint,2,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	Action               string               `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	ActorId              int64                `protobuf:"varint,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ItemId               int64                `protobuf:"varint,5,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	ProductId            int64                `protobuf:"varint,6,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId            string               `protobuf:"bytes,7,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	QuantityBefore       uint64               `protobuf:"varint,8,opt,name=quantity_before,json=quantityBefore,proto3" json:"quantity_before,omitempty"`
+	QuantityAfter        uint64               `protobuf:"varint,9,opt,name=quantity_after,json=quantityAfter,proto3" json:"quantity_after,omitempty"`
+	RequestId            string               `protobuf:"bytes,10,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HistoryEntry) Reset()         { *m = HistoryEntry{} }
+func (m *HistoryEntry) String() string { return proto.CompactTextString(m) }
+func (*HistoryEntry) ProtoMessage()    {}
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{3}
+}
+
+func (m *HistoryEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryEntry.Unmarshal(m, b)
+}
+func (m *HistoryEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryEntry.Marshal(b, m, deterministic)
+}
+func (m *HistoryEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryEntry.Merge(m, src)
+}
+func (m *HistoryEntry) XXX_Size() int {
+	return xxx_messageInfo_HistoryEntry.Size(m)
+}
+func (m *HistoryEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryEntry proto.InternalMessageInfo
+
+func (m *HistoryEntry) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *HistoryEntry) GetActorId() int64 {
+	if m != nil {
+		return m.ActorId
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetItemId() int64 {
+	if m != nil {
+		return m.ItemId
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetVariantId() string {
+	if m != nil {
+		return m.VariantId
+	}
+	return ""
+}
+
+func (m *HistoryEntry) GetQuantityBefore() uint64 {
+	if m != nil {
+		return m.QuantityBefore
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetQuantityAfter() uint64 {
+	if m != nil {
+		return m.QuantityAfter
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *HistoryEntry) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+type Snapshot struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShoppingcartId       int64                `protobuf:"varint,2,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	Reason               string               `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedBy            int64                `protobuf:"varint,4,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Items                []*SnapshotItem      `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Snapshot) Reset()         { *m = Snapshot{} }
+func (m *Snapshot) String() string { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()    {}
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{4}
+}
+
+func (m *Snapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Snapshot.Unmarshal(m, b)
+}
+func (m *Snapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Snapshot.Marshal(b, m, deterministic)
+}
+func (m *Snapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Snapshot.Merge(m, src)
+}
+func (m *Snapshot) XXX_Size() int {
+	return xxx_messageInfo_Snapshot.Size(m)
+}
+func (m *Snapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Snapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Snapshot proto.InternalMessageInfo
+
+func (m *Snapshot) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Snapshot) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *Snapshot) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Snapshot) GetCreatedBy() int64 {
+	if m != nil {
+		return m.CreatedBy
+	}
+	return 0
+}
+
+func (m *Snapshot) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Snapshot) GetItems() []*SnapshotItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+type SnapshotItem struct {
+	Id                   int64             `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SnapshotId           int64             `protobuf:"varint,2,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	ProductId            int64             `protobuf:"varint,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId            string            `protobuf:"bytes,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Attributes           map[string]string `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Quantity             uint64            `protobuf:"varint,6,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SnapshotItem) Reset()         { *m = SnapshotItem{} }
+func (m *SnapshotItem) String() string { return proto.CompactTextString(m) }
+func (*SnapshotItem) ProtoMessage()    {}
+func (*SnapshotItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{5}
+}
+
+func (m *SnapshotItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotItem.Unmarshal(m, b)
+}
+func (m *SnapshotItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotItem.Marshal(b, m, deterministic)
+}
+func (m *SnapshotItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotItem.Merge(m, src)
+}
+func (m *SnapshotItem) XXX_Size() int {
+	return xxx_messageInfo_SnapshotItem.Size(m)
+}
+func (m *SnapshotItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotItem proto.InternalMessageInfo
+
+func (m *SnapshotItem) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *SnapshotItem) GetSnapshotId() int64 {
+	if m != nil {
+		return m.SnapshotId
+	}
+	return 0
+}
+
+func (m *SnapshotItem) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *SnapshotItem) GetVariantId() string {
+	if m != nil {
+		return m.VariantId
+	}
+	return ""
+}
+
+func (m *SnapshotItem) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *SnapshotItem) GetQuantity() uint64 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+type CreateRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
+func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateRequest) ProtoMessage()    {}
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{6}
+}
+
+func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRequest.Unmarshal(m, b)
+}
+func (m *CreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRequest.Merge(m, src)
+}
+func (m *CreateRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateRequest.Size(m)
+}
+func (m *CreateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateRequest proto.InternalMessageInfo
+
+type GetRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRequest) Reset()         { *m = GetRequest{} }
+func (m *GetRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRequest) ProtoMessage()    {}
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{7}
+}
+
+func (m *GetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
+}
+func (m *GetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRequest.Merge(m, src)
+}
+func (m *GetRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRequest.Size(m)
+}
+func (m *GetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRequest proto.InternalMessageInfo
+
+func (m *GetRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+type EmptyRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EmptyRequest) Reset()         { *m = EmptyRequest{} }
+func (m *EmptyRequest) String() string { return proto.CompactTextString(m) }
+func (*EmptyRequest) ProtoMessage()    {}
+func (*EmptyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{8}
+}
+
+func (m *EmptyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EmptyRequest.Unmarshal(m, b)
+}
+func (m *EmptyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EmptyRequest.Marshal(b, m, deterministic)
+}
+func (m *EmptyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EmptyRequest.Merge(m, src)
+}
+func (m *EmptyRequest) XXX_Size() int {
+	return xxx_messageInfo_EmptyRequest.Size(m)
+}
+func (m *EmptyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EmptyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EmptyRequest proto.InternalMessageInfo
+
+func (m *EmptyRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+type CheckoutRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckoutRequest) Reset()         { *m = CheckoutRequest{} }
+func (m *CheckoutRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckoutRequest) ProtoMessage()    {}
+func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{9}
+}
+
+func (m *CheckoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckoutRequest.Unmarshal(m, b)
+}
+func (m *CheckoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckoutRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckoutRequest.Merge(m, src)
+}
+func (m *CheckoutRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckoutRequest.Size(m)
+}
+func (m *CheckoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckoutRequest proto.InternalMessageInfo
+
+func (m *CheckoutRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+type AddProductRequest struct {
+	ShoppingcartId       int64             `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	ProductId            int64             `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity             uint64            `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId            string            `protobuf:"bytes,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Attributes           map[string]string `protobuf:"bytes,5,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AddProductRequest) Reset()         { *m = AddProductRequest{} }
+func (m *AddProductRequest) String() string { return proto.CompactTextString(m) }
+func (*AddProductRequest) ProtoMessage()    {}
+func (*AddProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{10}
+}
+
+func (m *AddProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddProductRequest.Unmarshal(m, b)
+}
+func (m *AddProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddProductRequest.Marshal(b, m, deterministic)
+}
+func (m *AddProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddProductRequest.Merge(m, src)
+}
+func (m *AddProductRequest) XXX_Size() int {
+	return xxx_messageInfo_AddProductRequest.Size(m)
+}
+func (m *AddProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddProductRequest proto.InternalMessageInfo
+
+func (m *AddProductRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *AddProductRequest) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+func (m *AddProductRequest) GetQuantity() uint64 {
+	if m != nil {
+		return m.Quantity
+	}
+	return 0
+}
+
+func (m *AddProductRequest) GetVariantId() string {
+	if m != nil {
+		return m.VariantId
+	}
+	return ""
+}
+
+func (m *AddProductRequest) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+type RemoveProductRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	ProductId            int64    `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveProductRequest) Reset()         { *m = RemoveProductRequest{} }
+func (m *RemoveProductRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveProductRequest) ProtoMessage()    {}
+func (*RemoveProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{11}
+}
+
+func (m *RemoveProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveProductRequest.Unmarshal(m, b)
+}
+func (m *RemoveProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveProductRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveProductRequest.Merge(m, src)
+}
+func (m *RemoveProductRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveProductRequest.Size(m)
+}
+func (m *RemoveProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveProductRequest proto.InternalMessageInfo
+
+func (m *RemoveProductRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *RemoveProductRequest) GetProductId() int64 {
+	if m != nil {
+		return m.ProductId
+	}
+	return 0
+}
+
+type RemoveItemRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	ItemId               int64    `protobuf:"varint,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveItemRequest) Reset()         { *m = RemoveItemRequest{} }
+func (m *RemoveItemRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveItemRequest) ProtoMessage()    {}
+func (*RemoveItemRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{12}
+}
+
+func (m *RemoveItemRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveItemRequest.Unmarshal(m, b)
+}
+func (m *RemoveItemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveItemRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveItemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveItemRequest.Merge(m, src)
+}
+func (m *RemoveItemRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveItemRequest.Size(m)
+}
+func (m *RemoveItemRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveItemRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveItemRequest proto.InternalMessageInfo
+
+func (m *RemoveItemRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *RemoveItemRequest) GetItemId() int64 {
+	if m != nil {
+		return m.ItemId
+	}
+	return 0
+}
+
+type MoveItemRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	ItemId               int64    `protobuf:"varint,2,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MoveItemRequest) Reset()         { *m = MoveItemRequest{} }
+func (m *MoveItemRequest) String() string { return proto.CompactTextString(m) }
+func (*MoveItemRequest) ProtoMessage()    {}
+func (*MoveItemRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{13}
+}
+
+func (m *MoveItemRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveItemRequest.Unmarshal(m, b)
+}
+func (m *MoveItemRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveItemRequest.Marshal(b, m, deterministic)
+}
+func (m *MoveItemRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveItemRequest.Merge(m, src)
+}
+func (m *MoveItemRequest) XXX_Size() int {
+	return xxx_messageInfo_MoveItemRequest.Size(m)
+}
+func (m *MoveItemRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveItemRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveItemRequest proto.InternalMessageInfo
+
+func (m *MoveItemRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *MoveItemRequest) GetItemId() int64 {
+	if m != nil {
+		return m.ItemId
+	}
+	return 0
+}
+
+type InviteRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	UserId               int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role                 string   `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InviteRequest) Reset()         { *m = InviteRequest{} }
+func (m *InviteRequest) String() string { return proto.CompactTextString(m) }
+func (*InviteRequest) ProtoMessage()    {}
+func (*InviteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{14}
+}
+
+func (m *InviteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InviteRequest.Unmarshal(m, b)
+}
+func (m *InviteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InviteRequest.Marshal(b, m, deterministic)
+}
+func (m *InviteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InviteRequest.Merge(m, src)
+}
+func (m *InviteRequest) XXX_Size() int {
+	return xxx_messageInfo_InviteRequest.Size(m)
+}
+func (m *InviteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InviteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InviteRequest proto.InternalMessageInfo
+
+func (m *InviteRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *InviteRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *InviteRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type AcceptInvitationRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcceptInvitationRequest) Reset()         { *m = AcceptInvitationRequest{} }
+func (m *AcceptInvitationRequest) String() string { return proto.CompactTextString(m) }
+func (*AcceptInvitationRequest) ProtoMessage()    {}
+func (*AcceptInvitationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{15}
+}
+
+func (m *AcceptInvitationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptInvitationRequest.Unmarshal(m, b)
+}
+func (m *AcceptInvitationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptInvitationRequest.Marshal(b, m, deterministic)
+}
+func (m *AcceptInvitationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptInvitationRequest.Merge(m, src)
+}
+func (m *AcceptInvitationRequest) XXX_Size() int {
+	return xxx_messageInfo_AcceptInvitationRequest.Size(m)
+}
+func (m *AcceptInvitationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptInvitationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptInvitationRequest proto.InternalMessageInfo
+
+func (m *AcceptInvitationRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+type RevokeMemberRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	UserId               int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeMemberRequest) Reset()         { *m = RevokeMemberRequest{} }
+func (m *RevokeMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeMemberRequest) ProtoMessage()    {}
+func (*RevokeMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{16}
+}
+
+func (m *RevokeMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeMemberRequest.Unmarshal(m, b)
+}
+func (m *RevokeMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeMemberRequest.Merge(m, src)
+}
+func (m *RevokeMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeMemberRequest.Size(m)
+}
+func (m *RevokeMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeMemberRequest proto.InternalMessageInfo
+
+func (m *RevokeMemberRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *RevokeMemberRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type HistoryRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryRequest) Reset()         { *m = HistoryRequest{} }
+func (m *HistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*HistoryRequest) ProtoMessage()    {}
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{17}
+}
+
+func (m *HistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryRequest.Unmarshal(m, b)
+}
+func (m *HistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *HistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryRequest.Merge(m, src)
+}
+func (m *HistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_HistoryRequest.Size(m)
+}
+func (m *HistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryRequest proto.InternalMessageInfo
+
+func (m *HistoryRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *HistoryRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *HistoryRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type HistoryResponse struct {
+	Entries              []*HistoryEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *HistoryResponse) Reset()         { *m = HistoryResponse{} }
+func (m *HistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*HistoryResponse) ProtoMessage()    {}
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{18}
+}
+
+func (m *HistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryResponse.Unmarshal(m, b)
+}
+func (m *HistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *HistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryResponse.Merge(m, src)
+}
+func (m *HistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_HistoryResponse.Size(m)
+}
+func (m *HistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryResponse proto.InternalMessageInfo
+
+func (m *HistoryResponse) GetEntries() []*HistoryEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type SnapshotRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotRequest) Reset()         { *m = SnapshotRequest{} }
+func (m *SnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequest) ProtoMessage()    {}
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{19}
+}
+
+func (m *SnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequest.Unmarshal(m, b)
+}
+func (m *SnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequest.Merge(m, src)
+}
+func (m *SnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequest.Size(m)
+}
+func (m *SnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequest proto.InternalMessageInfo
+
+func (m *SnapshotRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+type ListSnapshotsRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSnapshotsRequest) Reset()         { *m = ListSnapshotsRequest{} }
+func (m *ListSnapshotsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSnapshotsRequest) ProtoMessage()    {}
+func (*ListSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{20}
+}
+
+func (m *ListSnapshotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSnapshotsRequest.Unmarshal(m, b)
+}
+func (m *ListSnapshotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSnapshotsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSnapshotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSnapshotsRequest.Merge(m, src)
+}
+func (m *ListSnapshotsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSnapshotsRequest.Size(m)
+}
+func (m *ListSnapshotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSnapshotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSnapshotsRequest proto.InternalMessageInfo
+
+func (m *ListSnapshotsRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+type ListSnapshotsResponse struct {
+	Snapshots            []*Snapshot `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListSnapshotsResponse) Reset()         { *m = ListSnapshotsResponse{} }
+func (m *ListSnapshotsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSnapshotsResponse) ProtoMessage()    {}
+func (*ListSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{21}
+}
+
+func (m *ListSnapshotsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSnapshotsResponse.Unmarshal(m, b)
+}
+func (m *ListSnapshotsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSnapshotsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSnapshotsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSnapshotsResponse.Merge(m, src)
+}
+func (m *ListSnapshotsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSnapshotsResponse.Size(m)
+}
+func (m *ListSnapshotsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSnapshotsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSnapshotsResponse proto.InternalMessageInfo
+
+func (m *ListSnapshotsResponse) GetSnapshots() []*Snapshot {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+type GetSnapshotRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	SnapshotId           int64    `protobuf:"varint,2,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSnapshotRequest) Reset()         { *m = GetSnapshotRequest{} }
+func (m *GetSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSnapshotRequest) ProtoMessage()    {}
+func (*GetSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{22}
+}
+
+func (m *GetSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSnapshotRequest.Unmarshal(m, b)
+}
+func (m *GetSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *GetSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSnapshotRequest.Merge(m, src)
+}
+func (m *GetSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSnapshotRequest.Size(m)
+}
+func (m *GetSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSnapshotRequest proto.InternalMessageInfo
+
+func (m *GetSnapshotRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *GetSnapshotRequest) GetSnapshotId() int64 {
+	if m != nil {
+		return m.SnapshotId
+	}
+	return 0
+}
+
+type RestoreRequest struct {
+	ShoppingcartId       int64    `protobuf:"varint,1,opt,name=shoppingcart_id,json=shoppingcartId,proto3" json:"shoppingcart_id,omitempty"`
+	SnapshotId           int64    `protobuf:"varint,2,opt,name=snapshot_id,json=snapshotId,proto3" json:"snapshot_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_587119dfaf3e8a6e, []int{23}
+}
+
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
+}
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
+}
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
+}
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
+
+func (m *RestoreRequest) GetShoppingcartId() int64 {
+	if m != nil {
+		return m.ShoppingcartId
+	}
+	return 0
+}
+
+func (m *RestoreRequest) GetSnapshotId() int64 {
+	if m != nil {
+		return m.SnapshotId
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ShoppingCart)(nil), "shoppingcart.v1.ShoppingCart")
+	proto.RegisterType((*ShoppingCartItem)(nil), "shoppingcart.v1.ShoppingCartItem")
+	proto.RegisterMapType((map[string]string)(nil), "shoppingcart.v1.ShoppingCartItem.AttributesEntry")
+	proto.RegisterType((*CartMember)(nil), "shoppingcart.v1.CartMember")
+	proto.RegisterType((*HistoryEntry)(nil), "shoppingcart.v1.HistoryEntry")
+	proto.RegisterType((*Snapshot)(nil), "shoppingcart.v1.Snapshot")
+	proto.RegisterType((*SnapshotItem)(nil), "shoppingcart.v1.SnapshotItem")
+	proto.RegisterMapType((map[string]string)(nil), "shoppingcart.v1.SnapshotItem.AttributesEntry")
+	proto.RegisterType((*CreateRequest)(nil), "shoppingcart.v1.CreateRequest")
+	proto.RegisterType((*GetRequest)(nil), "shoppingcart.v1.GetRequest")
+	proto.RegisterType((*EmptyRequest)(nil), "shoppingcart.v1.EmptyRequest")
+	proto.RegisterType((*CheckoutRequest)(nil), "shoppingcart.v1.CheckoutRequest")
+	proto.RegisterType((*AddProductRequest)(nil), "shoppingcart.v1.AddProductRequest")
+	proto.RegisterMapType((map[string]string)(nil), "shoppingcart.v1.AddProductRequest.AttributesEntry")
+	proto.RegisterType((*RemoveProductRequest)(nil), "shoppingcart.v1.RemoveProductRequest")
+	proto.RegisterType((*RemoveItemRequest)(nil), "shoppingcart.v1.RemoveItemRequest")
+	proto.RegisterType((*MoveItemRequest)(nil), "shoppingcart.v1.MoveItemRequest")
+	proto.RegisterType((*InviteRequest)(nil), "shoppingcart.v1.InviteRequest")
+	proto.RegisterType((*AcceptInvitationRequest)(nil), "shoppingcart.v1.AcceptInvitationRequest")
+	proto.RegisterType((*RevokeMemberRequest)(nil), "shoppingcart.v1.RevokeMemberRequest")
+	proto.RegisterType((*HistoryRequest)(nil), "shoppingcart.v1.HistoryRequest")
+	proto.RegisterType((*HistoryResponse)(nil), "shoppingcart.v1.HistoryResponse")
+	proto.RegisterType((*SnapshotRequest)(nil), "shoppingcart.v1.SnapshotRequest")
+	proto.RegisterType((*ListSnapshotsRequest)(nil), "shoppingcart.v1.ListSnapshotsRequest")
+	proto.RegisterType((*ListSnapshotsResponse)(nil), "shoppingcart.v1.ListSnapshotsResponse")
+	proto.RegisterType((*GetSnapshotRequest)(nil), "shoppingcart.v1.GetSnapshotRequest")
+	proto.RegisterType((*RestoreRequest)(nil), "shoppingcart.v1.RestoreRequest")
+}
+
+func init() {
+	proto.RegisterFile("shoppingcart.proto", fileDescriptor_587119dfaf3e8a6e)
+}
+
+var fileDescriptor_587119dfaf3e8a6e = []byte{
+	// 1255 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0xdb, 0x46,
+	0x13, 0x85, 0xfe, 0xa5, 0x91, 0x6d, 0x39, 0x1b, 0x7f, 0x89, 0xa2, 0xc0, 0x9f, 0x55, 0xb6, 0x69,
+	0x0c, 0x14, 0x91, 0x10, 0x07, 0x81, 0xdb, 0x04, 0x6d, 0x21, 0x19, 0xae, 0xa3, 0xd6, 0x2e, 0x12,
+	0x2a, 0x45, 0xd1, 0x5c, 0x58, 0x58, 0x89, 0x63, 0x99, 0xb0, 0x29, 0x32, 0xe4, 0x52, 0x80, 0x9e,
+	0xa8, 0xe8, 0x7d, 0x9f, 0xa1, 0xe8, 0x6b, 0xf4, 0xb6, 0xaf, 0xd0, 0x9b, 0x82, 0xbb, 0x4b, 0x8b,
+	0x22, 0x29, 0xd1, 0xb4, 0xdd, 0x3b, 0xee, 0x70, 0x66, 0x76, 0x77, 0xce, 0xec, 0x39, 0xbb, 0x40,
+	0x9c, 0x73, 0xd3, 0xb2, 0xf4, 0xc9, 0x78, 0x44, 0x6d, 0xd6, 0xb2, 0x6c, 0x93, 0x99, 0xa4, 0xb6,
+	0x60, 0x9b, 0x3e, 0x6f, 0x3c, 0x1e, 0x9b, 0xe6, 0xf8, 0x12, 0xdb, 0xfc, 0xf7, 0xd0, 0x3d, 0x6b,
+	0xa3, 0x61, 0xb1, 0x99, 0xf0, 0x6e, 0xec, 0x84, 0x7f, 0x32, 0xdd, 0x40, 0x87, 0x51, 0xc3, 0x12,
+	0x0e, 0xca, 0x5f, 0x59, 0x58, 0xeb, 0xcb, 0x8c, 0x07, 0xd4, 0x66, 0x64, 0x03, 0xb2, 0xba, 0x56,
+	0xcf, 0x34, 0x33, 0xbb, 0x39, 0x35, 0xab, 0x6b, 0xe4, 0x21, 0x94, 0x5c, 0x07, 0xed, 0x81, 0xae,
+	0xd5, 0xb3, 0xdc, 0x58, 0xf4, 0x86, 0x3d, 0x8d, 0x7c, 0x05, 0x30, 0xb2, 0x91, 0x32, 0xd4, 0x06,
+	0x94, 0xd5, 0x73, 0xcd, 0xcc, 0x6e, 0x75, 0xaf, 0xd1, 0x12, 0xf3, 0xb5, 0xfc, 0xf9, 0x5a, 0xef,
+	0xfd, 0xf9, 0xd4, 0x8a, 0xf4, 0xee, 0x30, 0x2f, 0xd4, 0xb5, 0x34, 0x3f, 0x34, 0x9f, 0x1c, 0x2a,
+	0xbd, 0x3b, 0x8c, 0xec, 0x43, 0x41, 0x67, 0x68, 0x38, 0xf5, 0x42, 0x33, 0xb7, 0x5b, 0xdd, 0xfb,
+	0xa4, 0x15, 0x2a, 0x47, 0x2b, 0xb8, 0x99, 0x1e, 0x43, 0x43, 0x15, 0xfe, 0xa4, 0x0b, 0x55, 0x87,
+	0x4e, 0x51, 0x1b, 0x88, 0xf0, 0xe2, 0x75, 0xc3, 0x81, 0x47, 0xf5, 0x78, 0x8e, 0x97, 0x50, 0x32,
+	0xd0, 0x18, 0xa2, 0xed, 0xd4, 0x4b, 0x3c, 0xfe, 0x71, 0x24, 0xde, 0x8b, 0x3b, 0xe1, 0x3e, 0xaa,
+	0xef, 0xab, 0xfc, 0x99, 0x83, 0xcd, 0x70, 0xde, 0x48, 0x9d, 0x9f, 0xc2, 0x02, 0xb2, 0xf3, 0x7a,
+	0x6f, 0x04, 0xcd, 0x3d, 0x8d, 0x6c, 0x03, 0x58, 0xb6, 0xa9, 0xb9, 0x23, 0xee, 0x93, 0xe3, 0x3e,
+	0x15, 0x69, 0xe9, 0x69, 0xa4, 0x01, 0xe5, 0x8f, 0x2e, 0x9d, 0x30, 0x9d, 0xcd, 0x78, 0x65, 0xf3,
+	0xea, 0xd5, 0x38, 0x04, 0x59, 0xe1, 0xe6, 0x90, 0x15, 0xd3, 0x40, 0xb6, 0x0d, 0x30, 0xa5, 0xb6,
+	0x4e, 0x27, 0x7c, 0xc1, 0xa5, 0x66, 0x66, 0xb7, 0xa2, 0x56, 0xa4, 0xa5, 0xa7, 0x91, 0x77, 0x00,
+	0x94, 0x31, 0x5b, 0x1f, 0xba, 0x0c, 0x9d, 0x7a, 0x99, 0xd7, 0xf5, 0x79, 0x22, 0x2e, 0xad, 0xce,
+	0x55, 0xcc, 0xe1, 0x84, 0xd9, 0x33, 0x35, 0x90, 0x84, 0x3c, 0x82, 0x32, 0xd5, 0x34, 0xd4, 0x06,
+	0xc3, 0x59, 0xbd, 0xc2, 0x0b, 0x54, 0xe2, 0xe3, 0xee, 0xac, 0xf1, 0x35, 0xd4, 0x42, 0x91, 0x64,
+	0x13, 0x72, 0x17, 0x38, 0xe3, 0x50, 0x54, 0x54, 0xef, 0x93, 0x6c, 0x41, 0x61, 0x4a, 0x2f, 0x5d,
+	0xe4, 0x08, 0x54, 0x54, 0x31, 0x78, 0x95, 0xfd, 0x32, 0xa3, 0xfc, 0x9e, 0x05, 0x98, 0x43, 0x1c,
+	0x07, 0x5a, 0x26, 0x16, 0xb4, 0xa5, 0xa7, 0x88, 0x40, 0xde, 0x36, 0x2f, 0x91, 0xe3, 0x58, 0x51,
+	0xf9, 0xb7, 0x57, 0x30, 0x7d, 0x32, 0xd5, 0x99, 0xd8, 0x40, 0x5e, 0x20, 0x2c, 0x2d, 0xdd, 0x19,
+	0x79, 0x0d, 0x55, 0x3a, 0x1a, 0xa1, 0x75, 0x6d, 0x18, 0xc1, 0x77, 0x17, 0x38, 0x06, 0x5a, 0xa0,
+	0x78, 0xf3, 0x16, 0x28, 0xa5, 0x68, 0x01, 0xe5, 0x9f, 0x2c, 0xac, 0xbd, 0xd1, 0x1d, 0x66, 0xda,
+	0x33, 0x51, 0xf3, 0x1b, 0x77, 0xff, 0x03, 0x28, 0xd2, 0x11, 0xd3, 0xcd, 0x89, 0xac, 0x98, 0x1c,
+	0x71, 0xc8, 0x47, 0xcc, 0xe4, 0x15, 0xce, 0x4b, 0xc8, 0xbd, 0xb1, 0xa8, 0xbd, 0x77, 0xe6, 0xbd,
+	0x3f, 0x05, 0x51, 0x7b, 0x6f, 0x18, 0x39, 0x49, 0xc5, 0xf0, 0x49, 0x4a, 0xe8, 0xdb, 0xa7, 0x50,
+	0xf3, 0x0f, 0xd6, 0x60, 0x88, 0x67, 0xa6, 0x8d, 0xf5, 0x32, 0x3f, 0x6f, 0x1b, 0xbe, 0xb9, 0xcb,
+	0xad, 0xe4, 0x09, 0x5c, 0x59, 0x06, 0xf4, 0x8c, 0xa1, 0xcd, 0x7b, 0x32, 0xaf, 0xae, 0xfb, 0xd6,
+	0x8e, 0x67, 0xf4, 0xa6, 0xb3, 0xf1, 0xa3, 0x8b, 0x0e, 0x9f, 0x0e, 0xc4, 0x74, 0xd2, 0x12, 0xa1,
+	0xdb, 0x6a, 0x0a, 0xe0, 0x94, 0xbf, 0x33, 0x50, 0xee, 0x4f, 0xa8, 0xe5, 0x9c, 0x9b, 0xec, 0x56,
+	0x95, 0xb7, 0x91, 0x3a, 0xf3, 0xca, 0x8b, 0x91, 0xb7, 0x6e, 0x7f, 0x61, 0xf3, 0x6e, 0x95, 0x96,
+	0xee, 0xad, 0x38, 0xe7, 0x05, 0x14, 0x82, 0x64, 0xbd, 0x1d, 0x25, 0x05, 0xb9, 0xa9, 0x00, 0xcf,
+	0x2b, 0xbf, 0x7a, 0x82, 0x16, 0xb0, 0x47, 0x36, 0xbc, 0x03, 0x55, 0x47, 0xfe, 0x9f, 0x6f, 0x16,
+	0x7c, 0x53, 0x32, 0xc1, 0x2e, 0xb6, 0x45, 0x3e, 0xdc, 0x16, 0x27, 0x0b, 0x74, 0x26, 0x54, 0xea,
+	0xd9, 0xca, 0x95, 0xaf, 0xa4, 0xb2, 0x20, 0x9d, 0x17, 0x17, 0xe9, 0xfc, 0xb6, 0x5c, 0x56, 0x83,
+	0xf5, 0x03, 0x5e, 0x6b, 0x55, 0x34, 0x99, 0xf2, 0x12, 0xe0, 0x08, 0x99, 0x1c, 0x5d, 0x9b, 0xdb,
+	0x94, 0x7d, 0x58, 0x3b, 0xf4, 0xae, 0x1c, 0xa9, 0x03, 0x5f, 0x41, 0xed, 0xe0, 0x1c, 0x47, 0x17,
+	0xa6, 0x9b, 0x7e, 0xd2, 0xdf, 0xb2, 0x70, 0xaf, 0xa3, 0x69, 0x6f, 0x05, 0x2c, 0x69, 0xc3, 0x43,
+	0x18, 0x67, 0x57, 0x89, 0x68, 0x2e, 0x24, 0xa2, 0x09, 0xf8, 0xab, 0x31, 0xf8, 0xef, 0x45, 0xf0,
+	0x8f, 0x2c, 0x7d, 0x55, 0x13, 0xdc, 0x16, 0xe8, 0x53, 0xd8, 0x52, 0xd1, 0x30, 0xa7, 0xf8, 0xdf,
+	0x54, 0x4b, 0xf9, 0x09, 0xee, 0x89, 0xfc, 0xfc, 0x1c, 0xa6, 0x4d, 0x1e, 0xa0, 0xe7, 0x6c, 0x90,
+	0x9e, 0x95, 0x3e, 0xd4, 0x4e, 0xee, 0x3c, 0x29, 0xc2, 0x7a, 0x8f, 0x2b, 0xe9, 0x4d, 0x52, 0x5e,
+	0x5b, 0xc2, 0x95, 0x2e, 0x3c, 0xec, 0x70, 0xd1, 0xe5, 0x93, 0x51, 0x4f, 0xa2, 0x52, 0xb7, 0xf8,
+	0xcf, 0x70, 0x5f, 0xc5, 0xa9, 0x79, 0x81, 0xf2, 0x3e, 0x79, 0x57, 0x0b, 0x56, 0xc6, 0xb0, 0x21,
+	0xc5, 0x38, 0x75, 0xce, 0x2d, 0x28, 0x5c, 0xea, 0x86, 0xce, 0x78, 0xc6, 0x82, 0x2a, 0x06, 0x9e,
+	0x34, 0x98, 0x67, 0x67, 0x0e, 0x8a, 0x67, 0x40, 0x41, 0x95, 0x23, 0xe5, 0x7b, 0xa8, 0x5d, 0x4d,
+	0xe4, 0x58, 0xe6, 0xc4, 0x41, 0xb2, 0x0f, 0x25, 0x9c, 0x30, 0x5b, 0x47, 0xa7, 0x9e, 0x59, 0xc2,
+	0xea, 0xc1, 0x8b, 0x82, 0xea, 0x7b, 0x7b, 0x64, 0xe1, 0x93, 0x66, 0xea, 0x4a, 0x7e, 0x0b, 0x5b,
+	0xc7, 0xba, 0xc3, 0xfc, 0x78, 0x27, 0x75, 0x82, 0xb7, 0xf0, 0xbf, 0x50, 0x82, 0xab, 0xed, 0x54,
+	0x7c, 0xe5, 0xf0, 0x37, 0xf4, 0x68, 0x29, 0xd9, 0xab, 0x73, 0x5f, 0xe5, 0x14, 0xc8, 0x11, 0xb2,
+	0x9b, 0xee, 0x28, 0x51, 0xc4, 0x94, 0x0f, 0xb0, 0xa1, 0xa2, 0x57, 0x47, 0xbc, 0xf3, 0xdc, 0x7b,
+	0x7f, 0x00, 0xdc, 0x0f, 0xde, 0xc7, 0xfb, 0x68, 0x4f, 0xf5, 0x11, 0x92, 0x23, 0x28, 0x0a, 0x41,
+	0x21, 0xff, 0x8f, 0xbe, 0x8b, 0x82, 0x4a, 0xd3, 0xd8, 0x5e, 0x79, 0xbf, 0x27, 0x1d, 0xc8, 0x1d,
+	0x21, 0x23, 0xd1, 0xd7, 0xd5, 0x5c, 0x9e, 0x92, 0x52, 0x7c, 0x03, 0x05, 0x2e, 0x4a, 0x24, 0xea,
+	0x17, 0x14, 0xab, 0xc6, 0x83, 0xc8, 0x55, 0x44, 0x84, 0xfd, 0x00, 0x65, 0x5f, 0x9b, 0x48, 0x33,
+	0xba, 0x9b, 0x45, 0xd9, 0x4a, 0x5a, 0x4c, 0x1f, 0x60, 0x4e, 0xf8, 0x44, 0x49, 0x56, 0x83, 0x46,
+	0xf2, 0xc3, 0x94, 0xfc, 0x08, 0xeb, 0x0b, 0xac, 0x4e, 0x9e, 0x44, 0x62, 0xe2, 0x58, 0x7f, 0xe9,
+	0x8e, 0xdf, 0x00, 0xcc, 0x59, 0x3c, 0x66, 0x91, 0x11, 0x8a, 0x5f, 0x9a, 0xa9, 0x0f, 0x6b, 0x7d,
+	0x3a, 0xc5, 0xef, 0x4c, 0xfb, 0x98, 0x7a, 0x37, 0xdb, 0x68, 0xfd, 0x42, 0xbc, 0x7e, 0x9d, 0xed,
+	0xbe, 0x03, 0xf0, 0xa2, 0xde, 0x9b, 0xbc, 0xa2, 0x77, 0x92, 0xf2, 0x10, 0x8a, 0x42, 0x0b, 0x62,
+	0xfa, 0x75, 0x41, 0x24, 0x1a, 0xab, 0xde, 0xf9, 0xe4, 0x17, 0xd8, 0x0c, 0x73, 0x3d, 0xd9, 0x8d,
+	0x62, 0x1c, 0x2f, 0x07, 0xab, 0x53, 0x1f, 0xc3, 0x5a, 0x50, 0x02, 0xc8, 0x67, 0x31, 0xa8, 0x44,
+	0x14, 0x62, 0x29, 0x2e, 0xc7, 0x50, 0x92, 0xdc, 0x4a, 0x76, 0x96, 0xb1, 0xae, 0x9f, 0xa3, 0xb9,
+	0xdc, 0x41, 0x52, 0xdf, 0x51, 0xe0, 0x51, 0xd1, 0x5c, 0xce, 0x79, 0x32, 0xdf, 0x72, 0x56, 0x24,
+	0xa7, 0xb0, 0xbe, 0x40, 0xae, 0x31, 0x8d, 0x1c, 0xc7, 0xde, 0x8d, 0xcf, 0x93, 0xdc, 0xe4, 0x42,
+	0x4f, 0xa0, 0x1a, 0xa0, 0x5a, 0xf2, 0x69, 0x1c, 0xab, 0xa4, 0x58, 0x6e, 0x0f, 0x4a, 0x92, 0x59,
+	0x63, 0xaa, 0xb8, 0xc8, 0xb9, 0x09, 0xbc, 0xd0, 0x7d, 0xf6, 0xe1, 0x8b, 0xb1, 0xce, 0xce, 0xdd,
+	0x61, 0x6b, 0x64, 0x1a, 0xed, 0xa1, 0x3b, 0xd6, 0x0d, 0x64, 0xf4, 0xb2, 0x1d, 0x0c, 0x6a, 0xdb,
+	0xd6, 0xa8, 0x6d, 0x0d, 0x5f, 0x5b, 0xc3, 0x61, 0x91, 0xe3, 0xf9, 0xe2, 0xdf, 0x01, 0x00, 0xa6,
+	0xb7, 0x92, 0x97, 0x17, 0x14, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ShoppingCartServiceClient is the client API for ShoppingCartService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ShoppingCartServiceClient interface {
+	// Create creates a shopping cart for the authenticated user.
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*ShoppingCart, error)
+	// Get retrieves a shopping cart along with its items.
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*ShoppingCart, error)
+	// Empty removes all items from a shopping cart.
+	Empty(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Checkout converts reserved stock of the cart items into sold stock and
+	// empties the cart. The checked out cart is returned.
+	Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*ShoppingCart, error)
+	// AddProduct adds a product to a shopping cart. If the line of the product,
+	// variant and attributes is already in the cart, its quantity is increased.
+	AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*ShoppingCartItem, error)
+	// RemoveProduct removes all lines of a product from a shopping cart.
+	RemoveProduct(ctx context.Context, in *RemoveProductRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// RemoveItem removes a single line from a shopping cart.
+	RemoveItem(ctx context.Context, in *RemoveItemRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// SaveForLater moves a line of the cart to the items saved for later.
+	// The saved line is returned.
+	SaveForLater(ctx context.Context, in *MoveItemRequest, opts ...grpc.CallOption) (*ShoppingCartItem, error)
+	// MoveToCart moves a line saved for later back to the cart.
+	// The active line is returned.
+	MoveToCart(ctx context.Context, in *MoveItemRequest, opts ...grpc.CallOption) (*ShoppingCartItem, error)
+	// Invite invites a user to a shopping cart as editor or viewer. Only the
+	// owner may invite, the invited user gets access after accepting.
+	Invite(ctx context.Context, in *InviteRequest, opts ...grpc.CallOption) (*CartMember, error)
+	// AcceptInvitation accepts the invitation of the authenticated user.
+	AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*CartMember, error)
+	// RevokeMember removes a member or a pending invitation. The owner may
+	// remove anyone, members may only remove themselves.
+	RevokeMember(ctx context.Context, in *RevokeMemberRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// History retrieves a page of the audit trail of a shopping cart, newest
+	// entries first. Only the owner and admins may read it. The limit is 50
+	// entries if not set, and at most 100.
+	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	// Snapshot takes a snapshot of the active items of a shopping cart. The
+	// message is fully qualified, as the method has the same name.
+	Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error)
+	// ListSnapshots lists the snapshots of a shopping cart, newest first and
+	// without their items.
+	ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error)
+	// GetSnapshot retrieves a snapshot along with its items.
+	GetSnapshot(ctx context.Context, in *GetSnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error)
+	// Restore replaces the active items of a shopping cart with the items of
+	// a snapshot. The current items are kept in a new snapshot. The restored
+	// cart is returned.
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*ShoppingCart, error)
+}
+
+type shoppingCartServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewShoppingCartServiceClient(cc grpc.ClientConnInterface) ShoppingCartServiceClient {
+	return &shoppingCartServiceClient{cc}
+}
+
+func (c *shoppingCartServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*ShoppingCart, error) {
+	out := new(ShoppingCart)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*ShoppingCart, error) {
+	out := new(ShoppingCart)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) Empty(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/Empty", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*ShoppingCart, error) {
+	out := new(ShoppingCart)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/Checkout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*ShoppingCartItem, error) {
+	out := new(ShoppingCartItem)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/AddProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) RemoveProduct(ctx context.Context, in *RemoveProductRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/RemoveProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) RemoveItem(ctx context.Context, in *RemoveItemRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/RemoveItem", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) SaveForLater(ctx context.Context, in *MoveItemRequest, opts ...grpc.CallOption) (*ShoppingCartItem, error) {
+	out := new(ShoppingCartItem)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/SaveForLater", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) MoveToCart(ctx context.Context, in *MoveItemRequest, opts ...grpc.CallOption) (*ShoppingCartItem, error) {
+	out := new(ShoppingCartItem)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/MoveToCart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) Invite(ctx context.Context, in *InviteRequest, opts ...grpc.CallOption) (*CartMember, error) {
+	out := new(CartMember)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/Invite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) AcceptInvitation(ctx context.Context, in *AcceptInvitationRequest, opts ...grpc.CallOption) (*CartMember, error) {
+	out := new(CartMember)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/AcceptInvitation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) RevokeMember(ctx context.Context, in *RevokeMemberRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/RevokeMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/History", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error) {
+	out := new(Snapshot)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/Snapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) ListSnapshots(ctx context.Context, in *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error) {
+	out := new(ListSnapshotsResponse)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/ListSnapshots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) GetSnapshot(ctx context.Context, in *GetSnapshotRequest, opts ...grpc.CallOption) (*Snapshot, error) {
+	out := new(Snapshot)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/GetSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shoppingCartServiceClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*ShoppingCart, error) {
+	out := new(ShoppingCart)
+	err := c.cc.Invoke(ctx, "/shoppingcart.v1.ShoppingCartService/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShoppingCartServiceServer is the server API for ShoppingCartService service.
+type ShoppingCartServiceServer interface {
+	// Create creates a shopping cart for the authenticated user.
+	Create(context.Context, *CreateRequest) (*ShoppingCart, error)
+	// Get retrieves a shopping cart along with its items.
+	Get(context.Context, *GetRequest) (*ShoppingCart, error)
+	// Empty removes all items from a shopping cart.
+	Empty(context.Context, *EmptyRequest) (*empty.Empty, error)
+	// Checkout converts reserved stock of the cart items into sold stock and
+	// empties the cart. The checked out cart is returned.
+	Checkout(context.Context, *CheckoutRequest) (*ShoppingCart, error)
+	// AddProduct adds a product to a shopping cart. If the line of the product,
+	// variant and attributes is already in the cart, its quantity is increased.
+	AddProduct(context.Context, *AddProductRequest) (*ShoppingCartItem, error)
+	// RemoveProduct removes all lines of a product from a shopping cart.
+	RemoveProduct(context.Context, *RemoveProductRequest) (*empty.Empty, error)
+	// RemoveItem removes a single line from a shopping cart.
+	RemoveItem(context.Context, *RemoveItemRequest) (*empty.Empty, error)
+	// SaveForLater moves a line of the cart to the items saved for later.
+	// The saved line is returned.
+	SaveForLater(context.Context, *MoveItemRequest) (*ShoppingCartItem, error)
+	// MoveToCart moves a line saved for later back to the cart.
+	// The active line is returned.
+	MoveToCart(context.Context, *MoveItemRequest) (*ShoppingCartItem, error)
+	// Invite invites a user to a shopping cart as editor or viewer. Only the
+	// owner may invite, the invited user gets access after accepting.
+	Invite(context.Context, *InviteRequest) (*CartMember, error)
+	// AcceptInvitation accepts the invitation of the authenticated user.
+	AcceptInvitation(context.Context, *AcceptInvitationRequest) (*CartMember, error)
+	// RevokeMember removes a member or a pending invitation. The owner may
+	// remove anyone, members may only remove themselves.
+	RevokeMember(context.Context, *RevokeMemberRequest) (*empty.Empty, error)
+	// History retrieves a page of the audit trail of a shopping cart, newest
+	// entries first. Only the owner and admins may read it. The limit is 50
+	// entries if not set, and at most 100.
+	History(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	// Snapshot takes a snapshot of the active items of a shopping cart. The
+	// message is fully qualified, as the method has the same name.
+	Snapshot(context.Context, *SnapshotRequest) (*Snapshot, error)
+	// ListSnapshots lists the snapshots of a shopping cart, newest first and
+	// without their items.
+	ListSnapshots(context.Context, *ListSnapshotsRequest) (*ListSnapshotsResponse, error)
+	// GetSnapshot retrieves a snapshot along with its items.
+	GetSnapshot(context.Context, *GetSnapshotRequest) (*Snapshot, error)
+	// Restore replaces the active items of a shopping cart with the items of
+	// a snapshot. The current items are kept in a new snapshot. The restored
+	// cart is returned.
+	Restore(context.Context, *RestoreRequest) (*ShoppingCart, error)
+}
+
+// UnimplementedShoppingCartServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedShoppingCartServiceServer struct {
+}
+
+func (*UnimplementedShoppingCartServiceServer) Create(ctx context.Context, req *CreateRequest) (*ShoppingCart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) Get(ctx context.Context, req *GetRequest) (*ShoppingCart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) Empty(ctx context.Context, req *EmptyRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Empty not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) Checkout(ctx context.Context, req *CheckoutRequest) (*ShoppingCart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkout not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) AddProduct(ctx context.Context, req *AddProductRequest) (*ShoppingCartItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProduct not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) RemoveProduct(ctx context.Context, req *RemoveProductRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveProduct not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) RemoveItem(ctx context.Context, req *RemoveItemRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveItem not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) SaveForLater(ctx context.Context, req *MoveItemRequest) (*ShoppingCartItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveForLater not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) MoveToCart(ctx context.Context, req *MoveItemRequest) (*ShoppingCartItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveToCart not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) Invite(ctx context.Context, req *InviteRequest) (*CartMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Invite not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) AcceptInvitation(ctx context.Context, req *AcceptInvitationRequest) (*CartMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptInvitation not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) RevokeMember(ctx context.Context, req *RevokeMemberRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeMember not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) History(ctx context.Context, req *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method History not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) Snapshot(ctx context.Context, req *SnapshotRequest) (*Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) ListSnapshots(ctx context.Context, req *ListSnapshotsRequest) (*ListSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshots not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) GetSnapshot(ctx context.Context, req *GetSnapshotRequest) (*Snapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshot not implemented")
+}
+func (*UnimplementedShoppingCartServiceServer) Restore(ctx context.Context, req *RestoreRequest) (*ShoppingCart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+
+func RegisterShoppingCartServiceServer(s *grpc.Server, srv ShoppingCartServiceServer) {
+	s.RegisterService(&_ShoppingCartService_serviceDesc, srv)
+}
+
+func _ShoppingCartService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_Empty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).Empty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/Empty",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).Empty(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_Checkout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).Checkout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/Checkout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).Checkout(ctx, req.(*CheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_AddProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).AddProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/AddProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).AddProduct(ctx, req.(*AddProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_RemoveProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).RemoveProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/RemoveProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).RemoveProduct(ctx, req.(*RemoveProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_RemoveItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).RemoveItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/RemoveItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).RemoveItem(ctx, req.(*RemoveItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_SaveForLater_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).SaveForLater(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/SaveForLater",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).SaveForLater(ctx, req.(*MoveItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_MoveToCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).MoveToCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/MoveToCart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).MoveToCart(ctx, req.(*MoveItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_Invite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).Invite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/Invite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).Invite(ctx, req.(*InviteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_AcceptInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).AcceptInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/AcceptInvitation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).AcceptInvitation(ctx, req.(*AcceptInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_RevokeMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).RevokeMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/RevokeMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).RevokeMember(ctx, req.(*RevokeMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/History",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).History(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_Snapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).Snapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/Snapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).Snapshot(ctx, req.(*SnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/ListSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).ListSnapshots(ctx, req.(*ListSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_GetSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).GetSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/GetSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).GetSnapshot(ctx, req.(*GetSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShoppingCartService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShoppingCartServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/shoppingcart.v1.ShoppingCartService/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShoppingCartServiceServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ShoppingCartService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "shoppingcart.v1.ShoppingCartService",
+	HandlerType: (*ShoppingCartServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _ShoppingCartService_Create_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _ShoppingCartService_Get_Handler,
+		},
+		{
+			MethodName: "Empty",
+			Handler:    _ShoppingCartService_Empty_Handler,
+		},
+		{
+			MethodName: "Checkout",
+			Handler:    _ShoppingCartService_Checkout_Handler,
+		},
+		{
+			MethodName: "AddProduct",
+			Handler:    _ShoppingCartService_AddProduct_Handler,
+		},
+		{
+			MethodName: "RemoveProduct",
+			Handler:    _ShoppingCartService_RemoveProduct_Handler,
+		},
+		{
+			MethodName: "RemoveItem",
+			Handler:    _ShoppingCartService_RemoveItem_Handler,
+		},
+		{
+			MethodName: "SaveForLater",
+			Handler:    _ShoppingCartService_SaveForLater_Handler,
+		},
+		{
+			MethodName: "MoveToCart",
+			Handler:    _ShoppingCartService_MoveToCart_Handler,
+		},
+		{
+			MethodName: "Invite",
+			Handler:    _ShoppingCartService_Invite_Handler,
+		},
+		{
+			MethodName: "AcceptInvitation",
+			Handler:    _ShoppingCartService_AcceptInvitation_Handler,
+		},
+		{
+			MethodName: "RevokeMember",
+			Handler:    _ShoppingCartService_RevokeMember_Handler,
+		},
+		{
+			MethodName: "History",
+			Handler:    _ShoppingCartService_History_Handler,
+		},
+		{
+			MethodName: "Snapshot",
+			Handler:    _ShoppingCartService_Snapshot_Handler,
+		},
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _ShoppingCartService_ListSnapshots_Handler,
+		},
+		{
+			MethodName: "GetSnapshot",
+			Handler:    _ShoppingCartService_GetSnapshot_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _ShoppingCartService_Restore_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "shoppingcart.proto",
+}
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/ratelimit"
+
+	"google.golang.org/grpc"
+)
+
+// readMethods are the calls limited as reads, all other calls are writes
+var readMethods = map[string]bool{
+	"/shoppingcart.v1.ShoppingCartService/Get":           true,
+	"/shoppingcart.v1.ShoppingCartService/History":       true,
+	"/shoppingcart.v1.ShoppingCartService/ListSnapshots": true,
+	"/shoppingcart.v1.ShoppingCartService/GetSnapshot":   true,
+}
+
+// rateLimitInterceptor limits the rate of calls of the authenticated user with
+// the limiter of the HTTP API, so both APIs share the buckets of a user. It
+// runs after authInterceptor. Calls are let through if the limiter fails.
+func (server *Server) rateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (interface{}, error) {
+	if server.rateLimiter == nil {
+		return next(ctx, req)
+	}
+
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	kind := ratelimit.Write
+	if readMethods[info.FullMethod] {
+		kind = ratelimit.Read
+	}
+
+	client := ratelimit.UserClient(user.ID)
+	result, err := server.rateLimiter.Allow(ctx, client, kind)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("Unable to check the rate limit of %s: %s", client, err)
+	} else if !result.Allowed {
+		return nil, Error(shoppingcart.ErrRateLimitExceeded)
+	}
+
+	return next(ctx, req)
+}
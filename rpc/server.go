@@ -0,0 +1,458 @@
+// Package rpc exposes the shopping cart services over gRPC.
+package rpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/handler"
+	"github.com/bugimetal/shoppingcart/rpc/pb"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"go.opencensus.io/plugin/ocgrpc"
+	"google.golang.org/grpc"
+)
+
+// Server implements the gRPC shopping cart API on top of the same services
+// the HTTP Handler relies on.
+type Server struct {
+	grpc                *grpc.Server
+	shoppingCartService handler.ShoppingCartService
+	authService         handler.AuthService
+	rateLimiter         handler.RateLimiter
+}
+
+// New returns a new Server.
+func New(services handler.Services) *Server {
+	server := &Server{
+		shoppingCartService: services.ShoppingCart,
+		authService:         services.Auth,
+		rateLimiter:         services.RateLimiter,
+	}
+
+	server.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.authInterceptor, server.rateLimitInterceptor),
+		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
+	)
+	pb.RegisterShoppingCartServiceServer(server.grpc, server)
+
+	return server
+}
+
+// Serve accepts incoming connections on the listener.
+func (server *Server) Serve(lis net.Listener) error {
+	return server.grpc.Serve(lis)
+}
+
+// GracefulStop stops the server from accepting new connections and blocks
+// until all the pending calls are finished.
+func (server *Server) GracefulStop() {
+	server.grpc.GracefulStop()
+}
+
+// Create creates a shopping cart for the authenticated user
+func (server *Server) Create(ctx context.Context, req *pb.CreateRequest) (*pb.ShoppingCart, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	cart := shoppingcart.ShoppingCart{
+		UserID: user.ID,
+	}
+
+	if err := server.shoppingCartService.Create(ctx, &cart); err != nil {
+		return nil, Error(err)
+	}
+
+	return newShoppingCart(cart), nil
+}
+
+// Get retrieves shopping cart along with shopping cart items
+func (server *Server) Get(ctx context.Context, req *pb.GetRequest) (*pb.ShoppingCart, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	cart, err := server.shoppingCartService.Get(ctx, req.ShoppingcartId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newShoppingCart(cart), nil
+}
+
+// Empty removes shopping cart items
+func (server *Server) Empty(ctx context.Context, req *pb.EmptyRequest) (*empty.Empty, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	if err := server.shoppingCartService.Empty(ctx, req.ShoppingcartId, user.ID); err != nil {
+		return nil, Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// Checkout checks out shopping cart
+func (server *Server) Checkout(ctx context.Context, req *pb.CheckoutRequest) (*pb.ShoppingCart, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	cart, err := server.shoppingCartService.Checkout(ctx, req.ShoppingcartId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newShoppingCart(cart), nil
+}
+
+// AddProduct adds product to existing shopping cart
+func (server *Server) AddProduct(ctx context.Context, req *pb.AddProductRequest) (*pb.ShoppingCartItem, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	cartItem := shoppingcart.ShoppingCartItem{
+		ShoppingCartID: req.ShoppingcartId,
+		ProductID:      req.ProductId,
+		VariantID:      req.VariantId,
+		Attributes:     req.Attributes,
+		Quantity:       req.Quantity,
+	}
+
+	if err := server.shoppingCartService.AddProduct(ctx, &cartItem, user.ID); err != nil {
+		return nil, Error(err)
+	}
+
+	return newShoppingCartItem(cartItem), nil
+}
+
+// RemoveProduct removes all lines of a product from existing shopping cart
+func (server *Server) RemoveProduct(ctx context.Context, req *pb.RemoveProductRequest) (*empty.Empty, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	if err := server.shoppingCartService.RemoveProduct(ctx, req.ShoppingcartId, req.ProductId, user.ID); err != nil {
+		return nil, Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// RemoveItem removes a single line from existing shopping cart
+func (server *Server) RemoveItem(ctx context.Context, req *pb.RemoveItemRequest) (*empty.Empty, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	if err := server.shoppingCartService.RemoveItem(ctx, req.ShoppingcartId, req.ItemId, user.ID); err != nil {
+		return nil, Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// SaveForLater moves a line of the cart to the items saved for later
+func (server *Server) SaveForLater(ctx context.Context, req *pb.MoveItemRequest) (*pb.ShoppingCartItem, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	cartItem, err := server.shoppingCartService.SaveForLater(ctx, req.ShoppingcartId, req.ItemId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newShoppingCartItem(cartItem), nil
+}
+
+// MoveToCart moves a line saved for later back to the cart
+func (server *Server) MoveToCart(ctx context.Context, req *pb.MoveItemRequest) (*pb.ShoppingCartItem, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	cartItem, err := server.shoppingCartService.MoveToCart(ctx, req.ShoppingcartId, req.ItemId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newShoppingCartItem(cartItem), nil
+}
+
+// Invite invites a user to the shopping cart
+func (server *Server) Invite(ctx context.Context, req *pb.InviteRequest) (*pb.CartMember, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	member := shoppingcart.CartMember{
+		UserID: req.UserId,
+		Role:   req.Role,
+	}
+
+	if err := server.shoppingCartService.Invite(ctx, req.ShoppingcartId, user.ID, &member); err != nil {
+		return nil, Error(err)
+	}
+
+	return newCartMember(member), nil
+}
+
+// AcceptInvitation accepts the invitation of the authenticated user to the shopping cart
+func (server *Server) AcceptInvitation(ctx context.Context, req *pb.AcceptInvitationRequest) (*pb.CartMember, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	member, err := server.shoppingCartService.AcceptInvitation(ctx, req.ShoppingcartId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newCartMember(member), nil
+}
+
+// RevokeMember removes a member or a pending invitation from the shopping cart
+func (server *Server) RevokeMember(ctx context.Context, req *pb.RevokeMemberRequest) (*empty.Empty, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	if err := server.shoppingCartService.RevokeMember(ctx, req.ShoppingcartId, user.ID, req.UserId); err != nil {
+		return nil, Error(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// History retrieves a page of the audit trail of the shopping cart, newest
+// entries first. Admins may read the history of any cart.
+func (server *Server) History(ctx context.Context, req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	limit, offset := int(req.Limit), int(req.Offset)
+	if limit == 0 {
+		limit = handler.DefaultHistoryLimit
+	}
+
+	var errs shoppingcart.ValidationErrors
+	if limit < 0 || limit > handler.MaxHistoryLimit {
+		errs = append(errs, shoppingcart.FieldError{Field: "limit", Err: handler.ErrOutOfRange})
+	}
+	if offset < 0 {
+		errs = append(errs, shoppingcart.FieldError{Field: "offset", Err: handler.ErrOutOfRange})
+	}
+	if len(errs) > 0 {
+		return nil, Error(errs)
+	}
+
+	var entries []shoppingcart.HistoryEntry
+	if user.Admin {
+		entries, err = server.shoppingCartService.AdminHistory(ctx, req.ShoppingcartId, limit, offset)
+	} else {
+		entries, err = server.shoppingCartService.History(ctx, req.ShoppingcartId, user.ID, limit, offset)
+	}
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	res := &pb.HistoryResponse{}
+	for _, entry := range entries {
+		res.Entries = append(res.Entries, newHistoryEntry(entry))
+	}
+
+	return res, nil
+}
+
+// Snapshot takes a snapshot of the active items of the shopping cart
+func (server *Server) Snapshot(ctx context.Context, req *pb.SnapshotRequest) (*pb.Snapshot, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	snapshot, err := server.shoppingCartService.Snapshot(ctx, req.ShoppingcartId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newSnapshot(snapshot), nil
+}
+
+// ListSnapshots lists the snapshots of the shopping cart, newest first
+func (server *Server) ListSnapshots(ctx context.Context, req *pb.ListSnapshotsRequest) (*pb.ListSnapshotsResponse, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	snapshots, err := server.shoppingCartService.ListSnapshots(ctx, req.ShoppingcartId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	res := &pb.ListSnapshotsResponse{}
+	for _, snapshot := range snapshots {
+		res.Snapshots = append(res.Snapshots, newSnapshot(snapshot))
+	}
+
+	return res, nil
+}
+
+// GetSnapshot retrieves a snapshot of the shopping cart along with its items
+func (server *Server) GetSnapshot(ctx context.Context, req *pb.GetSnapshotRequest) (*pb.Snapshot, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	snapshot, err := server.shoppingCartService.GetSnapshot(ctx, req.ShoppingcartId, req.SnapshotId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newSnapshot(snapshot), nil
+}
+
+// Restore replaces the active items of the shopping cart with the items of the snapshot
+func (server *Server) Restore(ctx context.Context, req *pb.RestoreRequest) (*pb.ShoppingCart, error) {
+	user, err := authUser(ctx)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	cart, err := server.shoppingCartService.Restore(ctx, req.ShoppingcartId, req.SnapshotId, user.ID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	return newShoppingCart(cart), nil
+}
+
+func newShoppingCart(cart shoppingcart.ShoppingCart) *pb.ShoppingCart {
+	msg := &pb.ShoppingCart{
+		Id:        cart.ID,
+		UserId:    cart.UserID,
+		CreatedAt: newTimestamp(cart.CreatedAt),
+		UpdatedAt: newTimestamp(cart.UpdatedAt),
+	}
+
+	for _, item := range cart.Items {
+		msg.Items = append(msg.Items, newShoppingCartItem(item))
+	}
+
+	for _, item := range cart.SavedItems {
+		msg.SavedItems = append(msg.SavedItems, newShoppingCartItem(item))
+	}
+
+	for _, member := range cart.Members {
+		msg.Members = append(msg.Members, newCartMember(member))
+	}
+
+	return msg
+}
+
+func newShoppingCartItem(item shoppingcart.ShoppingCartItem) *pb.ShoppingCartItem {
+	return &pb.ShoppingCartItem{
+		Id:             item.ID,
+		ShoppingcartId: item.ShoppingCartID,
+		ProductId:      item.ProductID,
+		VariantId:      item.VariantID,
+		Attributes:     item.Attributes,
+		AddedBy:        item.AddedBy,
+		Quantity:       item.Quantity,
+		CreatedAt:      newTimestamp(item.CreatedAt),
+		UpdatedAt:      newTimestamp(item.UpdatedAt),
+	}
+}
+
+func newCartMember(member shoppingcart.CartMember) *pb.CartMember {
+	msg := &pb.CartMember{
+		ShoppingcartId: member.ShoppingCartID,
+		UserId:         member.UserID,
+		Role:           member.Role,
+		InvitedBy:      member.InvitedBy,
+		CreatedAt:      newTimestamp(member.CreatedAt),
+		UpdatedAt:      newTimestamp(member.UpdatedAt),
+	}
+
+	if member.AcceptedAt != nil {
+		msg.AcceptedAt = newTimestamp(*member.AcceptedAt)
+	}
+
+	return msg
+}
+
+func newHistoryEntry(entry shoppingcart.HistoryEntry) *pb.HistoryEntry {
+	return &pb.HistoryEntry{
+		Id:             entry.ID,
+		ShoppingcartId: entry.ShoppingCartID,
+		Action:         entry.Action,
+		ActorId:        entry.ActorID,
+		ItemId:         entry.ItemID,
+		ProductId:      entry.ProductID,
+		VariantId:      entry.VariantID,
+		QuantityBefore: entry.QuantityBefore,
+		QuantityAfter:  entry.QuantityAfter,
+		RequestId:      entry.RequestID,
+		CreatedAt:      newTimestamp(entry.CreatedAt),
+	}
+}
+
+func newSnapshot(snapshot shoppingcart.Snapshot) *pb.Snapshot {
+	msg := &pb.Snapshot{
+		Id:             snapshot.ID,
+		ShoppingcartId: snapshot.ShoppingCartID,
+		Reason:         snapshot.Reason,
+		CreatedBy:      snapshot.CreatedBy,
+		CreatedAt:      newTimestamp(snapshot.CreatedAt),
+	}
+
+	for _, item := range snapshot.Items {
+		msg.Items = append(msg.Items, &pb.SnapshotItem{
+			Id:         item.ID,
+			SnapshotId: item.SnapshotID,
+			ProductId:  item.ProductID,
+			VariantId:  item.VariantID,
+			Attributes: item.Attributes,
+			Quantity:   item.Quantity,
+		})
+	}
+
+	return msg
+}
+
+func newTimestamp(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return nil
+	}
+
+	return ts
+}
@@ -0,0 +1,533 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/handler"
+	"github.com/bugimetal/shoppingcart/internal/mock/auth"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/ratelimit"
+	"github.com/bugimetal/shoppingcart/rpc/pb"
+	"github.com/bugimetal/shoppingcart/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient starts a Server on an in-memory listener and returns a client connected to it.
+func newClient(t *testing.T, services handler.Services) pb.ShoppingCartServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+	server := New(services)
+	go server.Serve(lis)
+	t.Cleanup(server.GracefulStop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatalf("Unable to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewShoppingCartServiceClient(conn)
+}
+
+// withCredentials returns a context carrying Basic credentials in the outgoing metadata.
+func withCredentials(user, password string) context.Context {
+	creds := base64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Basic %s", creds))
+}
+
+// testAuth authenticates the users of the auth mock and, in addition, the
+// viewer and the invitee of the shared cart of the storage mock and an admin.
+type testAuth struct{}
+
+func (testAuth) Authenticate(ctx context.Context, user auth.User) (auth.User, error) {
+	user, err := auth.New().Authenticate(ctx, user)
+	if err != nil {
+		return user, err
+	}
+
+	switch user.Name {
+	case "viewer":
+		user.ID = 4
+	case "invitee":
+		user.ID = 5
+	case "admin":
+		user.ID = 6
+		user.Admin = true
+	}
+
+	return user, nil
+}
+
+func TestServer_Create(t *testing.T) {
+	client := newClient(t, handler.Services{
+		ShoppingCart: &shoppingcart_mock.MockShoppingCartService{},
+		Auth:         auth.New(),
+	})
+
+	t.Run("create shopping cart without auth", func(t *testing.T) {
+		_, err := client.Create(context.Background(), &pb.CreateRequest{})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("Expected status code %s, but got %s", codes.Unauthenticated, status.Code(err))
+		}
+	})
+
+	t.Run("create shopping cart successful", func(t *testing.T) {
+		cart, err := client.Create(withCredentials("test", "test"), &pb.CreateRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if cart.UserId != 1 {
+			t.Fatalf("Expected user id %d, got %d", 1, cart.UserId)
+		}
+	})
+}
+
+func TestServer_Get(t *testing.T) {
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+	})
+
+	client := newClient(t, handler.Services{
+		ShoppingCart: services.ShoppingCart,
+		Auth:         auth.New(),
+	})
+
+	t.Run("get shopping cart successful", func(t *testing.T) {
+		cart, err := client.Get(withCredentials("test", "test"), &pb.GetRequest{ShoppingcartId: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(cart.Items) != 4 {
+			t.Fatalf("Expected %d items, got %d", 4, len(cart.Items))
+		}
+
+		if len(cart.SavedItems) != 2 {
+			t.Fatalf("Expected %d saved items, got %d", 2, len(cart.SavedItems))
+		}
+	})
+
+	t.Run("get shopping cart which doesn't belong to this user", func(t *testing.T) {
+		_, err := client.Get(withCredentials("hacker", "password"), &pb.GetRequest{ShoppingcartId: 1})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected status code %s, but got %s", codes.NotFound, status.Code(err))
+		}
+	})
+}
+
+func TestServer_AddProduct(t *testing.T) {
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+	})
+
+	client := newClient(t, handler.Services{
+		ShoppingCart: services.ShoppingCart,
+		Auth:         auth.New(),
+	})
+
+	ctx := withCredentials("test", "test")
+
+	t.Run("add existing product", func(t *testing.T) {
+		item, err := client.AddProduct(ctx, &pb.AddProductRequest{ShoppingcartId: 1, ProductId: 2, Quantity: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if item.Quantity != 11 {
+			t.Fatalf("Expected %d product quantity, got %d", 11, item.Quantity)
+		}
+	})
+
+	t.Run("add existing variant", func(t *testing.T) {
+		item, err := client.AddProduct(ctx, &pb.AddProductRequest{ShoppingcartId: 1, ProductId: 3, VariantId: "L", Quantity: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if item.Id != 4 || item.Quantity != 3 {
+			t.Fatalf("Expected line %d with quantity %d, got line %d with quantity %d", 4, 3, item.Id, item.Quantity)
+		}
+	})
+
+	t.Run("add product with attributes as a new line", func(t *testing.T) {
+		attributes := map[string]string{"gift_message": "Enjoy"}
+		item, err := client.AddProduct(ctx, &pb.AddProductRequest{ShoppingcartId: 1, ProductId: 2, Attributes: attributes, Quantity: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if item.Quantity != 1 || item.Attributes["gift_message"] != "Enjoy" {
+			t.Fatalf("Expected a new line with attributes %v, got %v", attributes, item)
+		}
+	})
+
+	t.Run("add product without quantity", func(t *testing.T) {
+		_, err := client.AddProduct(ctx, &pb.AddProductRequest{ShoppingcartId: 1, ProductId: 2})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected status code %s, but got %s", codes.InvalidArgument, status.Code(err))
+		}
+	})
+}
+
+func newMemberClient(t *testing.T) pb.ShoppingCartServiceClient {
+	storageMock := &shoppingcart_mock.MockStorage{}
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+		CartMemberStorage:   storageMock,
+	})
+
+	return newClient(t, handler.Services{
+		ShoppingCart: services.ShoppingCart,
+		Auth:         testAuth{},
+	})
+}
+
+func TestServer_Invite(t *testing.T) {
+	client := newMemberClient(t)
+
+	tests := []struct {
+		name     string
+		user     string
+		req      *pb.InviteRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "owner invites editor",
+			user:     "test",
+			req:      &pb.InviteRequest{ShoppingcartId: 1, UserId: 6, Role: shoppingcart.RoleEditor},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "owner invites existing member",
+			user:     "test",
+			req:      &pb.InviteRequest{ShoppingcartId: 1, UserId: 4, Role: shoppingcart.RoleEditor},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name:     "owner invites owner",
+			user:     "test",
+			req:      &pb.InviteRequest{ShoppingcartId: 1, UserId: 6, Role: shoppingcart.RoleOwner},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "viewer invites",
+			user:     "viewer",
+			req:      &pb.InviteRequest{ShoppingcartId: 1, UserId: 6, Role: shoppingcart.RoleViewer},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member, err := client.Invite(withCredentials(tt.user, tt.user), tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected status code %s, but got %s", tt.wantCode, status.Code(err))
+			}
+			if err != nil {
+				return
+			}
+
+			if member.UserId != tt.req.UserId || member.Role != tt.req.Role || member.InvitedBy != 1 || member.AcceptedAt != nil {
+				t.Fatalf("Expected a pending invitation of user %d as %s, got %v", tt.req.UserId, tt.req.Role, member)
+			}
+		})
+	}
+}
+
+func TestServer_AcceptInvitation(t *testing.T) {
+	client := newMemberClient(t)
+
+	t.Run("invited user accepts", func(t *testing.T) {
+		member, err := client.AcceptInvitation(withCredentials("invitee", "invitee"), &pb.AcceptInvitationRequest{ShoppingcartId: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if member.UserId != 5 || member.AcceptedAt == nil {
+			t.Fatalf("Expected accepted invitation of user %d, got %v", 5, member)
+		}
+	})
+
+	t.Run("user without invitation accepts", func(t *testing.T) {
+		_, err := client.AcceptInvitation(withCredentials("hacker", "password"), &pb.AcceptInvitationRequest{ShoppingcartId: 1})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected status code %s, but got %s", codes.NotFound, status.Code(err))
+		}
+	})
+}
+
+func TestServer_RevokeMember(t *testing.T) {
+	client := newMemberClient(t)
+
+	tests := []struct {
+		name     string
+		user     string
+		userID   int64
+		wantCode codes.Code
+	}{
+		{name: "owner revokes viewer", user: "test", userID: 4, wantCode: codes.OK},
+		{name: "viewer leaves", user: "viewer", userID: 4, wantCode: codes.OK},
+		{name: "viewer revokes editor", user: "viewer", userID: 3, wantCode: codes.PermissionDenied},
+		{name: "owner revokes unknown member", user: "test", userID: 6, wantCode: codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.RevokeMember(withCredentials(tt.user, tt.user), &pb.RevokeMemberRequest{ShoppingcartId: 1, UserId: tt.userID})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected status code %s, but got %s", tt.wantCode, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestServer_Get_members(t *testing.T) {
+	client := newMemberClient(t)
+
+	cart, err := client.Get(withCredentials("viewer", "viewer"), &pb.GetRequest{ShoppingcartId: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(cart.Members) != 3 || cart.Members[1].UserId != 4 || cart.Members[1].Role != shoppingcart.RoleViewer {
+		t.Fatalf("Expected the %d members of the shared cart, got %v", 3, cart.Members)
+	}
+}
+
+func TestServer_History(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+		HistoryStorage:      storageMock,
+	})
+
+	client := newClient(t, handler.Services{
+		ShoppingCart: services.ShoppingCart,
+		Auth:         testAuth{},
+	})
+
+	tests := []struct {
+		name        string
+		user        string
+		req         *pb.HistoryRequest
+		wantCode    codes.Code
+		wantEntries int
+	}{
+		{
+			name:        "owner",
+			user:        "test",
+			req:         &pb.HistoryRequest{ShoppingcartId: 1},
+			wantEntries: 3,
+		},
+		{
+			name:        "owner with page",
+			user:        "test",
+			req:         &pb.HistoryRequest{ShoppingcartId: 1, Limit: 1, Offset: 1},
+			wantEntries: 1,
+		},
+		{
+			name:        "admin",
+			user:        "admin",
+			req:         &pb.HistoryRequest{ShoppingcartId: 1},
+			wantEntries: 3,
+		},
+		{
+			name:     "admin reading a missing cart",
+			user:     "admin",
+			req:      &pb.HistoryRequest{ShoppingcartId: 2},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "viewer",
+			user:     "viewer",
+			req:      &pb.HistoryRequest{ShoppingcartId: 1},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "other user",
+			user:     "hacker",
+			req:      &pb.HistoryRequest{ShoppingcartId: 1},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "limit too large",
+			user:     "test",
+			req:      &pb.HistoryRequest{ShoppingcartId: 1, Limit: 1000},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "negative offset",
+			user:     "test",
+			req:      &pb.HistoryRequest{ShoppingcartId: 1, Offset: -1},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.History(withCredentials(tt.user, tt.user), tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected status code %s, but got %s", tt.wantCode, status.Code(err))
+			}
+			if err != nil {
+				return
+			}
+
+			if len(res.Entries) != tt.wantEntries {
+				t.Fatalf("Expected %d history entries, got %d", tt.wantEntries, len(res.Entries))
+			}
+		})
+	}
+}
+
+func TestServer_snapshots(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: storageMock,
+		SnapshotStorage:     storageMock,
+	})
+
+	client := newClient(t, handler.Services{
+		ShoppingCart: services.ShoppingCart,
+		Auth:         testAuth{},
+	})
+
+	t.Run("take snapshot", func(t *testing.T) {
+		snapshot, err := client.Snapshot(withCredentials("test", "test"), &pb.SnapshotRequest{ShoppingcartId: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if snapshot.Reason != shoppingcart.SnapshotManual || len(snapshot.Items) != 4 {
+			t.Fatalf("Expected %s snapshot with %d items, got %v", shoppingcart.SnapshotManual, 4, snapshot)
+		}
+	})
+
+	t.Run("take snapshot as viewer", func(t *testing.T) {
+		_, err := client.Snapshot(withCredentials("viewer", "viewer"), &pb.SnapshotRequest{ShoppingcartId: 1})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("Expected status code %s, but got %s", codes.PermissionDenied, status.Code(err))
+		}
+	})
+
+	t.Run("list snapshots as viewer", func(t *testing.T) {
+		res, err := client.ListSnapshots(withCredentials("viewer", "viewer"), &pb.ListSnapshotsRequest{ShoppingcartId: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(res.Snapshots) != 1 || len(res.Snapshots[0].Items) != 0 {
+			t.Fatalf("Expected %d snapshot without items, got %v", 1, res.Snapshots)
+		}
+	})
+
+	t.Run("list snapshots of other user", func(t *testing.T) {
+		_, err := client.ListSnapshots(withCredentials("hacker", "password"), &pb.ListSnapshotsRequest{ShoppingcartId: 1})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected status code %s, but got %s", codes.NotFound, status.Code(err))
+		}
+	})
+
+	t.Run("get snapshot", func(t *testing.T) {
+		snapshot, err := client.GetSnapshot(withCredentials("test", "test"), &pb.GetSnapshotRequest{ShoppingcartId: 1, SnapshotId: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if snapshot.Id != 1 || len(snapshot.Items) != 2 || snapshot.Items[1].VariantId != "M" {
+			t.Fatalf("Expected snapshot %d with %d items, got %v", 1, 2, snapshot)
+		}
+	})
+
+	t.Run("get unknown snapshot", func(t *testing.T) {
+		_, err := client.GetSnapshot(withCredentials("test", "test"), &pb.GetSnapshotRequest{ShoppingcartId: 1, SnapshotId: 2})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected status code %s, but got %s", codes.NotFound, status.Code(err))
+		}
+	})
+
+	t.Run("restore snapshot", func(t *testing.T) {
+		cart, err := client.Restore(withCredentials("test", "test"), &pb.RestoreRequest{ShoppingcartId: 1, SnapshotId: 1})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if cart.Id != 1 {
+			t.Fatalf("Expected restored cart %d, got %d", 1, cart.Id)
+		}
+	})
+
+	t.Run("restore unknown snapshot", func(t *testing.T) {
+		_, err := client.Restore(withCredentials("test", "test"), &pb.RestoreRequest{ShoppingcartId: 1, SnapshotId: 2})
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected status code %s, but got %s", codes.NotFound, status.Code(err))
+		}
+	})
+
+	t.Run("restore snapshot as viewer", func(t *testing.T) {
+		_, err := client.Restore(withCredentials("viewer", "viewer"), &pb.RestoreRequest{ShoppingcartId: 1, SnapshotId: 1})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("Expected status code %s, but got %s", codes.PermissionDenied, status.Code(err))
+		}
+	})
+}
+
+func TestServer_snapshots_notSupported(t *testing.T) {
+	client := newClient(t, handler.Services{
+		ShoppingCart: service.New(service.Dependencies{ShoppingCartStorage: &shoppingcart_mock.MockStorage{}}).ShoppingCart,
+		Auth:         auth.New(),
+	})
+
+	_, err := client.Snapshot(withCredentials("test", "test"), &pb.SnapshotRequest{ShoppingcartId: 1})
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatalf("Expected status code %s, but got %s", codes.Unimplemented, status.Code(err))
+	}
+}
+
+func TestServer_rateLimit(t *testing.T) {
+	services := service.New(service.Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+	})
+
+	client := newClient(t, handler.Services{
+		ShoppingCart: services.ShoppingCart,
+		Auth:         auth.New(),
+		RateLimiter: ratelimit.New(ratelimit.NewMemory(), ratelimit.Config{
+			Read: ratelimit.Limit{Rate: 0.01, Burst: 1},
+		}),
+	})
+
+	if _, err := client.Get(withCredentials("test", "test"), &pb.GetRequest{ShoppingcartId: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := client.Get(withCredentials("test", "test"), &pb.GetRequest{ShoppingcartId: 1})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected status code %s, but got %s", codes.ResourceExhausted, status.Code(err))
+	}
+
+	// Other reads take from the same bucket, writes are limited separately
+	_, err = client.ListSnapshots(withCredentials("test", "test"), &pb.ListSnapshotsRequest{ShoppingcartId: 1})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected status code %s, but got %s", codes.ResourceExhausted, status.Code(err))
+	}
+	if _, err := client.Create(withCredentials("test", "test"), &pb.CreateRequest{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Calls failing authentication are rejected before they are limited
+	_, err = client.Get(context.Background(), &pb.GetRequest{ShoppingcartId: 1})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Expected status code %s, but got %s", codes.Unauthenticated, status.Code(err))
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/requestid"
+	"github.com/bugimetal/shoppingcart/tracing"
+
+	"go.opencensus.io/trace"
+)
+
+// History retrieves a page of the audit trail of the shopping cart, newest
+// entries first. Only the owner of the cart may read it.
+func (service *ShoppingCart) History(ctx context.Context, shoppingCartID, userID int64, limit, offset int) (entries []shoppingcart.HistoryEntry, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.History")
+	defer func() { tracing.End(span, err) }()
+
+	cart, err := service.storage.Get(ctx, shoppingCartID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cart.RoleOf(userID) != shoppingcart.RoleOwner {
+		return nil, shoppingcart.ErrForbidden
+	}
+
+	return service.AdminHistory(ctx, shoppingCartID, limit, offset)
+}
+
+// AdminHistory retrieves a page of the audit trail of any shopping cart,
+// newest entries first. It is meant for support staff resolving disputes.
+// It fails with ErrNotSupported if no history is kept and with
+// ErrCartNotFound if the cart doesn't exist.
+func (service *ShoppingCart) AdminHistory(ctx context.Context, shoppingCartID int64, limit, offset int) (entries []shoppingcart.HistoryEntry, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.AdminHistory")
+	defer func() { tracing.End(span, err) }()
+
+	if service.history == nil {
+		return nil, shoppingcart.ErrNotSupported
+	}
+
+	return service.history.ListHistory(ctx, shoppingCartID, limit, offset)
+}
+
+// record adds entries to the audit trail, tagged with the ID of the request.
+// It is called within the unit of work of the change, so the change is
+// rolled back if it can't be recorded.
+func (service *ShoppingCart) record(ctx context.Context, entries ...shoppingcart.HistoryEntry) error {
+	if service.history == nil || len(entries) == 0 {
+		return nil
+	}
+
+	requestID := requestid.FromContext(ctx)
+	for i := range entries {
+		entries[i].RequestID = requestID
+	}
+
+	return service.history.AddHistoryEntries(ctx, entries...)
+}
+
+// lineChanges returns the history entries of items being removed by action
+func lineChanges(action string, actorID int64, items []shoppingcart.ShoppingCartItem) []shoppingcart.HistoryEntry {
+	entries := make([]shoppingcart.HistoryEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, shoppingcart.LineChange(action, actorID, item, item.Quantity, 0))
+	}
+
+	return entries
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/requestid"
+)
+
+// historyRecorder keeps the recorded history entries in memory
+type historyRecorder struct {
+	shoppingcart_mock.MockStorage
+	entries []shoppingcart.HistoryEntry
+}
+
+func (recorder *historyRecorder) AddHistoryEntries(ctx context.Context, entries ...shoppingcart.HistoryEntry) error {
+	recorder.entries = append(recorder.entries, entries...)
+	return nil
+}
+
+func TestShoppingCart_record(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(context.Context, *ShoppingCart) error
+		want   []shoppingcart.HistoryEntry
+	}{
+		{
+			name: "add new line",
+			change: func(ctx context.Context, service *ShoppingCart) error {
+				return service.AddProduct(ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 9, Quantity: 2}, 3)
+			},
+			want: []shoppingcart.HistoryEntry{
+				{ShoppingCartID: 1, Action: shoppingcart.ActionAdd, ActorID: 3, ProductID: 9, QuantityAfter: 2},
+			},
+		},
+		{
+			name: "add to existing line",
+			change: func(ctx context.Context, service *ShoppingCart) error {
+				return service.AddProduct(ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 2, Quantity: 2}, 1)
+			},
+			want: []shoppingcart.HistoryEntry{
+				{ShoppingCartID: 1, Action: shoppingcart.ActionAdd, ActorID: 1, ItemID: 2, ProductID: 2, QuantityBefore: 10, QuantityAfter: 12},
+			},
+		},
+		{
+			name: "remove product with variants",
+			change: func(ctx context.Context, service *ShoppingCart) error {
+				return service.RemoveProduct(ctx, 1, 3, 1)
+			},
+			want: []shoppingcart.HistoryEntry{
+				{ShoppingCartID: 1, Action: shoppingcart.ActionRemove, ActorID: 1, ItemID: 3, ProductID: 3, VariantID: "M", QuantityBefore: 1},
+				{ShoppingCartID: 1, Action: shoppingcart.ActionRemove, ActorID: 1, ItemID: 4, ProductID: 3, VariantID: "L", QuantityBefore: 2},
+			},
+		},
+		{
+			name: "save for later",
+			change: func(ctx context.Context, service *ShoppingCart) error {
+				_, err := service.SaveForLater(ctx, 1, 1, 1)
+				return err
+			},
+			want: []shoppingcart.HistoryEntry{
+				{ShoppingCartID: 1, Action: shoppingcart.ActionSaveForLater, ActorID: 1, ItemID: 1, ProductID: 1, QuantityBefore: 1},
+			},
+		},
+		{
+			name: "viewer is not recorded",
+			change: func(ctx context.Context, service *ShoppingCart) error {
+				if err := service.Empty(ctx, 1, 4); err != shoppingcart.ErrForbidden {
+					return err
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &historyRecorder{}
+			service := NewShoppingCart(Dependencies{
+				ShoppingCartStorage: recorder,
+				HistoryStorage:      recorder,
+			})
+
+			ctx := requestid.NewContext(context.Background(), "abc")
+			if err := tt.change(ctx, service); err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if len(recorder.entries) != len(tt.want) {
+				t.Fatalf("Recorded %+v, want %+v", recorder.entries, tt.want)
+			}
+			for i, want := range tt.want {
+				want.RequestID = "abc"
+				if recorder.entries[i] != want {
+					t.Fatalf("Recorded %+v, want %+v", recorder.entries[i], want)
+				}
+			}
+		})
+	}
+}
+
+// failingHistory fails to record history entries
+type failingHistory struct {
+	shoppingcart_mock.MockStorage
+}
+
+var errHistory = errors.New("history unavailable")
+
+func (storage *failingHistory) AddHistoryEntries(ctx context.Context, entries ...shoppingcart.HistoryEntry) error {
+	return errHistory
+}
+
+func TestShoppingCart_record_failure(t *testing.T) {
+	storageMock := &failingHistory{}
+	service := NewShoppingCart(Dependencies{
+		ShoppingCartStorage: storageMock,
+		HistoryStorage:      storageMock,
+	})
+
+	// The change is rolled back along with its history
+	err := service.AddProduct(context.Background(), &shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 9, Quantity: 2}, 1)
+	if err != errHistory {
+		t.Fatalf("AddProduct() error = %v, want %v", err, errHistory)
+	}
+}
+
+func TestShoppingCart_History(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      int64
+		limit       int
+		offset      int
+		wantEntries int
+		wantErr     error
+	}{
+		{
+			name:        "owner",
+			userID:      1,
+			limit:       50,
+			wantEntries: 3,
+		},
+		{
+			name:        "owner second page",
+			userID:      1,
+			limit:       2,
+			offset:      2,
+			wantEntries: 1,
+		},
+		{
+			name:    "editor",
+			userID:  3,
+			limit:   50,
+			wantErr: shoppingcart.ErrForbidden,
+		},
+		{
+			name:    "other user",
+			userID:  2,
+			limit:   50,
+			wantErr: shoppingcart.ErrCartNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := &shoppingcart_mock.MockStorage{}
+			service := NewShoppingCart(Dependencies{
+				ShoppingCartStorage: storageMock,
+				HistoryStorage:      storageMock,
+			})
+
+			entries, err := service.History(context.Background(), 1, tt.userID, tt.limit, tt.offset)
+			if err != tt.wantErr {
+				t.Fatalf("History() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(entries) != tt.wantEntries {
+				t.Fatalf("History() returned %d entries, want %d", len(entries), tt.wantEntries)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_AdminHistory(t *testing.T) {
+	storageMock := &shoppingcart_mock.MockStorage{}
+
+	tests := []struct {
+		name           string
+		dependencies   Dependencies
+		shoppingCartID int64
+		wantEntries    int
+		wantErr        error
+	}{
+		{
+			name:           "any cart",
+			dependencies:   Dependencies{ShoppingCartStorage: storageMock, HistoryStorage: storageMock},
+			shoppingCartID: 1,
+			wantEntries:    3,
+		},
+		{
+			name:           "missing cart",
+			dependencies:   Dependencies{ShoppingCartStorage: storageMock, HistoryStorage: storageMock},
+			shoppingCartID: 2,
+			wantErr:        shoppingcart.ErrCartNotFound,
+		},
+		{
+			name:           "no history storage",
+			dependencies:   Dependencies{ShoppingCartStorage: storageMock},
+			shoppingCartID: 1,
+			wantErr:        shoppingcart.ErrNotSupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewShoppingCart(tt.dependencies)
+
+			entries, err := service.AdminHistory(context.Background(), tt.shoppingCartID, 50, 0)
+			if err != tt.wantErr {
+				t.Fatalf("AdminHistory() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(entries) != tt.wantEntries {
+				t.Fatalf("AdminHistory() returned %d entries, want %d", len(entries), tt.wantEntries)
+			}
+		})
+	}
+}
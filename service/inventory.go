@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+)
+
+// InventoryService describes the interface to the stock service, which holds
+// soft reservations of products for shopping carts. Stock is kept per product
+// variant. Reservations expire unless they are extended, so carts which are
+// abandoned release their stock.
+type InventoryService interface {
+	// Reserve sets the reserved quantity of the product variant for the cart.
+	Reserve(ctx context.Context, shoppingCartID, productID int64, variantID string, quantity uint64) error
+	// Release removes the reservation of the product variant for the cart.
+	Release(ctx context.Context, shoppingCartID, productID int64, variantID string) error
+	// ReleaseAll removes all reservations of the cart.
+	ReleaseAll(ctx context.Context, shoppingCartID int64) error
+	// Extend prolongs all reservations of the cart.
+	Extend(ctx context.Context, shoppingCartID int64) error
+	// Commit converts the reservations of the cart into sold stock.
+	Commit(ctx context.Context, shoppingCartID int64) error
+}
+
+// reserve updates the reservation of the product variant of cartItem, as if
+// its line in the cart held quantity units. Lines of the same variant with
+// other attributes share the reservation. The reservation is restored if the
+// unit of work is rolled back.
+func (service *ShoppingCart) reserve(ctx context.Context, cart shoppingcart.ShoppingCart, cartItem shoppingcart.ShoppingCartItem, quantity uint64) error {
+	if service.inventory == nil {
+		return nil
+	}
+
+	for _, item := range cart.Items {
+		if item.ProductID == cartItem.ProductID && item.VariantID == cartItem.VariantID && !item.SameLine(cartItem) {
+			quantity += item.Quantity
+		}
+	}
+
+	service.keepReservations(ctx, cart, cartItem)
+
+	return service.setReservation(ctx, cart.ID, cartItem, quantity)
+}
+
+// release removes the reservation of the product variant of cartItem, if inventory is in use.
+// The reservation is restored if the unit of work is rolled back.
+func (service *ShoppingCart) release(ctx context.Context, cart shoppingcart.ShoppingCart, cartItem shoppingcart.ShoppingCartItem) error {
+	if service.inventory == nil {
+		return nil
+	}
+
+	service.keepReservations(ctx, cart, cartItem)
+
+	return service.inventory.Release(ctx, cart.ID, cartItem.ProductID, cartItem.VariantID)
+}
+
+// releaseAll removes all reservations of the cart, if inventory is in use.
+// The reservations are restored if the unit of work is rolled back.
+func (service *ShoppingCart) releaseAll(ctx context.Context, cart shoppingcart.ShoppingCart) error {
+	if service.inventory == nil {
+		return nil
+	}
+
+	service.keepReservations(ctx, cart, cart.Items...)
+
+	return service.inventory.ReleaseAll(ctx, cart.ID)
+}
+
+// keepReservations restores the reservations of the product variants of
+// items to the quantities of cart if the unit of work is rolled back
+func (service *ShoppingCart) keepReservations(ctx context.Context, cart shoppingcart.ShoppingCart, items ...shoppingcart.ShoppingCartItem) {
+	whenRolledBack(ctx, func(ctx context.Context) {
+		for _, item := range items {
+			var quantity uint64
+			for _, line := range cart.Items {
+				if line.ProductID == item.ProductID && line.VariantID == item.VariantID {
+					quantity += line.Quantity
+				}
+			}
+
+			if err := service.setReservation(ctx, cart.ID, item, quantity); err != nil {
+				logging.FromContext(ctx).Errorf("Unable to restore reservation of product %d of shopping cart %d: %s", item.ProductID, cart.ID, err)
+			}
+		}
+	})
+}
+
+// setReservation sets the reservation of the product variant of cartItem to
+// quantity, releasing it if there is nothing to reserve
+func (service *ShoppingCart) setReservation(ctx context.Context, shoppingCartID int64, cartItem shoppingcart.ShoppingCartItem, quantity uint64) error {
+	if quantity == 0 {
+		return service.inventory.Release(ctx, shoppingCartID, cartItem.ProductID, cartItem.VariantID)
+	}
+
+	return service.inventory.Reserve(ctx, shoppingCartID, cartItem.ProductID, cartItem.VariantID, quantity)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/inventory"
+)
+
+func TestShoppingCart_inventory(t *testing.T) {
+	ctx := context.Background()
+
+	// The mocked cart 1 of user 1 holds product 1 (quantity 1), product 2 (quantity 10)
+	// and product 3 in variants M (quantity 1) and L (quantity 2)
+	stock := inventory.NewLocal(map[int64]uint64{1: 5, 2: 12, 5: 2}, time.Minute)
+	stock.SetStock(3, "L", 3)
+
+	service := NewShoppingCart(Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+		InventoryService:    stock,
+	})
+
+	t.Run("add product out of stock", func(t *testing.T) {
+		item := shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 5, Quantity: 3}
+		if err := service.AddProduct(ctx, &item, 1); err != shoppingcart.ErrOutOfStock {
+			t.Fatalf("AddProduct() error = %v, want %v", err, shoppingcart.ErrOutOfStock)
+		}
+	})
+
+	t.Run("increasing quantity reserves the whole line", func(t *testing.T) {
+		item := shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 2, Quantity: 1}
+		if err := service.AddProduct(ctx, &item, 1); err != nil {
+			t.Fatalf("AddProduct() unexpected error = %v", err)
+		}
+
+		if available, _ := stock.Available(2, ""); available != 1 {
+			t.Fatalf("Available() = %d, want %d", available, 1)
+		}
+	})
+
+	t.Run("removing product releases the reservation", func(t *testing.T) {
+		if err := service.RemoveProduct(ctx, 1, 2, 1); err != nil {
+			t.Fatalf("RemoveProduct() unexpected error = %v", err)
+		}
+
+		if available, _ := stock.Available(2, ""); available != 12 {
+			t.Fatalf("Available() = %d, want %d", available, 12)
+		}
+	})
+
+	t.Run("lines of the same variant share the reservation", func(t *testing.T) {
+		item := shoppingcart.ShoppingCartItem{
+			ShoppingCartID: 1,
+			ProductID:      3,
+			VariantID:      "L",
+			Attributes:     shoppingcart.Attributes{"engraving": "For Anna"},
+			Quantity:       1,
+		}
+		if err := service.AddProduct(ctx, &item, 1); err != nil {
+			t.Fatalf("AddProduct() unexpected error = %v", err)
+		}
+
+		if available, _ := stock.Available(3, "L"); available != 0 {
+			t.Fatalf("Available() = %d, want %d", available, 0)
+		}
+
+		item = shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 3, VariantID: "L", Quantity: 2}
+		if err := service.AddProduct(ctx, &item, 1); err != shoppingcart.ErrOutOfStock {
+			t.Fatalf("AddProduct() error = %v, want %v", err, shoppingcart.ErrOutOfStock)
+		}
+	})
+
+	t.Run("removing the last line of a variant releases the reservation", func(t *testing.T) {
+		if err := service.RemoveItem(ctx, 1, 4, 1); err != nil {
+			t.Fatalf("RemoveItem() unexpected error = %v", err)
+		}
+
+		if available, _ := stock.Available(3, "L"); available != 3 {
+			t.Fatalf("Available() = %d, want %d", available, 3)
+		}
+	})
+
+	t.Run("removing unknown line", func(t *testing.T) {
+		if err := service.RemoveItem(ctx, 1, 99, 1); err != shoppingcart.ErrCartItemNotFound {
+			t.Fatalf("RemoveItem() error = %v, want %v", err, shoppingcart.ErrCartItemNotFound)
+		}
+	})
+
+	t.Run("checkout commits the cart items", func(t *testing.T) {
+		if _, err := service.Checkout(ctx, 1, 1); err != nil {
+			t.Fatalf("Checkout() unexpected error = %v", err)
+		}
+
+		if available, _ := stock.Available(1, ""); available != 4 {
+			t.Fatalf("Available() = %d, want %d", available, 4)
+		}
+
+		if available, _ := stock.Available(2, ""); available != 2 {
+			t.Fatalf("Available() = %d, want %d", available, 2)
+		}
+
+		if available, _ := stock.Available(3, "L"); available != 1 {
+			t.Fatalf("Available() = %d, want %d", available, 1)
+		}
+	})
+}
+
+// failingCommit fails to commit units of work which succeeded
+type failingCommit struct {
+	snapshotRecorder
+}
+
+var errCommit = errors.New("commit failed")
+
+func (storage *failingCommit) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+
+	return errCommit
+}
+
+func TestShoppingCart_inventory_rollback(t *testing.T) {
+	ctx := context.Background()
+
+	// newStock returns the stock with the items of the mocked cart 1 reserved
+	newStock := func(t *testing.T) *inventory.Local {
+		stock := inventory.NewLocal(map[int64]uint64{1: 5, 2: 12, 7: 1}, time.Minute)
+		stock.SetStock(3, "L", 3)
+
+		for _, r := range []struct {
+			productID int64
+			variantID string
+			quantity  uint64
+		}{{1, "", 1}, {2, "", 10}, {3, "L", 2}} {
+			if err := stock.Reserve(ctx, 1, r.productID, r.variantID, r.quantity); err != nil {
+				t.Fatalf("Reserve() unexpected error = %v", err)
+			}
+		}
+
+		return stock
+	}
+
+	expectAvailable := func(t *testing.T, stock *inventory.Local, productID int64, variantID string, want uint64) {
+		t.Helper()
+		if available, _ := stock.Available(productID, variantID); available != want {
+			t.Fatalf("Available(%d, %q) = %d, want %d", productID, variantID, available, want)
+		}
+	}
+
+	t.Run("rejected restore keeps the reservations", func(t *testing.T) {
+		stock := newStock(t)
+		recorder := &snapshotRecorder{}
+		service := NewShoppingCart(Dependencies{
+			ShoppingCartStorage: recorder,
+			SnapshotStorage:     recorder,
+			InventoryService:    stock,
+		})
+
+		// Product 7 of the snapshot is out of stock
+		if _, err := service.Restore(ctx, 1, 1, 1); err != shoppingcart.ErrOutOfStock {
+			t.Fatalf("Restore() error = %v, want %v", err, shoppingcart.ErrOutOfStock)
+		}
+
+		expectAvailable(t, stock, 1, "", 4)
+		expectAvailable(t, stock, 2, "", 2)
+		expectAvailable(t, stock, 3, "L", 1)
+		expectAvailable(t, stock, 7, "", 1)
+	})
+
+	t.Run("checkout rolled back doesn't sell stock", func(t *testing.T) {
+		stock := newStock(t)
+		service := NewShoppingCart(Dependencies{
+			ShoppingCartStorage: &failingCommit{},
+			InventoryService:    stock,
+		})
+
+		if _, err := service.Checkout(ctx, 1, 1); err != errCommit {
+			t.Fatalf("Checkout() error = %v, want %v", err, errCommit)
+		}
+
+		// The reservations are kept, and nothing is sold once they are released
+		expectAvailable(t, stock, 1, "", 4)
+		expectAvailable(t, stock, 2, "", 2)
+
+		if err := stock.ReleaseAll(ctx, 1); err != nil {
+			t.Fatalf("ReleaseAll() unexpected error = %v", err)
+		}
+		expectAvailable(t, stock, 1, "", 5)
+		expectAvailable(t, stock, 2, "", 12)
+	})
+}
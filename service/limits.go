@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// Limits restrict the quantities of products in a shopping cart. Zero values
+// mean there is no limit.
+type Limits struct {
+	// MaxLineQuantity is the maximum quantity of a single product.
+	MaxLineQuantity uint64
+	// MaxLines is the maximum number of distinct products in a cart.
+	MaxLines int
+	// MaxTotalQuantity is the maximum number of units in a cart.
+	MaxTotalQuantity uint64
+	// ProductMaxQuantity overrides MaxLineQuantity for specific products.
+	ProductMaxQuantity map[int64]uint64
+}
+
+// ProductCatalog describes the interface to the catalog holding the quantity
+// limits of individual products.
+type ProductCatalog interface {
+	// MaxQuantity returns the maximum quantity of the product in a single cart.
+	// It reports false if the product has no specific limit.
+	MaxQuantity(ctx context.Context, productID int64) (uint64, bool, error)
+}
+
+// maxLineQuantity returns the maximum quantity of the product. Overrides from
+// the configuration take precedence over the catalog.
+func (service *ShoppingCart) maxLineQuantity(ctx context.Context, productID int64) (uint64, error) {
+	if max, ok := service.limits.ProductMaxQuantity[productID]; ok {
+		return max, nil
+	}
+
+	if service.catalog != nil {
+		max, ok, err := service.catalog.MaxQuantity(ctx, productID)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return max, nil
+		}
+	}
+
+	return service.limits.MaxLineQuantity, nil
+}
+
+// checkLimits verifies that the cart stays within limits if the quantity of
+// the line of cartItem is changed to quantity. The quantity of a product is
+// limited across all its lines, whatever their variants and attributes.
+func (service *ShoppingCart) checkLimits(ctx context.Context, cart shoppingcart.ShoppingCart, cartItem shoppingcart.ShoppingCartItem, quantity uint64) error {
+	maxLineQuantity, err := service.maxLineQuantity(ctx, cartItem.ProductID)
+	if err != nil {
+		return err
+	}
+
+	productQuantity := quantity
+	for _, item := range cart.Items {
+		if item.ProductID == cartItem.ProductID && !item.SameLine(cartItem) {
+			productQuantity += item.Quantity
+		}
+	}
+
+	if maxLineQuantity != 0 && productQuantity > maxLineQuantity {
+		return &shoppingcart.QuantityLimitError{
+			Limit:     shoppingcart.LimitLineQuantity,
+			ProductID: cartItem.ProductID,
+			Max:       maxLineQuantity,
+		}
+	}
+
+	lines := len(cart.Items)
+	totalQuantity := quantity
+	for _, item := range cart.Items {
+		if item.SameLine(cartItem) {
+			lines--
+			continue
+		}
+		totalQuantity += item.Quantity
+	}
+
+	if service.limits.MaxLines != 0 && lines+1 > service.limits.MaxLines {
+		return &shoppingcart.QuantityLimitError{
+			Limit: shoppingcart.LimitCartLines,
+			Max:   uint64(service.limits.MaxLines),
+		}
+	}
+
+	if service.limits.MaxTotalQuantity != 0 && totalQuantity > service.limits.MaxTotalQuantity {
+		return &shoppingcart.QuantityLimitError{
+			Limit: shoppingcart.LimitCartQuantity,
+			Max:   service.limits.MaxTotalQuantity,
+		}
+	}
+
+	return nil
+}
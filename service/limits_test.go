@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+)
+
+type catalogMock map[int64]uint64
+
+func (catalog catalogMock) MaxQuantity(ctx context.Context, productID int64) (uint64, bool, error) {
+	max, ok := catalog[productID]
+	return max, ok, nil
+}
+
+func TestShoppingCart_AddProduct_limits(t *testing.T) {
+	// The mocked cart 1 of user 1 holds product 1 (quantity 1), product 2 (quantity 10)
+	// and product 3 in variants M (quantity 1) and L (quantity 2)
+	tests := []struct {
+		name    string
+		limits  Limits
+		catalog ProductCatalog
+		item    shoppingcart.ShoppingCartItem
+		wantErr error
+	}{
+		{
+			name:   "no limits",
+			limits: Limits{},
+			item:   shoppingcart.ShoppingCartItem{ProductID: 2, Quantity: 1000},
+		},
+		{
+			name:    "line quantity exceeded by existing product",
+			limits:  Limits{MaxLineQuantity: 10},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 2, Quantity: 1},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitLineQuantity, ProductID: 2, Max: 10},
+		},
+		{
+			name:   "line quantity overridden for product",
+			limits: Limits{MaxLineQuantity: 10, ProductMaxQuantity: map[int64]uint64{2: 20}},
+			item:   shoppingcart.ShoppingCartItem{ProductID: 2, Quantity: 5},
+		},
+		{
+			name:    "line quantity limited by catalog",
+			limits:  Limits{MaxLineQuantity: 10},
+			catalog: catalogMock{5: 1},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 5, Quantity: 2},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitLineQuantity, ProductID: 5, Max: 1},
+		},
+		{
+			name:    "product quantity exceeded by other attributes",
+			limits:  Limits{ProductMaxQuantity: map[int64]uint64{1: 1}},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 1, Attributes: shoppingcart.Attributes{"engraving": "For Anna"}, Quantity: 1},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitLineQuantity, ProductID: 1, Max: 1},
+		},
+		{
+			name:    "product quantity exceeded by another variant",
+			catalog: catalogMock{3: 3},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 3, VariantID: "S", Quantity: 1},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitLineQuantity, ProductID: 3, Max: 3},
+		},
+		{
+			name:    "product quantity counts the other lines once",
+			catalog: catalogMock{3: 4},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 3, VariantID: "L", Quantity: 1},
+		},
+		{
+			name:    "too many lines",
+			limits:  Limits{MaxLines: 4},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 5, Quantity: 1},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitCartLines, Max: 4},
+		},
+		{
+			name:   "existing line doesn't count as a new one",
+			limits: Limits{MaxLines: 4},
+			item:   shoppingcart.ShoppingCartItem{ProductID: 3, VariantID: "L", Quantity: 1},
+		},
+		{
+			name:    "new variant counts as a new line",
+			limits:  Limits{MaxLines: 4},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 3, VariantID: "XL", Quantity: 1},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitCartLines, Max: 4},
+		},
+		{
+			name:    "new attributes count as a new line",
+			limits:  Limits{MaxLines: 4},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 1, Attributes: shoppingcart.Attributes{"gift_message": "Enjoy"}, Quantity: 1},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitCartLines, Max: 4},
+		},
+		{
+			name:    "too many units",
+			limits:  Limits{MaxTotalQuantity: 15},
+			item:    shoppingcart.ShoppingCartItem{ProductID: 5, Quantity: 2},
+			wantErr: &shoppingcart.QuantityLimitError{Limit: shoppingcart.LimitCartQuantity, Max: 15},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewShoppingCart(Dependencies{
+				ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+				ProductCatalog:      tt.catalog,
+				Limits:              tt.limits,
+			})
+
+			item := tt.item
+			item.ShoppingCartID = 1
+
+			err := service.AddProduct(context.Background(), &item, 1)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("AddProduct() unexpected error = %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, shoppingcart.ErrQuantityLimitExceeded) || !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("AddProduct() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/tracing"
+
+	"go.opencensus.io/trace"
+)
+
+// Invite invites a user to the shopping cart with the role of member. Only
+// the owner may invite. The invited user gets access after accepting.
+func (service *ShoppingCart) Invite(ctx context.Context, shoppingCartID, userID int64, member *shoppingcart.CartMember) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.Invite")
+	defer func() { tracing.End(span, err) }()
+
+	if service.members == nil {
+		return shoppingcart.ErrNotSupported
+	}
+
+	if err := member.Validate(); err != nil {
+		return err
+	}
+
+	return service.inTx(ctx, func(ctx context.Context) error {
+		cart, err := service.Get(ctx, shoppingCartID, userID)
+		if err != nil {
+			return err
+		}
+
+		if cart.RoleOf(userID) != shoppingcart.RoleOwner {
+			return shoppingcart.ErrForbidden
+		}
+
+		if _, err := cart.GetMember(member.UserID); err == nil || member.UserID == cart.UserID {
+			return shoppingcart.ErrMemberAlreadyExists
+		}
+
+		member.ShoppingCartID = shoppingCartID
+		member.InvitedBy = userID
+		member.AcceptedAt = nil
+
+		return service.members.AddMember(ctx, member)
+	})
+}
+
+// AcceptInvitation grants the invited user access to the shopping cart.
+// The accepted membership is returned.
+func (service *ShoppingCart) AcceptInvitation(ctx context.Context, shoppingCartID, userID int64) (member shoppingcart.CartMember, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.AcceptInvitation")
+	defer func() { tracing.End(span, err) }()
+
+	if service.members == nil {
+		return shoppingcart.CartMember{}, shoppingcart.ErrNotSupported
+	}
+
+	err = service.inTx(ctx, func(ctx context.Context) error {
+		if member, err = service.members.GetMember(ctx, shoppingCartID, userID); err != nil {
+			return err
+		}
+
+		if member.Accepted() {
+			return nil
+		}
+
+		acceptedAt := time.Now()
+		member.AcceptedAt = &acceptedAt
+
+		return service.members.UpdateMember(ctx, &member)
+	})
+
+	return member, err
+}
+
+// RevokeMember removes a member, or a pending invitation, from the shopping
+// cart. The owner may revoke anyone, members may only leave the cart themselves.
+func (service *ShoppingCart) RevokeMember(ctx context.Context, shoppingCartID, userID, memberUserID int64) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.RevokeMember")
+	defer func() { tracing.End(span, err) }()
+
+	if service.members == nil {
+		return shoppingcart.ErrNotSupported
+	}
+
+	return service.inTx(ctx, func(ctx context.Context) error {
+		if memberUserID != userID {
+			cart, err := service.Get(ctx, shoppingCartID, userID)
+			if err != nil {
+				return err
+			}
+
+			if cart.RoleOf(userID) != shoppingcart.RoleOwner {
+				return shoppingcart.ErrForbidden
+			}
+		}
+
+		if _, err := service.members.GetMember(ctx, shoppingCartID, memberUserID); err != nil {
+			return err
+		}
+
+		return service.members.RemoveMember(ctx, shoppingCartID, memberUserID)
+	})
+}
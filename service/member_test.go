@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+)
+
+// The mocked cart 1 is owned by user 1, user 3 is an editor, user 4 a viewer
+// and user 5 is invited as an editor
+
+func newMemberService() *ShoppingCart {
+	storageMock := &shoppingcart_mock.MockStorage{}
+
+	return NewShoppingCart(Dependencies{
+		ShoppingCartStorage: storageMock,
+		CartMemberStorage:   storageMock,
+	})
+}
+
+func TestShoppingCart_AddProductMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int64
+		wantErr error
+	}{
+		{
+			name:   "owner",
+			userID: 1,
+		},
+		{
+			name:   "editor",
+			userID: 3,
+		},
+		{
+			name:    "viewer",
+			userID:  4,
+			wantErr: shoppingcart.ErrForbidden,
+		},
+		{
+			name:    "invited user",
+			userID:  5,
+			wantErr: shoppingcart.ErrCartNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newMemberService()
+
+			cartItem := shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 9, Quantity: 1}
+			err := service.AddProduct(context.Background(), &cartItem, tt.userID)
+			if err != tt.wantErr {
+				t.Fatalf("AddProduct() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && cartItem.AddedBy != tt.userID {
+				t.Fatalf("AddProduct() added by %d, want %d", cartItem.AddedBy, tt.userID)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_Invite(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int64
+		member  shoppingcart.CartMember
+		wantErr error
+	}{
+		{
+			name:   "owner invites editor",
+			userID: 1,
+			member: shoppingcart.CartMember{UserID: 6, Role: shoppingcart.RoleEditor},
+		},
+		{
+			name:    "editor invites",
+			userID:  3,
+			member:  shoppingcart.CartMember{UserID: 6, Role: shoppingcart.RoleViewer},
+			wantErr: shoppingcart.ErrForbidden,
+		},
+		{
+			name:    "owner invites existing member",
+			userID:  1,
+			member:  shoppingcart.CartMember{UserID: 5, Role: shoppingcart.RoleViewer},
+			wantErr: shoppingcart.ErrMemberAlreadyExists,
+		},
+		{
+			name:    "owner invites themselves",
+			userID:  1,
+			member:  shoppingcart.CartMember{UserID: 1, Role: shoppingcart.RoleEditor},
+			wantErr: shoppingcart.ErrMemberAlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newMemberService()
+
+			member := tt.member
+			err := service.Invite(context.Background(), 1, tt.userID, &member)
+			if err != tt.wantErr {
+				t.Fatalf("Invite() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (member.InvitedBy != tt.userID || member.ShoppingCartID != 1 || member.Accepted()) {
+				t.Fatalf("Invite() = %+v, want pending invitation to cart %d by %d", member, 1, tt.userID)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_AcceptInvitation(t *testing.T) {
+	service := newMemberService()
+
+	member, err := service.AcceptInvitation(context.Background(), 1, 5)
+	if err != nil || !member.Accepted() {
+		t.Fatalf("AcceptInvitation() = %+v, %v, want accepted member", member, err)
+	}
+
+	if _, err := service.AcceptInvitation(context.Background(), 1, 6); err != shoppingcart.ErrMemberNotFound {
+		t.Fatalf("AcceptInvitation() error = %v, want %v", err, shoppingcart.ErrMemberNotFound)
+	}
+}
+
+func TestShoppingCart_RevokeMember(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       int64
+		memberUserID int64
+		wantErr      error
+	}{
+		{
+			name:         "owner revokes editor",
+			userID:       1,
+			memberUserID: 3,
+		},
+		{
+			name:         "viewer leaves",
+			userID:       4,
+			memberUserID: 4,
+		},
+		{
+			name:         "invited user declines",
+			userID:       5,
+			memberUserID: 5,
+		},
+		{
+			name:         "editor revokes viewer",
+			userID:       3,
+			memberUserID: 4,
+			wantErr:      shoppingcart.ErrForbidden,
+		},
+		{
+			name:         "owner revokes unknown user",
+			userID:       1,
+			memberUserID: 6,
+			wantErr:      shoppingcart.ErrMemberNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newMemberService()
+
+			if err := service.RevokeMember(context.Background(), 1, tt.userID, tt.memberUserID); err != tt.wantErr {
+				t.Fatalf("RevokeMember() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_members_notSupported(t *testing.T) {
+	ctx := context.Background()
+	service := NewShoppingCart(Dependencies{ShoppingCartStorage: &shoppingcart_mock.MockStorage{}})
+
+	if err := service.Invite(ctx, 1, 1, &shoppingcart.CartMember{UserID: 6, Role: shoppingcart.RoleViewer}); err != shoppingcart.ErrNotSupported {
+		t.Fatalf("Invite() error = %v, want %v", err, shoppingcart.ErrNotSupported)
+	}
+	if _, err := service.AcceptInvitation(ctx, 1, 5); err != shoppingcart.ErrNotSupported {
+		t.Fatalf("AcceptInvitation() error = %v, want %v", err, shoppingcart.ErrNotSupported)
+	}
+	if err := service.RevokeMember(ctx, 1, 1, 3); err != shoppingcart.ErrNotSupported {
+		t.Fatalf("RevokeMember() error = %v, want %v", err, shoppingcart.ErrNotSupported)
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage"
+	"github.com/bugimetal/shoppingcart/tracing"
+
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opencensus.io/trace"
+)
+
+// Outcomes of operations
+const (
+	outcomeSuccess         = "success"
+	outcomeNotFound        = "not_found"
+	outcomeValidationError = "validation_error"
+	outcomeForbidden       = "forbidden"
+	outcomeError           = "error"
+)
+
+var (
+	// CartsCreated counts the shopping carts created
+	CartsCreated = stats.Int64("shoppingcart/carts_created", "Number of shopping carts created", stats.UnitDimensionless)
+
+	// ItemsAdded counts the products added to shopping carts
+	ItemsAdded = stats.Int64("shoppingcart/items_added", "Number of products added to shopping carts", stats.UnitDimensionless)
+
+	// ItemsRemoved counts the products and lines removed from shopping carts
+	ItemsRemoved = stats.Int64("shoppingcart/items_removed", "Number of products removed from shopping carts", stats.UnitDimensionless)
+
+	// CartsEmptied counts the shopping carts emptied
+	CartsEmptied = stats.Int64("shoppingcart/carts_emptied", "Number of shopping carts emptied", stats.UnitDimensionless)
+
+	// CartsRestored counts the shopping carts restored from a snapshot
+	CartsRestored = stats.Int64("shoppingcart/carts_restored", "Number of shopping carts restored from snapshots", stats.UnitDimensionless)
+
+	// ItemQuantity is the quantity of a product added to a shopping cart
+	ItemQuantity = stats.Int64("shoppingcart/item_quantity", "Quantity of products added to shopping carts", stats.UnitDimensionless)
+
+	// CartSize is the number of active lines of a shopping cart at checkout
+	CartSize = stats.Int64("shoppingcart/cart_size", "Number of lines of shopping carts at checkout", stats.UnitDimensionless)
+
+	// TimeToFirstItem is the time from the creation of a shopping cart until
+	// a product is added to it while it has no lines
+	TimeToFirstItem = stats.Float64("shoppingcart/time_to_first_item", "Time from the creation of shopping carts to their first item", "s")
+
+	// StorageLatency is the duration of calls to the shopping cart storage
+	StorageLatency = stats.Float64("shoppingcart/storage_latency", "Latency of shopping cart storage calls", stats.UnitMilliseconds)
+
+	// Outcome is either success, not_found, validation_error, forbidden or
+	// error for any other failure
+	Outcome = tag.MustNewKey("outcome")
+
+	// Method is the method of the storage which was called
+	Method = tag.MustNewKey("method")
+
+	sizeDistribution = view.Distribution(1, 2, 3, 5, 10, 20, 50, 100)
+
+	// Views export the measures of the service
+	Views = []*view.View{
+		{Name: "carts_created", Description: "Number of shopping carts created by outcome", Measure: CartsCreated, TagKeys: []tag.Key{Outcome}, Aggregation: view.Count()},
+		{Name: "items_added", Description: "Number of products added to shopping carts by outcome", Measure: ItemsAdded, TagKeys: []tag.Key{Outcome}, Aggregation: view.Count()},
+		{Name: "items_removed", Description: "Number of products removed from shopping carts by outcome", Measure: ItemsRemoved, TagKeys: []tag.Key{Outcome}, Aggregation: view.Count()},
+		{Name: "carts_emptied", Description: "Number of shopping carts emptied by outcome", Measure: CartsEmptied, TagKeys: []tag.Key{Outcome}, Aggregation: view.Count()},
+		{Name: "carts_restored", Description: "Number of shopping carts restored from snapshots by outcome", Measure: CartsRestored, TagKeys: []tag.Key{Outcome}, Aggregation: view.Count()},
+		{Name: "item_quantity", Description: "Quantity of products added to shopping carts by outcome", Measure: ItemQuantity, TagKeys: []tag.Key{Outcome}, Aggregation: sizeDistribution},
+		{Name: "cart_size", Description: "Number of lines of shopping carts at checkout by outcome", Measure: CartSize, TagKeys: []tag.Key{Outcome}, Aggregation: sizeDistribution},
+		{
+			Name:        "time_to_first_item_seconds",
+			Description: "Time from the creation of shopping carts to their first item",
+			Measure:     TimeToFirstItem,
+			TagKeys:     []tag.Key{Outcome},
+			Aggregation: view.Distribution(1, 5, 15, 30, 60, 300, 900, 3600, 86400),
+		},
+		{
+			Name:        "storage_latency_ms",
+			Description: "Latency of shopping cart storage calls by method and outcome",
+			Measure:     StorageLatency,
+			TagKeys:     []tag.Key{Method, Outcome},
+			Aggregation: view.Distribution(1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000),
+		},
+	}
+)
+
+func init() {
+	if err := view.Register(Views...); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// outcome classifies the error of an operation
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case errors.Is(err, shoppingcart.ErrCartNotFound), errors.Is(err, shoppingcart.ErrCartItemNotFound),
+		errors.Is(err, shoppingcart.ErrSnapshotNotFound):
+		return outcomeNotFound
+	case errors.Is(err, shoppingcart.ErrValidation), errors.Is(err, shoppingcart.ErrUserNotSet),
+		errors.Is(err, shoppingcart.ErrCartItemNoProductSet), errors.Is(err, shoppingcart.ErrCartItemNoQuantitySet):
+		return outcomeValidationError
+	case errors.Is(err, shoppingcart.ErrForbidden), errors.Is(err, shoppingcart.ErrNoPermission):
+		return outcomeForbidden
+	}
+
+	return outcomeError
+}
+
+// recordOutcome records the measurements of an operation which ended with err
+func recordOutcome(ctx context.Context, err error, ms ...stats.Measurement) {
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(Outcome, outcome(err))}, ms...)
+}
+
+// instrumentedStorage traces every call to the shopping cart storage and
+// records its latency, including the calls within units of work
+type instrumentedStorage struct {
+	carts storage.ShoppingCart
+}
+
+// observe starts the span of a call to the storage, named after the method,
+// and tagged with the method metric. done ends the span and records the
+// latency once the call returns err.
+func (s instrumentedStorage) observe(ctx context.Context, name, method string) (_ context.Context, done func(err error)) {
+	start := time.Now()
+	ctx, span := trace.StartSpan(ctx, "storage.ShoppingCart."+name)
+
+	return ctx, func(err error) {
+		tracing.End(span, err)
+
+		latency := float64(time.Since(start)) / float64(time.Millisecond)
+		_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(Method, method), tag.Upsert(Outcome, outcome(err))}, StorageLatency.M(latency))
+	}
+}
+
+func (s instrumentedStorage) Create(ctx context.Context, cart *shoppingcart.ShoppingCart) (err error) {
+	ctx, done := s.observe(ctx, "Create", "create")
+	defer func() { done(err) }()
+	return s.carts.Create(ctx, cart)
+}
+
+func (s instrumentedStorage) Get(ctx context.Context, shoppingCartID, userID int64) (cart shoppingcart.ShoppingCart, err error) {
+	ctx, done := s.observe(ctx, "Get", "get")
+	defer func() { done(err) }()
+	return s.carts.Get(ctx, shoppingCartID, userID)
+}
+
+func (s instrumentedStorage) Empty(ctx context.Context, shoppingCartID int64) (err error) {
+	ctx, done := s.observe(ctx, "Empty", "empty")
+	defer func() { done(err) }()
+	return s.carts.Empty(ctx, shoppingCartID)
+}
+
+func (s instrumentedStorage) AddProduct(ctx context.Context, item *shoppingcart.ShoppingCartItem) (err error) {
+	ctx, done := s.observe(ctx, "AddProduct", "add_product")
+	defer func() { done(err) }()
+	return s.carts.AddProduct(ctx, item)
+}
+
+func (s instrumentedStorage) UpdateProduct(ctx context.Context, item *shoppingcart.ShoppingCartItem) (err error) {
+	ctx, done := s.observe(ctx, "UpdateProduct", "update_product")
+	defer func() { done(err) }()
+	return s.carts.UpdateProduct(ctx, item)
+}
+
+func (s instrumentedStorage) RemoveProduct(ctx context.Context, shoppingCartID, productID int64) (err error) {
+	ctx, done := s.observe(ctx, "RemoveProduct", "remove_product")
+	defer func() { done(err) }()
+	return s.carts.RemoveProduct(ctx, shoppingCartID, productID)
+}
+
+func (s instrumentedStorage) RemoveItem(ctx context.Context, shoppingCartID, itemID int64) (err error) {
+	ctx, done := s.observe(ctx, "RemoveItem", "remove_item")
+	defer func() { done(err) }()
+	return s.carts.RemoveItem(ctx, shoppingCartID, itemID)
+}
+
+// WithTx observes the whole unit of work, the calls within it are observed
+// as well
+func (s instrumentedStorage) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	ctx, done := s.observe(ctx, "WithTx", "with_tx")
+	defer func() { done(err) }()
+	return s.carts.WithTx(ctx, fn)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+
+	"go.opencensus.io/stats/view"
+)
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "success", want: outcomeSuccess},
+		{name: "cart not found", err: shoppingcart.ErrCartNotFound, want: outcomeNotFound},
+		{name: "wrapped item not found", err: fmt.Errorf("remove: %w", shoppingcart.ErrCartItemNotFound), want: outcomeNotFound},
+		{name: "snapshot not found", err: shoppingcart.ErrSnapshotNotFound, want: outcomeNotFound},
+		{name: "validation", err: shoppingcart.ValidationErrors{{Field: "quantity", Err: shoppingcart.ErrCartItemNoQuantitySet}}, want: outcomeValidationError},
+		{name: "forbidden", err: shoppingcart.ErrForbidden, want: outcomeForbidden},
+		{name: "other", err: shoppingcart.ErrStorageTimeout, want: outcomeError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outcome(tt.err); got != tt.want {
+				t.Fatalf("Expected outcome %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// countByOutcome returns the counts of the view by outcome
+func countByOutcome(t *testing.T, name string) map[string]int64 {
+	rows, err := view.RetrieveData(name)
+	if err != nil {
+		t.Fatalf("Unable to retrieve %s: %s", name, err)
+	}
+
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		for _, tag := range row.Tags {
+			if tag.Key == Outcome {
+				counts[tag.Value] = row.Data.(*view.CountData).Value
+			}
+		}
+	}
+
+	return counts
+}
+
+func TestShoppingCart_AddProduct_metrics(t *testing.T) {
+	service := NewShoppingCart(Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+	})
+	before := countByOutcome(t, "items_added")
+
+	ctx := context.Background()
+	if err := service.AddProduct(ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 3, Quantity: 1}, 1); err != nil {
+		t.Fatalf("Unable to add product: %s", err)
+	}
+	if err := service.AddProduct(ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 3}, 1); err == nil {
+		t.Fatal("Expected adding a product without quantity to fail")
+	}
+
+	after := countByOutcome(t, "items_added")
+	for _, o := range []string{outcomeSuccess, outcomeValidationError} {
+		if after[o]-before[o] != 1 {
+			t.Fatalf("Expected 1 more %s, but got %d", o, after[o]-before[o])
+		}
+	}
+}
+
+func TestShoppingCart_Restore_metrics(t *testing.T) {
+	recorder := &snapshotRecorder{}
+	service := NewShoppingCart(Dependencies{
+		ShoppingCartStorage: recorder,
+		SnapshotStorage:     recorder,
+	})
+	restoredBefore := countByOutcome(t, "carts_restored")
+	addedBefore := countByOutcome(t, "items_added")
+
+	ctx := context.Background()
+	if _, err := service.Restore(ctx, 1, 1, 1); err != nil {
+		t.Fatalf("Unable to restore snapshot: %s", err)
+	}
+	if _, err := service.Restore(ctx, 1, 2, 1); err == nil {
+		t.Fatal("Expected restoring an unknown snapshot to fail")
+	}
+
+	restoredAfter := countByOutcome(t, "carts_restored")
+	for _, o := range []string{outcomeSuccess, outcomeNotFound} {
+		if restoredAfter[o]-restoredBefore[o] != 1 {
+			t.Fatalf("Expected 1 more %s restore, but got %d", o, restoredAfter[o]-restoredBefore[o])
+		}
+	}
+
+	// The restored items are not counted as added by the user
+	addedAfter := countByOutcome(t, "items_added")
+	for o := range addedAfter {
+		if addedAfter[o] != addedBefore[o] {
+			t.Fatalf("Expected no more %s items added, but got %d", o, addedAfter[o]-addedBefore[o])
+		}
+	}
+}
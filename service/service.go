@@ -7,19 +7,61 @@ type ShoppingCartStorage interface {
 	storage.ShoppingCart
 }
 
-// Dependencies list the interfaces that individual services rely on.
+// CartMemberStorage describes the interface to store and retrieve members of shared shopping carts.
+type CartMemberStorage interface {
+	storage.CartMembers
+}
+
+// HistoryStorage describes the interface to store and retrieve the audit trail of shopping carts.
+type HistoryStorage interface {
+	storage.History
+}
+
+// SnapshotStorage describes the interface to store and retrieve snapshots of shopping carts.
+type SnapshotStorage interface {
+	storage.Snapshots
+}
+
+// WishlistStorage describes the interface to store and retrieve wishlists.
+type WishlistStorage interface {
+	storage.Wishlist
+}
+
+// Dependencies list the interfaces that individual services rely on, along
+// with their settings.
 type Dependencies struct {
 	ShoppingCartStorage
+	WishlistStorage
+
+	// CartMemberStorage and SnapshotStorage are optional, without them
+	// sharing carts and snapshots fail with shoppingcart.ErrNotSupported.
+	CartMemberStorage
+	SnapshotStorage
+
+	// ProductCatalog is optional, it provides per-product quantity limits.
+	ProductCatalog ProductCatalog
+	Limits         Limits
+
+	// HistoryStorage is optional, it records every change of shopping carts.
+	// Without it reading the history fails with shoppingcart.ErrNotSupported.
+	HistoryStorage HistoryStorage
+
+	// InventoryService is optional, it reserves stock for products in carts.
+	InventoryService InventoryService
 }
 
 // Services contains all the services that this package has to offer.
 type Services struct {
 	*ShoppingCart
+	*Wishlist
 }
 
 // New returns Services.
 func New(deps Dependencies) *Services {
+	shoppingCart := NewShoppingCart(deps)
+
 	return &Services{
-		ShoppingCart: NewShoppingCart(deps),
+		ShoppingCart: shoppingCart,
+		Wishlist:     NewWishlist(deps, shoppingCart),
 	}
 }
@@ -2,85 +2,467 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/tracing"
+
+	"go.opencensus.io/trace"
 )
 
 // ShoppingCart service responsible for shopping cart operations
+// Carts can be read by their owner and members, but only the owner and
+// editors may modify them.
 type ShoppingCart struct {
-	storage ShoppingCartStorage
+	storage   ShoppingCartStorage
+	members   CartMemberStorage
+	history   HistoryStorage
+	snapshots SnapshotStorage
+	catalog   ProductCatalog
+	limits    Limits
+	inventory InventoryService
 }
 
 // NewShoppingCart returns a new Shopping cart service
 func NewShoppingCart(deps Dependencies) *ShoppingCart {
-	return &ShoppingCart{storage: deps.ShoppingCartStorage}
+	return &ShoppingCart{
+		storage:   instrumentedStorage{carts: deps.ShoppingCartStorage},
+		members:   deps.CartMemberStorage,
+		history:   deps.HistoryStorage,
+		snapshots: deps.SnapshotStorage,
+		catalog:   deps.ProductCatalog,
+		limits:    deps.Limits,
+		inventory: deps.InventoryService,
+	}
 }
 
 // Create creates a new shopping cart in storage
-func (service *ShoppingCart) Create(ctx context.Context, cart *shoppingcart.ShoppingCart) error {
+func (service *ShoppingCart) Create(ctx context.Context, cart *shoppingcart.ShoppingCart) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.Create")
+	defer func() { tracing.End(span, err) }()
+
+	defer func() { recordOutcome(ctx, err, CartsCreated.M(1)) }()
+
 	if err := cart.Validate(); err != nil {
 		return err
 	}
 
-	return service.storage.Create(ctx, cart)
+	return service.inTx(ctx, func(ctx context.Context) error {
+		if err := service.storage.Create(ctx, cart); err != nil {
+			return err
+		}
+
+		return service.record(ctx, shoppingcart.HistoryEntry{
+			ShoppingCartID: cart.ID,
+			Action:         shoppingcart.ActionCreate,
+			ActorID:        cart.UserID,
+		})
+	})
 }
 
 // Get retrieves a shopping cart from the storage
-func (service *ShoppingCart) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
-	return service.storage.Get(ctx, ID, userID)
+// Reservations of the cart items are extended, as the cart is still in use
+func (service *ShoppingCart) Get(ctx context.Context, ID, userID int64) (cart shoppingcart.ShoppingCart, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.Get")
+	defer func() { tracing.End(span, err) }()
+
+	cart, err = service.storage.Get(ctx, ID, userID)
+	if err != nil {
+		return cart, err
+	}
+
+	if service.inventory != nil && len(cart.Items) > 0 {
+		if err := service.inventory.Extend(ctx, cart.ID); err != nil {
+			logging.FromContext(ctx).Errorf("Unable to extend reservations of shopping cart %d: %s", cart.ID, err)
+		}
+	}
+
+	return cart, nil
 }
 
-// Empty removes items associated with shopping cart
-func (service *ShoppingCart) Empty(ctx context.Context, shoppingCartID, userID int64) error {
-	// Checking if shopping cart belong to this user
-	cart, err := service.Get(ctx, shoppingCartID, userID)
+// getEditable retrieves a shopping cart which the user is allowed to modify
+func (service *ShoppingCart) getEditable(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
+	cart, err := service.Get(ctx, ID, userID)
 	if err != nil {
+		return cart, err
+	}
+
+	if !cart.CanEdit(userID) {
+		return cart, shoppingcart.ErrForbidden
+	}
+
+	return cart, nil
+}
+
+// inTx runs fn as a unit of work of the cart storage, so the carts can't
+// change between the checks and the writes of fn. The storages sharing the
+// database of the carts take part in it. Snapshots are taken once the unit
+// of work commits: they are optional and aren't taken of changes which are
+// rolled back. Nested calls join the outer unit of work.
+func (service *ShoppingCart) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runTx(ctx, service.storage, fn)
+}
+
+// Empty removes items associated with shopping cart
+// A snapshot of the items is taken first, so the cart can be restored.
+func (service *ShoppingCart) Empty(ctx context.Context, shoppingCartID, userID int64) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.Empty")
+	defer func() { tracing.End(span, err) }()
+
+	defer func() { recordOutcome(ctx, err, CartsEmptied.M(1)) }()
+
+	return service.inTx(ctx, func(ctx context.Context) error {
+		// Checking if this user is allowed to modify the shopping cart
+		cart, err := service.getEditable(ctx, shoppingCartID, userID)
+		if err != nil {
+			return err
+		}
+
+		if len(cart.Items) == 0 {
+			return nil
+		}
+
+		service.autoSnapshot(ctx, cart, shoppingcart.SnapshotEmpty, userID)
+
+		return service.empty(ctx, cart, userID)
+	})
+}
+
+// empty removes the active items of the cart and releases their reservations
+func (service *ShoppingCart) empty(ctx context.Context, cart shoppingcart.ShoppingCart, userID int64) error {
+	if err := service.storage.Empty(ctx, cart.ID); err != nil {
 		return err
 	}
 
-	if len(cart.Items) == 0 {
-		return nil
+	if err := service.record(ctx, lineChanges(shoppingcart.ActionEmpty, userID, cart.Items)...); err != nil {
+		return err
 	}
 
-	return service.storage.Empty(ctx, shoppingCartID)
+	return service.releaseAll(ctx, cart)
+}
+
+// Checkout finishes shopping: the cart is emptied and, once that is
+// committed, reservations of the cart items are converted into sold stock.
+// A snapshot of the checked out items is taken. The checked out cart is returned.
+func (service *ShoppingCart) Checkout(ctx context.Context, shoppingCartID, userID int64) (cart shoppingcart.ShoppingCart, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.Checkout")
+	defer func() { tracing.End(span, err) }()
+
+	defer func() {
+		if len(cart.Items) > 0 {
+			recordOutcome(ctx, err, CartSize.M(int64(len(cart.Items))))
+		}
+	}()
+
+	err = service.inTx(ctx, func(ctx context.Context) error {
+		if cart, err = service.getEditable(ctx, shoppingCartID, userID); err != nil {
+			return err
+		}
+
+		if len(cart.Items) == 0 {
+			return shoppingcart.ErrCartHasNoItems
+		}
+
+		// Reservations may have expired since the items were added
+		for _, item := range cart.Items {
+			if err := service.reserve(ctx, cart, item, item.Quantity); err != nil {
+				return err
+			}
+		}
+
+		if err := service.storage.Empty(ctx, shoppingCartID); err != nil {
+			return err
+		}
+
+		if err := service.record(ctx, lineChanges(shoppingcart.ActionCheckout, userID, cart.Items)...); err != nil {
+			return err
+		}
+
+		service.autoSnapshot(ctx, cart, shoppingcart.SnapshotCheckout, userID)
+
+		// Sold stock can't be given back, so it is only sold for a
+		// checkout which is committed
+		if service.inventory != nil {
+			whenCommitted(ctx, func(ctx context.Context) {
+				if err := service.inventory.Commit(ctx, shoppingCartID); err != nil {
+					logging.FromContext(ctx).Errorf("Unable to commit reservations of shopping cart %d: %s", shoppingCartID, err)
+				}
+			})
+		}
+
+		return nil
+	})
+
+	return cart, err
 }
 
 // AddProduct adds new product to existing shopping cart
-// If product exists, quantity will be updated
-func (service *ShoppingCart) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem, userID int64) error {
+// If the line of the product, variant and attributes exists, quantity will be updated
+// The resulting quantities are checked against the configured limits
+func (service *ShoppingCart) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem, userID int64) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.AddProduct")
+	defer func() { tracing.End(span, err) }()
+
+	quantity := int64(cartItem.Quantity)
+	defer func() { recordOutcome(ctx, err, ItemsAdded.M(1), ItemQuantity.M(quantity)) }()
+
 	if err := cartItem.Validate(); err != nil {
 		return err
 	}
 
-	cart, err := service.Get(ctx, cartItem.ShoppingCartID, userID)
-	if err != nil {
-		return err
-	}
+	return service.inTx(ctx, func(ctx context.Context) error {
+		cart, err := service.getEditable(ctx, cartItem.ShoppingCartID, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := service.addProduct(ctx, cart, cartItem, userID); err != nil {
+			return err
+		}
+
+		if len(cart.Items) == 0 && len(cart.SavedItems) == 0 {
+			whenCommitted(ctx, func(ctx context.Context) {
+				recordOutcome(ctx, nil, TimeToFirstItem.M(time.Since(cart.CreatedAt).Seconds()))
+			})
+		}
+
+		return nil
+	})
+}
 
-	if cart.HasProduct(cartItem.ProductID) {
-		existingItem, err := cart.GetProduct(cartItem.ProductID)
+// addProduct adds a validated item to the cart, which the user may edit,
+// within the unit of work of ctx
+func (service *ShoppingCart) addProduct(ctx context.Context, cart shoppingcart.ShoppingCart, cartItem *shoppingcart.ShoppingCartItem, userID int64) error {
+	if cart.HasProduct(*cartItem) {
+		existingItem, err := cart.GetProduct(*cartItem)
 		if err != nil {
 			return err
 		}
+
+		if err := service.checkLimits(ctx, cart, *cartItem, existingItem.Quantity+cartItem.Quantity); err != nil {
+			return err
+		}
+
+		if err := service.reserve(ctx, cart, *cartItem, existingItem.Quantity+cartItem.Quantity); err != nil {
+			return err
+		}
+
+		previousQuantity := existingItem.Quantity
 		existingItem.Quantity += cartItem.Quantity
 		*cartItem = existingItem
-		return service.storage.UpdateProduct(ctx, cartItem)
+		if err := service.storage.UpdateProduct(ctx, cartItem); err != nil {
+			return err
+		}
+
+		return service.record(ctx, shoppingcart.LineChange(shoppingcart.ActionAdd, userID, *cartItem, previousQuantity, cartItem.Quantity))
+	}
+
+	if err := service.checkLimits(ctx, cart, *cartItem, cartItem.Quantity); err != nil {
+		return err
+	}
+
+	if err := service.reserve(ctx, cart, *cartItem, cartItem.Quantity); err != nil {
+		return err
 	}
 
-	return service.storage.AddProduct(ctx, cartItem)
+	cartItem.AddedBy = userID
+	if err := service.storage.AddProduct(ctx, cartItem); err != nil {
+		return err
+	}
+
+	return service.record(ctx, shoppingcart.LineChange(shoppingcart.ActionAdd, userID, *cartItem, 0, cartItem.Quantity))
+}
+
+// RemoveProduct removes all lines of a product from existing shopping cart
+func (service *ShoppingCart) RemoveProduct(ctx context.Context, shoppingCartID, productID, userID int64) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.RemoveProduct")
+	defer func() { tracing.End(span, err) }()
+
+	defer func() { recordOutcome(ctx, err, ItemsRemoved.M(1)) }()
+
+	return service.inTx(ctx, func(ctx context.Context) error {
+		return service.removeProduct(ctx, shoppingCartID, productID, userID)
+	})
 }
 
-// RemoveProduct removes a product from existing shopping cart
-func (service *ShoppingCart) RemoveProduct(ctx context.Context, shoppingCartID, productID, userID int64) error {
-	// Checking if shopping cart belong to this user
-	cart, err := service.Get(ctx, shoppingCartID, userID)
+func (service *ShoppingCart) removeProduct(ctx context.Context, shoppingCartID, productID, userID int64) error {
+	// Checking if this user is allowed to modify the shopping cart
+	cart, err := service.getEditable(ctx, shoppingCartID, userID)
 	if err != nil {
 		return err
 	}
 
-	if !cart.HasProduct(productID) {
+	items := cart.ProductItems(productID)
+	if len(items) == 0 {
 		return nil
 	}
 
-	return service.storage.RemoveProduct(ctx, shoppingCartID, productID)
+	if err := service.storage.RemoveProduct(ctx, shoppingCartID, productID); err != nil {
+		return err
+	}
+
+	if err := service.record(ctx, lineChanges(shoppingcart.ActionRemove, userID, items)...); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := service.release(ctx, cart, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveItem removes a single line from existing shopping cart, either active or saved for later
+func (service *ShoppingCart) RemoveItem(ctx context.Context, shoppingCartID, itemID, userID int64) (err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.RemoveItem")
+	defer func() { tracing.End(span, err) }()
+
+	defer func() { recordOutcome(ctx, err, ItemsRemoved.M(1)) }()
+
+	return service.inTx(ctx, func(ctx context.Context) error {
+		return service.removeItem(ctx, shoppingCartID, itemID, userID)
+	})
+}
+
+func (service *ShoppingCart) removeItem(ctx context.Context, shoppingCartID, itemID, userID int64) error {
+	// Checking if this user is allowed to modify the shopping cart
+	cart, err := service.getEditable(ctx, shoppingCartID, userID)
+	if err != nil {
+		return err
+	}
+
+	if savedItem, err := cart.GetSavedItem(itemID); err == nil {
+		if err := service.storage.RemoveItem(ctx, shoppingCartID, itemID); err != nil {
+			return err
+		}
+
+		// Saved lines are not part of the active quantities
+		return service.record(ctx, shoppingcart.LineChange(shoppingcart.ActionRemove, userID, savedItem, 0, 0))
+	}
+
+	item, err := cart.GetItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	if err := service.storage.RemoveItem(ctx, shoppingCartID, itemID); err != nil {
+		return err
+	}
+
+	if err := service.record(ctx, shoppingcart.LineChange(shoppingcart.ActionRemove, userID, item, item.Quantity, 0)); err != nil {
+		return err
+	}
+
+	return service.reserve(ctx, cart, item, 0)
+}
+
+// SaveForLater moves an active line to the items saved for later, keeping its
+// quantity and attributes. If the same line is already saved, quantities are
+// merged. Stock reserved for the line is released. The saved line is returned.
+func (service *ShoppingCart) SaveForLater(ctx context.Context, shoppingCartID, itemID, userID int64) (savedItem shoppingcart.ShoppingCartItem, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.SaveForLater")
+	defer func() { tracing.End(span, err) }()
+
+	err = service.inTx(ctx, func(ctx context.Context) error {
+		savedItem, err = service.saveForLater(ctx, shoppingCartID, itemID, userID)
+		return err
+	})
+
+	return savedItem, err
+}
+
+func (service *ShoppingCart) saveForLater(ctx context.Context, shoppingCartID, itemID, userID int64) (shoppingcart.ShoppingCartItem, error) {
+	cart, err := service.getEditable(ctx, shoppingCartID, userID)
+	if err != nil {
+		return shoppingcart.ShoppingCartItem{}, err
+	}
+
+	item, err := cart.GetItem(itemID)
+	if err != nil {
+		return item, err
+	}
+
+	savedItem, err := service.moveItem(ctx, cart, item, shoppingcart.ListSaved)
+	if err != nil {
+		return savedItem, err
+	}
+
+	if err := service.record(ctx, shoppingcart.LineChange(shoppingcart.ActionSaveForLater, userID, item, item.Quantity, 0)); err != nil {
+		return savedItem, err
+	}
+
+	return savedItem, service.reserve(ctx, cart, item, 0)
+}
+
+// MoveToCart moves a line saved for later back to the active items, keeping
+// its quantity and attributes. If the same line is already in the cart,
+// quantities are merged. Limits and stock are checked as for AddProduct.
+// The active line is returned.
+func (service *ShoppingCart) MoveToCart(ctx context.Context, shoppingCartID, itemID, userID int64) (activeItem shoppingcart.ShoppingCartItem, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.MoveToCart")
+	defer func() { tracing.End(span, err) }()
+
+	err = service.inTx(ctx, func(ctx context.Context) error {
+		activeItem, err = service.moveToCart(ctx, shoppingCartID, itemID, userID)
+		return err
+	})
+
+	return activeItem, err
+}
+
+func (service *ShoppingCart) moveToCart(ctx context.Context, shoppingCartID, itemID, userID int64) (shoppingcart.ShoppingCartItem, error) {
+	cart, err := service.getEditable(ctx, shoppingCartID, userID)
+	if err != nil {
+		return shoppingcart.ShoppingCartItem{}, err
+	}
+
+	item, err := cart.GetSavedItem(itemID)
+	if err != nil {
+		return item, err
+	}
+
+	quantity := item.Quantity
+	if existingItem, err := cart.GetProduct(item); err == nil {
+		quantity += existingItem.Quantity
+	}
+
+	if err := service.checkLimits(ctx, cart, item, quantity); err != nil {
+		return item, err
+	}
+
+	if err := service.reserve(ctx, cart, item, quantity); err != nil {
+		return item, err
+	}
+
+	activeItem, err := service.moveItem(ctx, cart, item, shoppingcart.ListCart)
+	if err != nil {
+		return activeItem, err
+	}
+
+	return activeItem, service.record(ctx, shoppingcart.LineChange(shoppingcart.ActionMoveToCart, userID, activeItem, quantity-item.Quantity, activeItem.Quantity))
+}
+
+// moveItem moves item of the cart to list. If the same line is already in
+// list, item is merged into it, otherwise item itself is moved.
+func (service *ShoppingCart) moveItem(ctx context.Context, cart shoppingcart.ShoppingCart, item shoppingcart.ShoppingCartItem, list string) (shoppingcart.ShoppingCartItem, error) {
+	getTarget := cart.GetProduct
+	if list == shoppingcart.ListSaved {
+		getTarget = cart.GetSavedProduct
+	}
+
+	target, err := getTarget(item)
+	if err != nil {
+		item.List = list
+		return item, service.storage.UpdateProduct(ctx, &item)
+	}
+
+	target.Quantity += item.Quantity
+	if err := service.storage.UpdateProduct(ctx, &target); err != nil {
+		return target, err
+	}
+
+	return target, service.storage.RemoveItem(ctx, cart.ID, item.ID)
 }
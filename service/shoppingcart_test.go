@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/inventory"
+)
+
+// The mocked cart 1 of user 1 has saved product 4 (quantity 1, line 5) and
+// product 2 (quantity 3, line 6) for later, product 2 is in the cart as well
+// (quantity 10, line 2)
+
+func TestShoppingCart_SaveForLater(t *testing.T) {
+	tests := []struct {
+		name         string
+		itemID       int64
+		wantID       int64
+		wantQuantity uint64
+		wantErr      error
+	}{
+		{
+			name:         "save line",
+			itemID:       1,
+			wantID:       1,
+			wantQuantity: 1,
+		},
+		{
+			name:         "save line which is already saved",
+			itemID:       2,
+			wantID:       6,
+			wantQuantity: 13,
+		},
+		{
+			name:    "save line which is not in the cart",
+			itemID:  5,
+			wantErr: shoppingcart.ErrCartItemNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewShoppingCart(Dependencies{
+				ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+			})
+
+			item, err := service.SaveForLater(context.Background(), 1, tt.itemID, 1)
+			if err != tt.wantErr {
+				t.Fatalf("SaveForLater() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if item.ID != tt.wantID || item.Quantity != tt.wantQuantity || !item.Saved() {
+				t.Fatalf("SaveForLater() = %+v, want saved line %d with quantity %d", item, tt.wantID, tt.wantQuantity)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_MoveToCart(t *testing.T) {
+	tests := []struct {
+		name         string
+		limits       Limits
+		itemID       int64
+		wantID       int64
+		wantQuantity uint64
+		wantErr      error
+	}{
+		{
+			name:         "move line",
+			itemID:       5,
+			wantID:       5,
+			wantQuantity: 1,
+		},
+		{
+			name:         "move line which is already in the cart",
+			itemID:       6,
+			wantID:       2,
+			wantQuantity: 13,
+		},
+		{
+			name:    "merged line exceeds the limit",
+			limits:  Limits{MaxLineQuantity: 12},
+			itemID:  6,
+			wantErr: shoppingcart.ErrQuantityLimitExceeded,
+		},
+		{
+			name:    "move line which is not saved",
+			itemID:  1,
+			wantErr: shoppingcart.ErrCartItemNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewShoppingCart(Dependencies{
+				ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+				Limits:              tt.limits,
+			})
+
+			item, err := service.MoveToCart(context.Background(), 1, tt.itemID, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MoveToCart() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if item.ID != tt.wantID || item.Quantity != tt.wantQuantity || item.Saved() {
+				t.Fatalf("MoveToCart() = %+v, want active line %d with quantity %d", item, tt.wantID, tt.wantQuantity)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_savedItems_inventory(t *testing.T) {
+	ctx := context.Background()
+	stock := inventory.NewLocal(map[int64]uint64{1: 5, 4: 0}, time.Minute)
+
+	service := NewShoppingCart(Dependencies{
+		ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+		InventoryService:    stock,
+	})
+
+	t.Run("saving releases the reservation", func(t *testing.T) {
+		if err := stock.Reserve(ctx, 1, 1, "", 1); err != nil {
+			t.Fatalf("Reserve() unexpected error = %v", err)
+		}
+
+		if _, err := service.SaveForLater(ctx, 1, 1, 1); err != nil {
+			t.Fatalf("SaveForLater() unexpected error = %v", err)
+		}
+
+		if available, _ := stock.Available(1, ""); available != 5 {
+			t.Fatalf("Available() = %d, want %d", available, 5)
+		}
+	})
+
+	t.Run("moving to cart reserves stock", func(t *testing.T) {
+		if _, err := service.MoveToCart(ctx, 1, 5, 1); err != shoppingcart.ErrOutOfStock {
+			t.Fatalf("MoveToCart() error = %v, want %v", err, shoppingcart.ErrOutOfStock)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/tracing"
+
+	"go.opencensus.io/trace"
+)
+
+// Snapshot takes a snapshot of the active items of the shopping cart
+func (service *ShoppingCart) Snapshot(ctx context.Context, shoppingCartID, userID int64) (snapshot shoppingcart.Snapshot, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.Snapshot")
+	defer func() { tracing.End(span, err) }()
+
+	if service.snapshots == nil {
+		return shoppingcart.Snapshot{}, shoppingcart.ErrNotSupported
+	}
+
+	err = service.inTx(ctx, func(ctx context.Context) error {
+		cart, err := service.getEditable(ctx, shoppingCartID, userID)
+		if err != nil {
+			return err
+		}
+
+		snapshot = shoppingcart.NewSnapshot(cart, shoppingcart.SnapshotManual, userID)
+
+		return service.snapshots.CreateSnapshot(ctx, &snapshot)
+	})
+
+	return snapshot, err
+}
+
+// ListSnapshots retrieves the snapshots of the shopping cart, newest first, without items
+func (service *ShoppingCart) ListSnapshots(ctx context.Context, shoppingCartID, userID int64) (snapshots []shoppingcart.Snapshot, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.ListSnapshots")
+	defer func() { tracing.End(span, err) }()
+
+	if service.snapshots == nil {
+		return nil, shoppingcart.ErrNotSupported
+	}
+
+	// Checking if this user has access to the shopping cart
+	if _, err := service.storage.Get(ctx, shoppingCartID, userID); err != nil {
+		return nil, err
+	}
+
+	return service.snapshots.ListSnapshots(ctx, shoppingCartID)
+}
+
+// GetSnapshot retrieves a snapshot of the shopping cart along with items
+func (service *ShoppingCart) GetSnapshot(ctx context.Context, shoppingCartID, snapshotID, userID int64) (snapshot shoppingcart.Snapshot, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.GetSnapshot")
+	defer func() { tracing.End(span, err) }()
+
+	if service.snapshots == nil {
+		return shoppingcart.Snapshot{}, shoppingcart.ErrNotSupported
+	}
+
+	// Checking if this user has access to the shopping cart
+	if _, err := service.storage.Get(ctx, shoppingCartID, userID); err != nil {
+		return shoppingcart.Snapshot{}, err
+	}
+
+	return service.snapshots.GetSnapshot(ctx, shoppingCartID, snapshotID)
+}
+
+// Restore replaces the active items of the shopping cart with the items of
+// the snapshot. The current items are kept in a new snapshot, so the restore
+// can be undone. Items are validated and added as by AddProduct, so limits
+// and stock apply; if an item is rejected, the cart is left as it was and the
+// error is returned. The restored cart is returned.
+func (service *ShoppingCart) Restore(ctx context.Context, shoppingCartID, snapshotID, userID int64) (cart shoppingcart.ShoppingCart, err error) {
+	ctx, span := trace.StartSpan(ctx, "service.ShoppingCart.Restore")
+	defer func() { tracing.End(span, err) }()
+
+	if service.snapshots == nil {
+		return shoppingcart.ShoppingCart{}, shoppingcart.ErrNotSupported
+	}
+
+	defer func() { recordOutcome(ctx, err, CartsRestored.M(1)) }()
+
+	err = service.inTx(ctx, func(ctx context.Context) error {
+		if cart, err = service.getEditable(ctx, shoppingCartID, userID); err != nil {
+			return err
+		}
+
+		snapshot, err := service.snapshots.GetSnapshot(ctx, shoppingCartID, snapshotID)
+		if err != nil {
+			return err
+		}
+
+		if len(cart.Items) > 0 {
+			service.autoSnapshot(ctx, cart, shoppingcart.SnapshotRestore, userID)
+
+			if err := service.empty(ctx, cart, userID); err != nil {
+				return err
+			}
+		}
+
+		for _, snapshotItem := range snapshot.Items {
+			cartItem := snapshotItem.CartItem(shoppingCartID)
+			if err := cartItem.Validate(); err != nil {
+				return err
+			}
+
+			// The cart is read again, as limits apply to the items restored so far
+			if cart, err = service.getEditable(ctx, shoppingCartID, userID); err != nil {
+				return err
+			}
+
+			if err := service.addProduct(ctx, cart, &cartItem, userID); err != nil {
+				return err
+			}
+		}
+
+		cart, err = service.Get(ctx, shoppingCartID, userID)
+		return err
+	})
+
+	return cart, err
+}
+
+// autoSnapshot takes a snapshot of the cart before it is emptied, checked out
+// or restored. Snapshots are optional, so failures are only logged.
+func (service *ShoppingCart) autoSnapshot(ctx context.Context, cart shoppingcart.ShoppingCart, reason string, userID int64) {
+	if service.snapshots == nil {
+		return
+	}
+
+	snapshot := shoppingcart.NewSnapshot(cart, reason, userID)
+	whenCommitted(ctx, func(ctx context.Context) {
+		if err := service.snapshots.CreateSnapshot(ctx, &snapshot); err != nil {
+			logging.FromContext(ctx).Errorf("Unable to take %s snapshot of shopping cart %d: %s", reason, cart.ID, err)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+)
+
+// snapshotRecorder keeps taken snapshots and added products in memory. Once
+// emptied, the mocked cart is returned without active items.
+type snapshotRecorder struct {
+	shoppingcart_mock.MockStorage
+	emptied   bool
+	snapshots []shoppingcart.Snapshot
+	added     []shoppingcart.ShoppingCartItem
+}
+
+func (recorder *snapshotRecorder) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
+	cart, err := recorder.MockStorage.Get(ctx, ID, userID)
+	if err == nil && recorder.emptied {
+		cart.Items = nil
+	}
+
+	return cart, err
+}
+
+func (recorder *snapshotRecorder) Empty(ctx context.Context, shoppingCartID int64) error {
+	recorder.emptied = true
+	return nil
+}
+
+func (recorder *snapshotRecorder) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	recorder.added = append(recorder.added, *cartItem)
+	return nil
+}
+
+func (recorder *snapshotRecorder) CreateSnapshot(ctx context.Context, snapshot *shoppingcart.Snapshot) error {
+	recorder.snapshots = append(recorder.snapshots, *snapshot)
+	return nil
+}
+
+func TestShoppingCart_Empty_snapshot(t *testing.T) {
+	recorder := &snapshotRecorder{}
+	service := NewShoppingCart(Dependencies{
+		ShoppingCartStorage: recorder,
+		SnapshotStorage:     recorder,
+	})
+
+	if err := service.Empty(context.Background(), 1, 1); err != nil {
+		t.Fatalf("Empty() error = %v", err)
+	}
+
+	if len(recorder.snapshots) != 1 || recorder.snapshots[0].Reason != shoppingcart.SnapshotEmpty || len(recorder.snapshots[0].Items) != 4 {
+		t.Fatalf("Empty() took snapshots %+v, want one %s snapshot with %d items", recorder.snapshots, shoppingcart.SnapshotEmpty, 4)
+	}
+}
+
+func TestShoppingCart_Restore(t *testing.T) {
+	tests := []struct {
+		name       string
+		snapshotID int64
+		userID     int64
+		limits     Limits
+		wantAdded  int
+		wantErr    error
+	}{
+		{
+			name:       "restore snapshot",
+			snapshotID: 1,
+			userID:     1,
+			wantAdded:  2,
+		},
+		{
+			name:       "restore unknown snapshot",
+			snapshotID: 2,
+			userID:     1,
+			wantErr:    shoppingcart.ErrSnapshotNotFound,
+		},
+		{
+			name:       "restore as viewer",
+			snapshotID: 1,
+			userID:     4,
+			wantErr:    shoppingcart.ErrForbidden,
+		},
+		{
+			name:       "restore beyond limits",
+			snapshotID: 1,
+			userID:     1,
+			limits:     Limits{MaxLineQuantity: 1},
+			wantErr:    shoppingcart.ErrQuantityLimitExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &snapshotRecorder{}
+			service := NewShoppingCart(Dependencies{
+				ShoppingCartStorage: recorder,
+				SnapshotStorage:     recorder,
+				Limits:              tt.limits,
+			})
+
+			_, err := service.Restore(context.Background(), 1, tt.snapshotID, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Restore() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				// The unit of work is rolled back, so the items aren't replaced
+				if len(recorder.snapshots) != 0 {
+					t.Fatalf("Restore() took snapshots %+v of a cart left as it was", recorder.snapshots)
+				}
+				return
+			}
+
+			if len(recorder.snapshots) != 1 || recorder.snapshots[0].Reason != shoppingcart.SnapshotRestore {
+				t.Fatalf("Restore() took snapshots %+v, want one %s snapshot", recorder.snapshots, shoppingcart.SnapshotRestore)
+			}
+			if !recorder.emptied || len(recorder.added) != tt.wantAdded {
+				t.Fatalf("Restore() added %+v, want %d items to the emptied cart", recorder.added, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestShoppingCart_snapshots_notSupported(t *testing.T) {
+	ctx := context.Background()
+	service := NewShoppingCart(Dependencies{ShoppingCartStorage: &shoppingcart_mock.MockStorage{}})
+
+	if _, err := service.Snapshot(ctx, 1, 1); err != shoppingcart.ErrNotSupported {
+		t.Fatalf("Snapshot() error = %v, want %v", err, shoppingcart.ErrNotSupported)
+	}
+	if _, err := service.ListSnapshots(ctx, 1, 1); err != shoppingcart.ErrNotSupported {
+		t.Fatalf("ListSnapshots() error = %v, want %v", err, shoppingcart.ErrNotSupported)
+	}
+	if _, err := service.GetSnapshot(ctx, 1, 1, 1); err != shoppingcart.ErrNotSupported {
+		t.Fatalf("GetSnapshot() error = %v, want %v", err, shoppingcart.ErrNotSupported)
+	}
+	if _, err := service.Restore(ctx, 1, 1, 1); err != shoppingcart.ErrNotSupported {
+		t.Fatalf("Restore() error = %v, want %v", err, shoppingcart.ErrNotSupported)
+	}
+}
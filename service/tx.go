@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// unitKey is the context key of the unit of work of the services
+type unitKey struct{}
+
+// unitOfWork holds the work waiting for the unit of work to commit, and the
+// undoing of side effects outside of the storage if it is rolled back
+type unitOfWork struct {
+	onCommit   []func(ctx context.Context)
+	onRollback []func(ctx context.Context)
+}
+
+// runTx runs fn as a unit of work of s, which is carried by the context
+// passed to fn. Nested calls join the outer unit of work, and the storage
+// joins it or starts its own within it. Side effects of attempts which are
+// rolled back are undone in reverse order.
+func runTx(ctx context.Context, s storage.Transactional, fn func(ctx context.Context) error) error {
+	if ctx.Value(unitKey{}) != nil {
+		return s.WithTx(ctx, fn)
+	}
+
+	var unit *unitOfWork
+	err := s.WithTx(ctx, func(txCtx context.Context) error {
+		// Units of work may be retried, only the last attempt counts
+		if unit != nil {
+			unit.rollback(ctx)
+		}
+		unit = &unitOfWork{}
+		return fn(context.WithValue(txCtx, unitKey{}, unit))
+	})
+	if err != nil {
+		if unit != nil {
+			unit.rollback(ctx)
+		}
+		return err
+	}
+
+	for _, write := range unit.onCommit {
+		write(ctx)
+	}
+
+	return nil
+}
+
+// whenCommitted runs write once the unit of work carried by ctx commits, or
+// right away outside of a unit of work. write gets a context outside of the
+// unit of work.
+func whenCommitted(ctx context.Context, write func(ctx context.Context)) {
+	unit, ok := ctx.Value(unitKey{}).(*unitOfWork)
+	if !ok {
+		write(ctx)
+		return
+	}
+
+	unit.onCommit = append(unit.onCommit, write)
+}
+
+// whenRolledBack runs undo if the unit of work carried by ctx is rolled back.
+// Outside of a unit of work there is nothing to roll back. undo gets a context
+// outside of the unit of work.
+func whenRolledBack(ctx context.Context, undo func(ctx context.Context)) {
+	unit, ok := ctx.Value(unitKey{}).(*unitOfWork)
+	if !ok {
+		return
+	}
+
+	unit.onRollback = append(unit.onRollback, undo)
+}
+
+// rollback undoes the side effects of the unit of work, the latest first
+func (unit *unitOfWork) rollback(ctx context.Context) {
+	for i := len(unit.onRollback) - 1; i >= 0; i-- {
+		unit.onRollback[i](ctx)
+	}
+	unit.onRollback = nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// Wishlist service responsible for wishlist operations
+type Wishlist struct {
+	storage      WishlistStorage
+	shoppingCart *ShoppingCart
+}
+
+// NewWishlist returns a new Wishlist service. Items are moved to carts
+// through shoppingCart, so limits and stock are checked as usual.
+func NewWishlist(deps Dependencies, shoppingCart *ShoppingCart) *Wishlist {
+	return &Wishlist{
+		storage:      deps.WishlistStorage,
+		shoppingCart: shoppingCart,
+	}
+}
+
+// Create creates a new wishlist in storage
+func (service *Wishlist) Create(ctx context.Context, wishlist *shoppingcart.Wishlist) error {
+	if err := wishlist.Validate(); err != nil {
+		return err
+	}
+
+	return service.storage.CreateWishlist(ctx, wishlist)
+}
+
+// Get retrieves a wishlist of the user along with items
+func (service *Wishlist) Get(ctx context.Context, wishlistID, userID int64) (shoppingcart.Wishlist, error) {
+	return service.storage.GetWishlist(ctx, wishlistID, userID)
+}
+
+// inTx runs fn as a unit of work of the wishlist storage, so the wishlists
+// can't change between the checks and the writes of fn
+func (service *Wishlist) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runTx(ctx, service.storage, fn)
+}
+
+// List retrieves all wishlists of the user, without items
+func (service *Wishlist) List(ctx context.Context, userID int64) ([]shoppingcart.Wishlist, error) {
+	return service.storage.ListWishlists(ctx, userID)
+}
+
+// Rename changes the name of the wishlist. The renamed wishlist is returned.
+func (service *Wishlist) Rename(ctx context.Context, wishlistID, userID int64, name string) (wishlist shoppingcart.Wishlist, err error) {
+	err = service.inTx(ctx, func(ctx context.Context) error {
+		if wishlist, err = service.Get(ctx, wishlistID, userID); err != nil {
+			return err
+		}
+
+		wishlist.Name = name
+		if err := wishlist.Validate(); err != nil {
+			return err
+		}
+
+		return service.storage.UpdateWishlist(ctx, &wishlist)
+	})
+
+	return wishlist, err
+}
+
+// Delete removes the wishlist along with items
+func (service *Wishlist) Delete(ctx context.Context, wishlistID, userID int64) error {
+	return service.inTx(ctx, func(ctx context.Context) error {
+		// Checking if wishlist belong to this user
+		if _, err := service.Get(ctx, wishlistID, userID); err != nil {
+			return err
+		}
+
+		return service.storage.DeleteWishlist(ctx, wishlistID)
+	})
+}
+
+// AddProduct adds new product to existing wishlist
+// If the line of the product, variant and attributes exists, quantity will be updated
+func (service *Wishlist) AddProduct(ctx context.Context, wishlistItem *shoppingcart.WishlistItem, userID int64) error {
+	if err := wishlistItem.Validate(); err != nil {
+		return err
+	}
+
+	return service.inTx(ctx, func(ctx context.Context) error {
+		wishlist, err := service.Get(ctx, wishlistItem.WishlistID, userID)
+		if err != nil {
+			return err
+		}
+
+		existingItem, err := wishlist.GetProduct(*wishlistItem)
+		if err != nil {
+			return service.storage.AddWishlistItem(ctx, wishlistItem)
+		}
+
+		existingItem.Quantity += wishlistItem.Quantity
+		*wishlistItem = existingItem
+
+		return service.storage.UpdateWishlistItem(ctx, wishlistItem)
+	})
+}
+
+// RemoveItem removes a single line from existing wishlist
+func (service *Wishlist) RemoveItem(ctx context.Context, wishlistID, itemID, userID int64) error {
+	return service.inTx(ctx, func(ctx context.Context) error {
+		wishlist, err := service.Get(ctx, wishlistID, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := wishlist.GetItem(itemID); err != nil {
+			return err
+		}
+
+		return service.storage.RemoveWishlistItem(ctx, wishlistID, itemID)
+	})
+}
+
+// MoveToCart adds a line of the wishlist to an existing shopping cart of the
+// user and removes it from the wishlist, as a single unit of work. The line
+// of the cart is returned.
+func (service *Wishlist) MoveToCart(ctx context.Context, wishlistID, itemID, shoppingCartID, userID int64) (cartItem shoppingcart.ShoppingCartItem, err error) {
+	// The unit of work of the cart storage is the outer one, as it may retry
+	err = service.shoppingCart.inTx(ctx, func(ctx context.Context) error {
+		return service.inTx(ctx, func(ctx context.Context) error {
+			wishlist, err := service.Get(ctx, wishlistID, userID)
+			if err != nil {
+				return err
+			}
+
+			wishlistItem, err := wishlist.GetItem(itemID)
+			if err != nil {
+				return err
+			}
+
+			cartItem = wishlistItem.CartItem(shoppingCartID)
+			if err := service.shoppingCart.AddProduct(ctx, &cartItem, userID); err != nil {
+				return err
+			}
+
+			return service.storage.RemoveWishlistItem(ctx, wishlistID, itemID)
+		})
+	})
+
+	return cartItem, err
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+)
+
+// The mocked wishlist 1 of user 1 holds product 7 (line 1), product 2 (line 2)
+// and product 3 in variant M (line 3), each with quantity 1
+
+func TestWishlist_AddProduct(t *testing.T) {
+	tests := []struct {
+		name         string
+		item         shoppingcart.WishlistItem
+		wantID       int64
+		wantQuantity uint64
+		wantErr      error
+	}{
+		{
+			name:         "add new product",
+			item:         shoppingcart.WishlistItem{WishlistID: 1, ProductID: 5, Quantity: 2},
+			wantQuantity: 2,
+		},
+		{
+			name:         "add existing product",
+			item:         shoppingcart.WishlistItem{WishlistID: 1, ProductID: 2, Quantity: 2},
+			wantID:       2,
+			wantQuantity: 3,
+		},
+		{
+			name:         "add another variant",
+			item:         shoppingcart.WishlistItem{WishlistID: 1, ProductID: 3, VariantID: "L", Quantity: 1},
+			wantQuantity: 1,
+		},
+		{
+			name:    "add product to unknown wishlist",
+			item:    shoppingcart.WishlistItem{WishlistID: 2, ProductID: 2, Quantity: 1},
+			wantErr: shoppingcart.ErrWishlistNotFound,
+		},
+		{
+			name:    "add product without quantity",
+			item:    shoppingcart.WishlistItem{WishlistID: 1, ProductID: 2},
+			wantErr: shoppingcart.ErrCartItemNoQuantitySet,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := New(Dependencies{
+				ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+				WishlistStorage:     &shoppingcart_mock.MockStorage{},
+			})
+
+			item := tt.item
+			err := services.Wishlist.AddProduct(context.Background(), &item, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddProduct() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if item.ID != tt.wantID || item.Quantity != tt.wantQuantity {
+				t.Fatalf("AddProduct() = %+v, want line %d with quantity %d", item, tt.wantID, tt.wantQuantity)
+			}
+		})
+	}
+}
+
+func TestWishlist_MoveToCart(t *testing.T) {
+	tests := []struct {
+		name           string
+		itemID         int64
+		shoppingCartID int64
+		wantID         int64
+		wantQuantity   uint64
+		wantErr        error
+	}{
+		{
+			name:           "move product which is in the cart",
+			itemID:         2,
+			shoppingCartID: 1,
+			wantID:         2,
+			wantQuantity:   11,
+		},
+		{
+			name:           "move new product",
+			itemID:         1,
+			shoppingCartID: 1,
+			wantQuantity:   1,
+		},
+		{
+			name:           "move to cart of another user",
+			itemID:         1,
+			shoppingCartID: 2,
+			wantErr:        shoppingcart.ErrCartNotFound,
+		},
+		{
+			name:           "move unknown item",
+			itemID:         9,
+			shoppingCartID: 1,
+			wantErr:        shoppingcart.ErrWishlistItemNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := New(Dependencies{
+				ShoppingCartStorage: &shoppingcart_mock.MockStorage{},
+				WishlistStorage:     &shoppingcart_mock.MockStorage{},
+			})
+
+			item, err := services.Wishlist.MoveToCart(context.Background(), 1, tt.itemID, tt.shoppingCartID, 1)
+			if err != tt.wantErr {
+				t.Fatalf("MoveToCart() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if item.ID != tt.wantID || item.Quantity != tt.wantQuantity || item.ShoppingCartID != tt.shoppingCartID {
+				t.Fatalf("MoveToCart() = %+v, want line %d of cart %d with quantity %d", item, tt.wantID, tt.shoppingCartID, tt.wantQuantity)
+			}
+		})
+	}
+}
+
+func TestWishlist_Rename(t *testing.T) {
+	service := NewWishlist(Dependencies{WishlistStorage: &shoppingcart_mock.MockStorage{}}, nil)
+
+	wishlist, err := service.Rename(context.Background(), 1, 1, "Wedding")
+	if err != nil || wishlist.Name != "Wedding" {
+		t.Fatalf("Rename() = %v, %v, want name %q", wishlist, err, "Wedding")
+	}
+
+	if _, err := service.Rename(context.Background(), 1, 1, ""); !errors.Is(err, shoppingcart.ErrWishlistNameNotSet) {
+		t.Fatalf("Rename() error = %v, want %v", err, shoppingcart.ErrWishlistNameNotSet)
+	}
+}
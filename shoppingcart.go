@@ -1,7 +1,11 @@
 package shoppingcart
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -9,7 +13,9 @@ import (
 // operating on ShoppingCart
 var (
 	ErrNoPermission = errors.New("user does not have the permissions")
+	ErrForbidden    = errors.New("user is not allowed to modify the shopping cart")
 	ErrUserNotSet   = errors.New("no user set")
+	ErrValidation   = errors.New("validation failed")
 
 	ErrCartNotFound   = errors.New("shopping cart not found")
 	ErrCartHasNoItems = errors.New("shopping cart has no items")
@@ -18,16 +24,108 @@ var (
 	ErrCartItemAlreadyExists = errors.New("this product already added to shopping cart")
 	ErrCartItemNoProductSet  = errors.New("product is not specified")
 	ErrCartItemNoQuantitySet = errors.New("quantity is not specified")
+
+	ErrQuantityLimitExceeded = errors.New("quantity limit exceeded")
+	ErrOutOfStock            = errors.New("product is out of stock")
+
+	ErrStorageTimeout    = errors.New("storage did not respond in time")
+	ErrRateLimitExceeded = errors.New("rate limit exceeded")
+	ErrNotSupported      = errors.New("operation is not supported by the configured storage")
+)
+
+// Limits which can be exceeded, as reported by QuantityLimitError
+const (
+	LimitLineQuantity = "line_quantity"
+	LimitCartLines    = "cart_lines"
+	LimitCartQuantity = "cart_quantity"
+)
+
+// QuantityLimitError is returned when an operation would exceed one of the
+// quantity limits. It matches ErrQuantityLimitExceeded.
+type QuantityLimitError struct {
+	// Limit is one of LimitLineQuantity, LimitCartLines or LimitCartQuantity
+	Limit string
+	// ProductID is set when the limit applies to a single product
+	ProductID int64
+	// Max is the allowed maximum
+	Max uint64
+}
+
+func (err *QuantityLimitError) Error() string {
+	if err.ProductID != 0 {
+		return fmt.Sprintf("%s: %s of product %d can't exceed %d", ErrQuantityLimitExceeded, err.Limit, err.ProductID, err.Max)
+	}
+
+	return fmt.Sprintf("%s: %s can't exceed %d", ErrQuantityLimitExceeded, err.Limit, err.Max)
+}
+
+// Is reports whether target is ErrQuantityLimitExceeded
+func (err *QuantityLimitError) Is(target error) bool {
+	return target == ErrQuantityLimitExceeded
+}
+
+// FieldError describes a validation failure of a single field
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (err FieldError) Error() string {
+	return err.Field + ": " + err.Err.Error()
+}
+
+// Unwrap returns the reason of the failure
+func (err FieldError) Unwrap() error {
+	return err.Err
+}
+
+// ValidationErrors is returned when one or more fields fail validation.
+// It matches ErrValidation as well as the error of every failed field.
+type ValidationErrors []FieldError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrValidation or the error of one of the fields
+func (errs ValidationErrors) Is(target error) bool {
+	if target == ErrValidation {
+		return true
+	}
+
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lists a shopping cart item can belong to, see ShoppingCartItem.List
+const (
+	ListCart  = "cart"
+	ListSaved = "saved"
 )
 
 // ShoppingCart describes shopping cart
+// Items saved for later are kept apart from the active items, they are not
+// part of limits, reservations and checkout.
+// UserID is the owner of the cart, other users get access as Members.
 // swagger:response ShoppingCart
 type ShoppingCart struct {
-	ID        int64              `json:"id" gorm:"primary_key"`
-	UserID    int64              `json:"user_id" gorm:"primary_key"`
-	CreatedAt time.Time          `json:"created_at"`
-	UpdatedAt time.Time          `json:"updated_at"`
-	Items     []ShoppingCartItem `json:"items,omitempty" gorm:"foreignkey:ShoppingCartID;association_foreignkey:ID"`
+	ID         int64              `json:"id" gorm:"primary_key"`
+	UserID     int64              `json:"user_id" gorm:"primary_key"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	Items      []ShoppingCartItem `json:"items,omitempty" gorm:"foreignkey:ShoppingCartID;association_foreignkey:ID"`
+	SavedItems []ShoppingCartItem `json:"saved_items,omitempty" gorm:"-"`
+	Members    []CartMember       `json:"members,omitempty" gorm:"foreignkey:ShoppingCartID;association_foreignkey:ID"`
 }
 
 // TableName specifies storage table name
@@ -43,35 +141,134 @@ func (cart *ShoppingCart) Validate() error {
 	return nil
 }
 
-// HasProduct checks if shopping cart contains specific product
-func (cart *ShoppingCart) HasProduct(productID int64) bool {
+// RoleOf returns the role of the user in the shopping cart: RoleOwner for the
+// owner, the role of accepted members and an empty string for everyone else
+func (cart *ShoppingCart) RoleOf(userID int64) string {
+	if cart.UserID == userID {
+		return RoleOwner
+	}
+
+	member, err := cart.GetMember(userID)
+	if err != nil || !member.Accepted() {
+		return ""
+	}
+
+	return member.Role
+}
+
+// CanEdit reports whether the user is allowed to modify the shopping cart
+func (cart *ShoppingCart) CanEdit(userID int64) bool {
+	role := cart.RoleOf(userID)
+	return role == RoleOwner || role == RoleEditor
+}
+
+// GetMember retrieves the membership of the user, accepted or not
+func (cart *ShoppingCart) GetMember(userID int64) (CartMember, error) {
+	for _, member := range cart.Members {
+		if member.UserID == userID {
+			return member, nil
+		}
+	}
+	return CartMember{}, ErrMemberNotFound
+}
+
+// HasProduct checks if shopping cart contains the line of cartItem,
+// see ShoppingCartItem.SameLine
+func (cart *ShoppingCart) HasProduct(cartItem ShoppingCartItem) bool {
 	for _, item := range cart.Items {
-		if item.ProductID == productID {
+		if item.SameLine(cartItem) {
 			return true
 		}
 	}
 	return false
 }
 
-// GetProduct retrieves product from shopping cart
-func (cart *ShoppingCart) GetProduct(productID int64) (ShoppingCartItem, error) {
+// GetProduct retrieves the line of cartItem from shopping cart,
+// see ShoppingCartItem.SameLine
+func (cart *ShoppingCart) GetProduct(cartItem ShoppingCartItem) (ShoppingCartItem, error) {
 	for _, item := range cart.Items {
-		if item.ProductID == productID {
+		if item.SameLine(cartItem) {
 			return item, nil
 		}
 	}
 	return ShoppingCartItem{}, ErrCartItemNotFound
 }
 
+// GetItem retrieves active shopping cart item by its ID
+func (cart *ShoppingCart) GetItem(itemID int64) (ShoppingCartItem, error) {
+	for _, item := range cart.Items {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return ShoppingCartItem{}, ErrCartItemNotFound
+}
+
+// GetSavedItem retrieves shopping cart item saved for later by its ID
+func (cart *ShoppingCart) GetSavedItem(itemID int64) (ShoppingCartItem, error) {
+	for _, item := range cart.SavedItems {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return ShoppingCartItem{}, ErrCartItemNotFound
+}
+
+// GetSavedProduct retrieves the line of cartItem from the items saved for later,
+// see ShoppingCartItem.SameLine
+func (cart *ShoppingCart) GetSavedProduct(cartItem ShoppingCartItem) (ShoppingCartItem, error) {
+	for _, item := range cart.SavedItems {
+		if item.SameLine(cartItem) {
+			return item, nil
+		}
+	}
+	return ShoppingCartItem{}, ErrCartItemNotFound
+}
+
+// ProductItems returns all lines of the product, regardless of variant and attributes
+func (cart *ShoppingCart) ProductItems(productID int64) []ShoppingCartItem {
+	var items []ShoppingCartItem
+	for _, item := range cart.Items {
+		if item.ProductID == productID {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // ShoppingCartItem represents shopping cart entity
+// A line of the cart is identified by product, variant and attributes, so the
+// same product may be added several times with different variants or attributes.
 // swagger:response ShoppingCartItem
 type ShoppingCartItem struct {
-	ID             int64     `json:"id"`
-	ShoppingCartID int64     `json:"shoppingcart_id" gorm:"column:shoppingcart_id"`
-	ProductID      int64     `json:"product_id"`
-	Quantity       uint64    `json:"quantity"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             int64      `json:"id"`
+	ShoppingCartID int64      `json:"shoppingcart_id" gorm:"column:shoppingcart_id"`
+	ProductID      int64      `json:"product_id"`
+	VariantID      string     `json:"variant_id,omitempty"`
+	Attributes     Attributes `json:"attributes,omitempty"`
+	Quantity       uint64     `json:"quantity"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// AddedBy is the user who added the line, which matters for shared carts
+	AddedBy int64 `json:"added_by,omitempty"`
+
+	// List is ListCart or ListSaved. It's not part of the JSON representation,
+	// as saved items are listed separately by ShoppingCart.
+	List string `json:"-"`
+}
+
+// Saved reports whether the item is saved for later
+func (cartItem ShoppingCartItem) Saved() bool {
+	return cartItem.List == ListSaved
+}
+
+// SameLine reports whether both items describe the same line of the cart,
+// i.e. they have the same product, variant and attributes
+func (cartItem ShoppingCartItem) SameLine(other ShoppingCartItem) bool {
+	return cartItem.ProductID == other.ProductID &&
+		cartItem.VariantID == other.VariantID &&
+		cartItem.Attributes.Equal(other.Attributes)
 }
 
 // TableName specifies storage table name
@@ -79,13 +276,64 @@ func (ShoppingCartItem) TableName() string {
 	return "shoppingcart_item"
 }
 
-// Validate validates ShoppingCartItem
+// Attributes are custom properties of a cart line, like engraving text or a
+// gift message. They are stored as a JSON object.
+type Attributes map[string]string
+
+// Equal reports whether both attribute sets hold the same values.
+// Nil and empty attributes are equal.
+func (attributes Attributes) Equal(other Attributes) bool {
+	if len(attributes) != len(other) {
+		return false
+	}
+
+	for key, value := range attributes {
+		if otherValue, ok := other[key]; !ok || otherValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Value implements driver.Valuer
+func (attributes Attributes) Value() (driver.Value, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(attributes)
+}
+
+// Scan implements sql.Scanner
+func (attributes *Attributes) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*attributes = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, attributes)
+	case string:
+		return json.Unmarshal([]byte(src), attributes)
+	}
+
+	return fmt.Errorf("unsupported attributes type %T", src)
+}
+
+// Validate validates ShoppingCartItem. All failed fields are reported at once
+// as ValidationErrors.
 func (cartItem *ShoppingCartItem) Validate() error {
-	switch {
-	case cartItem.ProductID == 0:
-		return ErrCartItemNoProductSet
-	case cartItem.Quantity == 0:
-		return ErrCartItemNoQuantitySet
+	var errs ValidationErrors
+
+	if cartItem.ProductID == 0 {
+		errs = append(errs, FieldError{Field: "product_id", Err: ErrCartItemNoProductSet})
+	}
+	if cartItem.Quantity == 0 {
+		errs = append(errs, FieldError{Field: "quantity", Err: ErrCartItemNoQuantitySet})
+	}
+
+	if len(errs) > 0 {
+		return errs
 	}
 
 	return nil
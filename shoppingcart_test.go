@@ -1,6 +1,7 @@
 package shoppingcart
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -60,12 +61,29 @@ func TestShoppingCartItem_Validate(t *testing.T) {
 	}
 }
 
+func TestShoppingCartItem_Validate_allFields(t *testing.T) {
+	cartItem := &ShoppingCartItem{ShoppingCartID: 1}
+
+	err := cartItem.Validate()
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("Validate() error = %v, want both fields reported", err)
+	}
+
+	for _, target := range []error{ErrValidation, ErrCartItemNoProductSet, ErrCartItemNoQuantitySet} {
+		if !errors.Is(err, target) {
+			t.Errorf("Validate() error = %v, want it to match %v", err, target)
+		}
+	}
+}
+
 func TestShoppingCart_HasProduct(t *testing.T) {
 	type fields struct {
 		Items []ShoppingCartItem
 	}
 	type args struct {
-		productID int64
+		cartItem ShoppingCartItem
 	}
 
 	tests := []struct {
@@ -81,7 +99,7 @@ func TestShoppingCart_HasProduct(t *testing.T) {
 				{ProductID: 2},
 				{ProductID: 3},
 			}},
-			args: args{productID: 3},
+			args: args{cartItem: ShoppingCartItem{ProductID: 3}},
 			want: true,
 		},
 		{
@@ -91,9 +109,42 @@ func TestShoppingCart_HasProduct(t *testing.T) {
 				{ProductID: 2},
 				{ProductID: 3},
 			}},
-			args: args{productID: 5},
+			args: args{cartItem: ShoppingCartItem{ProductID: 5}},
+			want: false,
+		},
+		{
+			name: "variant found",
+			fields: fields{[]ShoppingCartItem{
+				{ProductID: 1, VariantID: "M"},
+				{ProductID: 1, VariantID: "L"},
+			}},
+			args: args{cartItem: ShoppingCartItem{ProductID: 1, VariantID: "L"}},
+			want: true,
+		},
+		{
+			name: "variant not found",
+			fields: fields{[]ShoppingCartItem{
+				{ProductID: 1, VariantID: "M"},
+			}},
+			args: args{cartItem: ShoppingCartItem{ProductID: 1, VariantID: "L"}},
+			want: false,
+		},
+		{
+			name: "attributes differ",
+			fields: fields{[]ShoppingCartItem{
+				{ProductID: 1, Attributes: Attributes{"engraving": "Anna"}},
+			}},
+			args: args{cartItem: ShoppingCartItem{ProductID: 1, Attributes: Attributes{"engraving": "Bob"}}},
 			want: false,
 		},
+		{
+			name: "empty attributes match no attributes",
+			fields: fields{[]ShoppingCartItem{
+				{ProductID: 1, Attributes: Attributes{}},
+			}},
+			args: args{cartItem: ShoppingCartItem{ProductID: 1}},
+			want: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -101,7 +152,7 @@ func TestShoppingCart_HasProduct(t *testing.T) {
 
 				Items: tt.fields.Items,
 			}
-			if got := cart.HasProduct(tt.args.productID); got != tt.want {
+			if got := cart.HasProduct(tt.args.cartItem); got != tt.want {
 				t.Errorf("HasProduct() = %v, want %v", got, tt.want)
 			}
 		})
@@ -113,7 +164,7 @@ func TestShoppingCart_GetProduct(t *testing.T) {
 		Items []ShoppingCartItem
 	}
 	type args struct {
-		productID int64
+		cartItem ShoppingCartItem
 	}
 	tests := []struct {
 		name    string
@@ -129,7 +180,7 @@ func TestShoppingCart_GetProduct(t *testing.T) {
 				{ProductID: 2},
 				{ProductID: 3},
 			}},
-			args:    args{productID: 3},
+			args:    args{cartItem: ShoppingCartItem{ProductID: 3}},
 			want:    ShoppingCartItem{ProductID: 3},
 			wantErr: false,
 		},
@@ -140,16 +191,26 @@ func TestShoppingCart_GetProduct(t *testing.T) {
 				{ProductID: 2},
 				{ProductID: 3},
 			}},
-			args:    args{productID: 5},
+			args:    args{cartItem: ShoppingCartItem{ProductID: 5}},
 			wantErr: true,
 		},
+		{
+			name: "variant found",
+			fields: fields{[]ShoppingCartItem{
+				{ID: 1, ProductID: 1, VariantID: "M", Quantity: 1},
+				{ID: 2, ProductID: 1, VariantID: "L", Quantity: 2},
+			}},
+			args:    args{cartItem: ShoppingCartItem{ProductID: 1, VariantID: "L"}},
+			want:    ShoppingCartItem{ID: 2, ProductID: 1, VariantID: "L", Quantity: 2},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cart := &ShoppingCart{
 				Items: tt.fields.Items,
 			}
-			got, err := cart.GetProduct(tt.args.productID)
+			got, err := cart.GetProduct(tt.args.cartItem)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetProduct() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -160,3 +221,25 @@ func TestShoppingCart_GetProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestAttributes_Scan(t *testing.T) {
+	attributes := Attributes{"gift_message": "Happy birthday"}
+
+	value, err := attributes.Value()
+	if err != nil {
+		t.Fatalf("Value() unexpected error = %v", err)
+	}
+
+	var got Attributes
+	if err := got.Scan(value); err != nil {
+		t.Fatalf("Scan() unexpected error = %v", err)
+	}
+
+	if !got.Equal(attributes) {
+		t.Fatalf("Scan() got = %v, want %v", got, attributes)
+	}
+
+	if err := got.Scan(nil); err != nil || got != nil {
+		t.Fatalf("Scan(nil) got = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,83 @@
+package shoppingcart
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSnapshotNotFound is returned when the snapshot doesn't exist or belongs
+// to another shopping cart
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Reasons for taking a snapshot of a shopping cart
+const (
+	SnapshotManual   = "manual"
+	SnapshotCheckout = "checkout"
+	SnapshotEmpty    = "empty"
+	SnapshotRestore  = "restore"
+)
+
+// Snapshot is an immutable copy of the active items of a shopping cart at
+// a point in time. Snapshots are taken on demand, at checkout and before the
+// cart is emptied or restored, so these changes can be undone.
+// swagger:response Snapshot
+type Snapshot struct {
+	ID             int64          `json:"id"`
+	ShoppingCartID int64          `json:"shoppingcart_id" gorm:"column:shoppingcart_id"`
+	Reason         string         `json:"reason"`
+	CreatedBy      int64          `json:"created_by"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Items          []SnapshotItem `json:"items,omitempty" gorm:"foreignkey:SnapshotID;association_foreignkey:ID"`
+}
+
+// TableName specifies storage table name
+func (Snapshot) TableName() string {
+	return "shoppingcart_snapshot"
+}
+
+// SnapshotItem is a line of the shopping cart kept in a snapshot
+type SnapshotItem struct {
+	ID         int64      `json:"id"`
+	SnapshotID int64      `json:"snapshot_id"`
+	ProductID  int64      `json:"product_id"`
+	VariantID  string     `json:"variant_id,omitempty"`
+	Attributes Attributes `json:"attributes,omitempty"`
+	Quantity   uint64     `json:"quantity"`
+}
+
+// TableName specifies storage table name
+func (SnapshotItem) TableName() string {
+	return "shoppingcart_snapshot_item"
+}
+
+// NewSnapshot returns a snapshot of the active items of the cart
+func NewSnapshot(cart ShoppingCart, reason string, userID int64) Snapshot {
+	snapshot := Snapshot{
+		ShoppingCartID: cart.ID,
+		Reason:         reason,
+		CreatedBy:      userID,
+		Items:          make([]SnapshotItem, 0, len(cart.Items)),
+	}
+
+	for _, item := range cart.Items {
+		snapshot.Items = append(snapshot.Items, SnapshotItem{
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			Attributes: item.Attributes,
+			Quantity:   item.Quantity,
+		})
+	}
+
+	return snapshot
+}
+
+// CartItem converts the snapshot item to an item of the shopping cart
+func (snapshotItem SnapshotItem) CartItem(shoppingCartID int64) ShoppingCartItem {
+	return ShoppingCartItem{
+		ShoppingCartID: shoppingCartID,
+		ProductID:      snapshotItem.ProductID,
+		VariantID:      snapshotItem.VariantID,
+		Attributes:     snapshotItem.Attributes,
+		Quantity:       snapshotItem.Quantity,
+	}
+}
@@ -0,0 +1,32 @@
+package shoppingcart
+
+import "testing"
+
+func TestNewSnapshot(t *testing.T) {
+	cart := ShoppingCart{
+		ID:     2,
+		UserID: 1,
+		Items: []ShoppingCartItem{
+			{ID: 1, ShoppingCartID: 2, ProductID: 1, Quantity: 3},
+			{ID: 2, ShoppingCartID: 2, ProductID: 5, VariantID: "M", Attributes: Attributes{"engraving": "For Anna"}, Quantity: 1},
+		},
+		SavedItems: []ShoppingCartItem{
+			{ID: 3, ShoppingCartID: 2, ProductID: 7, Quantity: 1, List: ListSaved},
+		},
+	}
+
+	snapshot := NewSnapshot(cart, SnapshotManual, 3)
+
+	if snapshot.ShoppingCartID != 2 || snapshot.Reason != SnapshotManual || snapshot.CreatedBy != 3 {
+		t.Fatalf("NewSnapshot() = %+v, want manual snapshot of cart %d by %d", snapshot, 2, 3)
+	}
+	if len(snapshot.Items) != len(cart.Items) {
+		t.Fatalf("NewSnapshot() has %d items, want %d", len(snapshot.Items), len(cart.Items))
+	}
+
+	for i, item := range snapshot.Items {
+		if cartItem := item.CartItem(cart.ID); !cartItem.SameLine(cart.Items[i]) || cartItem.Quantity != cart.Items[i].Quantity {
+			t.Fatalf("Snapshot item %d = %+v, want %+v", i, cartItem, cart.Items[i])
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Package cache provides a read-through cache of shopping carts in front of
+// storage.ShoppingCart. Every write invalidates the cached cart.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// DefaultTTL is how long carts are cached if Config.TTL is not set
+const DefaultTTL = 30 * time.Second
+
+// DefaultLoadTimeout is how long a cart may take to load if
+// Config.LoadTimeout is not set
+const DefaultLoadTimeout = 5 * time.Second
+
+// Cache describes an interface to a key-value cache. Besides the in-process
+// LRU, it can be implemented by an external cache shared between instances.
+type Cache interface {
+	// Get returns the value of the key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores the value of the key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Config defines the settings of Store
+type Config struct {
+	// Members is optional, membership changes through Store invalidate the cart
+	Members storage.CartMembers
+
+	// TTL is how long carts are cached, DefaultTTL if zero
+	TTL time.Duration
+
+	// LoadTimeout is how long a cart may take to load on a miss,
+	// DefaultLoadTimeout if zero. Concurrent misses share the load, so it
+	// doesn't end when the request which started it does.
+	LoadTimeout time.Duration
+}
+
+// Store caches the shopping carts returned by Get. Carts are cached per user,
+// as access depends on the user. Each cart has a generation which is part of
+// the keys of its cached copies; writes replace the generation, so all copies
+// of the cart are invalidated at once.
+type Store struct {
+	next        storage.ShoppingCart
+	members     storage.CartMembers
+	cache       Cache
+	ttl         time.Duration
+	loadTimeout time.Duration
+	loads       group
+}
+
+// unitKey is the context key of the unit of work of WithTx on the store
+type unitKey struct {
+	store *Store
+}
+
+var (
+	_ storage.ShoppingCart = (*Store)(nil)
+	_ storage.CartMembers  = (*Store)(nil)
+)
+
+// New returns a new Store caching the carts of next in cache
+func New(next storage.ShoppingCart, cache Cache, config Config) *Store {
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.LoadTimeout <= 0 {
+		config.LoadTimeout = DefaultLoadTimeout
+	}
+
+	return &Store{
+		next:        next,
+		members:     config.Members,
+		cache:       cache,
+		ttl:         config.TTL,
+		loadTimeout: config.LoadTimeout,
+	}
+}
+
+// Create creates shopping cart in the storage
+func (store *Store) Create(ctx context.Context, cart *shoppingcart.ShoppingCart) error {
+	return store.next.Create(ctx, cart)
+}
+
+// Get retrieves shopping cart from the cache, or from the storage if it is
+// not cached. Concurrent misses of the same cart are coalesced into a load
+// which ends after the load timeout, while every caller only waits for it
+// until its own ctx is done. Within WithTx carts are read from the storage,
+// so they are locked as the storage does it.
+func (store *Store) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
+	if store.unit(ctx) != nil {
+		return store.next.Get(ctx, ID, userID)
+	}
+
+	key := store.key(ctx, ID, userID)
+
+	if data, ok, err := store.cache.Get(ctx, key); err != nil {
+		logging.FromContext(ctx).Errorf("Unable to get shopping cart %d from cache: %s", ID, err)
+	} else if ok {
+		if cart, err := decode(data); err == nil {
+			recordLookup(ctx, resultHit)
+			return cart, nil
+		}
+	}
+
+	data, shared, err := store.loads.do(ctx, key, store.loadTimeout, func(ctx context.Context) ([]byte, error) {
+		cart, err := store.next.Get(ctx, ID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := encode(cart)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := store.cache.Set(ctx, key, data, store.ttl); err != nil {
+			logging.FromContext(ctx).Errorf("Unable to cache shopping cart %d: %s", ID, err)
+		}
+
+		return data, nil
+	})
+
+	if shared {
+		recordLookup(ctx, resultCoalesced)
+	} else {
+		recordLookup(ctx, resultMiss)
+	}
+
+	if err != nil {
+		return shoppingcart.ShoppingCart{}, err
+	}
+
+	// Every caller gets its own copy of the cart
+	return decode(data)
+}
+
+// Empty removes active items associated with shopping cart
+func (store *Store) Empty(ctx context.Context, shoppingCartID int64) error {
+	defer store.written(ctx, shoppingCartID)
+	return store.next.Empty(ctx, shoppingCartID)
+}
+
+// AddProduct adds product to the shopping cart
+func (store *Store) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	defer store.written(ctx, cartItem.ShoppingCartID)
+	return store.next.AddProduct(ctx, cartItem)
+}
+
+// UpdateProduct updates product in the shopping cart
+func (store *Store) UpdateProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	defer store.written(ctx, cartItem.ShoppingCartID)
+	return store.next.UpdateProduct(ctx, cartItem)
+}
+
+// RemoveProduct removes all active lines of the product from the shopping cart
+func (store *Store) RemoveProduct(ctx context.Context, shoppingCartID, productID int64) error {
+	defer store.written(ctx, shoppingCartID)
+	return store.next.RemoveProduct(ctx, shoppingCartID, productID)
+}
+
+// RemoveItem removes a single line from the shopping cart
+func (store *Store) RemoveItem(ctx context.Context, shoppingCartID, itemID int64) error {
+	defer store.written(ctx, shoppingCartID)
+	return store.next.RemoveItem(ctx, shoppingCartID, itemID)
+}
+
+// WithTx runs fn as a unit of work of the storage, which is carried by the
+// context passed to fn along with the carts written within it. Cached copies
+// of the written carts are invalidated once the unit of work ends.
+func (store *Store) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if store.unit(ctx) != nil {
+		return fn(ctx)
+	}
+
+	written := map[int64]bool{}
+	defer func() {
+		for ID := range written {
+			store.invalidate(ctx, ID)
+		}
+	}()
+
+	return store.next.WithTx(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, unitKey{store}, written))
+	})
+}
+
+// AddMember adds a member to the shopping cart
+func (store *Store) AddMember(ctx context.Context, member *shoppingcart.CartMember) error {
+	defer store.written(ctx, member.ShoppingCartID)
+	return store.members.AddMember(ctx, member)
+}
+
+// GetMember retrieves the membership of the user in the shopping cart
+func (store *Store) GetMember(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.CartMember, error) {
+	return store.members.GetMember(ctx, shoppingCartID, userID)
+}
+
+// UpdateMember updates the membership
+func (store *Store) UpdateMember(ctx context.Context, member *shoppingcart.CartMember) error {
+	defer store.written(ctx, member.ShoppingCartID)
+	return store.members.UpdateMember(ctx, member)
+}
+
+// RemoveMember removes the user from the members of the shopping cart
+func (store *Store) RemoveMember(ctx context.Context, shoppingCartID, userID int64) error {
+	defer store.written(ctx, shoppingCartID)
+	return store.members.RemoveMember(ctx, shoppingCartID, userID)
+}
+
+// key returns the key of the cart of the user in the current generation
+func (store *Store) key(ctx context.Context, ID, userID int64) string {
+	return fmt.Sprintf("shoppingcart:%d:%s:%d", ID, store.generation(ctx, ID), userID)
+}
+
+// generation returns the current generation of the cart. A new generation
+// is started if there is none, e.g. because it was evicted.
+func (store *Store) generation(ctx context.Context, ID int64) string {
+	generationKey := fmt.Sprintf("shoppingcart:%d:generation", ID)
+
+	generation, ok, err := store.cache.Get(ctx, generationKey)
+	if err == nil && ok {
+		return string(generation)
+	}
+
+	return store.newGeneration(ctx, ID)
+}
+
+// written invalidates the cart written by a call, or once the unit of work of
+// WithTx carried by ctx ends
+func (store *Store) written(ctx context.Context, ID int64) {
+	if written := store.unit(ctx); written != nil {
+		written[ID] = true
+		return
+	}
+
+	store.invalidate(ctx, ID)
+}
+
+// unit returns the carts written within the unit of work of WithTx carried
+// by ctx, nil outside of it
+func (store *Store) unit(ctx context.Context) map[int64]bool {
+	written, _ := ctx.Value(unitKey{store}).(map[int64]bool)
+	return written
+}
+
+// invalidate starts a new generation of the cart, so cached copies are no longer found
+func (store *Store) invalidate(ctx context.Context, ID int64) {
+	store.newGeneration(ctx, ID)
+}
+
+func (store *Store) newGeneration(ctx context.Context, ID int64) string {
+	generation := strconv.FormatInt(rand.Int63(), 36)
+
+	// The generation outlives the cached carts, they expire first
+	if err := store.cache.Set(ctx, fmt.Sprintf("shoppingcart:%d:generation", ID), []byte(generation), 2*store.ttl); err != nil {
+		logging.FromContext(ctx).Errorf("Unable to invalidate cached shopping cart %d: %s", ID, err)
+	}
+
+	return generation
+}
+
+func encode(cart shoppingcart.ShoppingCart) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cart); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decode(data []byte) (shoppingcart.ShoppingCart, error) {
+	var cart shoppingcart.ShoppingCart
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cart)
+
+	return cart, err
+}
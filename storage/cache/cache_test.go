@@ -0,0 +1,238 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+)
+
+// countingStorage counts the shopping carts retrieved from the mocked storage
+type countingStorage struct {
+	shoppingcart_mock.MockStorage
+	gets  int32
+	delay time.Duration
+}
+
+func (storage *countingStorage) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
+	atomic.AddInt32(&storage.gets, 1)
+	time.Sleep(storage.delay)
+	return storage.MockStorage.Get(ctx, ID, userID)
+}
+
+func TestStore_Get(t *testing.T) {
+	ctx := context.Background()
+	next := &countingStorage{}
+	store := New(next, NewLRU(100), Config{Members: next})
+
+	for i := 0; i < 3; i++ {
+		cart, err := store.Get(ctx, 1, 1)
+		if err != nil || len(cart.Items) != 4 || len(cart.SavedItems) != 2 || cart.SavedItems[0].List != shoppingcart.ListSaved {
+			t.Fatalf("Get() = %+v, %v, want cart with 4 items and 2 saved items", cart, err)
+		}
+	}
+	if next.gets != 1 {
+		t.Fatalf("Storage was read %d times, want %d", next.gets, 1)
+	}
+
+	// Members get their own copy, as access depends on the user
+	if _, err := store.Get(ctx, 1, 3); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if next.gets != 2 {
+		t.Fatalf("Storage was read %d times, want %d", next.gets, 2)
+	}
+
+	// Carts which are not found are not cached
+	for i := 0; i < 2; i++ {
+		if _, err := store.Get(ctx, 1, 2); err != shoppingcart.ErrCartNotFound {
+			t.Fatalf("Get() error = %v, want %v", err, shoppingcart.ErrCartNotFound)
+		}
+	}
+	if next.gets != 4 {
+		t.Fatalf("Storage was read %d times, want %d", next.gets, 4)
+	}
+}
+
+func TestStore_invalidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(context.Context, *Store) error
+	}{
+		{
+			name: "empty",
+			write: func(ctx context.Context, store *Store) error {
+				return store.Empty(ctx, 1)
+			},
+		},
+		{
+			name: "add product",
+			write: func(ctx context.Context, store *Store) error {
+				return store.AddProduct(ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 9, Quantity: 1})
+			},
+		},
+		{
+			name: "update product",
+			write: func(ctx context.Context, store *Store) error {
+				return store.UpdateProduct(ctx, &shoppingcart.ShoppingCartItem{ID: 1, ShoppingCartID: 1, ProductID: 1, Quantity: 2})
+			},
+		},
+		{
+			name: "remove product",
+			write: func(ctx context.Context, store *Store) error {
+				return store.RemoveProduct(ctx, 1, 1)
+			},
+		},
+		{
+			name: "remove item",
+			write: func(ctx context.Context, store *Store) error {
+				return store.RemoveItem(ctx, 1, 1)
+			},
+		},
+		{
+			name: "unit of work",
+			write: func(ctx context.Context, store *Store) error {
+				return store.WithTx(ctx, func(ctx context.Context) error {
+					return store.AddProduct(ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: 1, ProductID: 9, Quantity: 1})
+				})
+			},
+		},
+		{
+			name: "remove member",
+			write: func(ctx context.Context, store *Store) error {
+				return store.RemoveMember(ctx, 1, 3)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			next := &countingStorage{}
+			store := New(next, NewLRU(100), Config{Members: next})
+
+			for _, userID := range []int64{1, 3} {
+				if _, err := store.Get(ctx, 1, userID); err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+			}
+
+			if err := tt.write(ctx, store); err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			for _, userID := range []int64{1, 3} {
+				if _, err := store.Get(ctx, 1, userID); err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+			}
+			if next.gets != 4 {
+				t.Fatalf("Storage was read %d times, want %d", next.gets, 4)
+			}
+		})
+	}
+}
+
+func TestStore_ttl(t *testing.T) {
+	ctx := context.Background()
+	next := &countingStorage{}
+	store := New(next, NewLRU(100), Config{TTL: 10 * time.Millisecond})
+
+	if _, err := store.Get(ctx, 1, 1); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := store.Get(ctx, 1, 1); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if next.gets != 2 {
+		t.Fatalf("Storage was read %d times, want %d", next.gets, 2)
+	}
+}
+
+func TestStore_coalesce(t *testing.T) {
+	ctx := context.Background()
+	next := &countingStorage{delay: 50 * time.Millisecond}
+	store := New(next, NewLRU(100), Config{})
+
+	// Starting the generation of the cart up front, so all lookups use the same key
+	store.generation(ctx, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Get(ctx, 1, 1); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if next.gets != 1 {
+		t.Fatalf("Storage was read %d times, want %d", next.gets, 1)
+	}
+}
+
+// blockingStorage blocks retrieving shopping carts until release is closed
+// or the context is done
+type blockingStorage struct {
+	shoppingcart_mock.MockStorage
+	release chan struct{}
+}
+
+func (storage *blockingStorage) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
+	select {
+	case <-storage.release:
+		return storage.MockStorage.Get(ctx, ID, userID)
+	case <-ctx.Done():
+		return shoppingcart.ShoppingCart{}, ctx.Err()
+	}
+}
+
+func TestStore_coalesce_cancel(t *testing.T) {
+	next := &blockingStorage{release: make(chan struct{})}
+	store := New(next, NewLRU(100), Config{LoadTimeout: time.Second})
+	store.generation(context.Background(), 1)
+
+	// The caller starting the load gives up, the load goes on for the others
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan error)
+	go func() {
+		_, err := store.Get(ctx, 1, 1)
+		started <- err
+	}()
+
+	waiting := make(chan error)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, err := store.Get(context.Background(), 1, 1)
+		waiting <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-started; err != context.Canceled {
+		t.Fatalf("Get() error = %v, want %v", err, context.Canceled)
+	}
+
+	close(next.release)
+	if err := <-waiting; err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+}
+
+func TestStore_coalesce_timeout(t *testing.T) {
+	next := &blockingStorage{release: make(chan struct{})}
+	store := New(next, NewLRU(100), Config{LoadTimeout: 20 * time.Millisecond})
+
+	if _, err := store.Get(context.Background(), 1, 1); err != context.DeadlineExceeded {
+		t.Fatalf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// call is a load in flight
+type call struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// group coalesces concurrent loads of the same key, so only one of them
+// reaches the storage and the others wait for its result
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// do runs load once for all concurrent callers with the same key. The load
+// runs on a context which keeps the values of ctx but can't be canceled by
+// any of the callers, and ends after timeout. Every caller stops waiting
+// once its own ctx is done. shared reports whether the result was loaded
+// for another caller.
+func (g *group) do(ctx context.Context, key string, timeout time.Duration, load func(ctx context.Context) ([]byte, error)) (value []byte, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	c, shared := g.calls[key]
+	if !shared {
+		c = &call{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.load(detached{ctx}, key, timeout, c, load)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, shared, c.err
+	case <-ctx.Done():
+		return nil, shared, ctx.Err()
+	}
+}
+
+// load runs the load of the call and hands its result to the waiting callers
+func (g *group) load(ctx context.Context, key string, timeout time.Duration, c *call, load func(ctx context.Context) ([]byte, error)) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c.value, c.err = load(ctx)
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+}
+
+// detached carries the values of a context, such as its logger and trace,
+// without its deadline and cancellation
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detached) Done() <-chan struct{}       { return nil }
+func (detached) Err() error                  { return nil }
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in-process Cache which holds up to a fixed number of entries and
+// evicts the least recently used entry when it is full. Entries are not
+// shared between instances of the service.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+var _ Cache = (*LRU)(nil)
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU returns an empty LRU holding up to size entries
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value of the key, if it is cached and not expired
+func (lru *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	element, ok := lru.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		lru.remove(element)
+		return nil, false, nil
+	}
+
+	lru.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+// Set caches the value of the key for ttl
+func (lru *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if element, ok := lru.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		lru.order.MoveToFront(element)
+		return nil
+	}
+
+	lru.entries[key] = lru.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for lru.order.Len() > lru.size {
+		lru.remove(lru.order.Back())
+	}
+
+	return nil
+}
+
+// Len returns the number of cached entries, including expired ones
+func (lru *LRU) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	return lru.order.Len()
+}
+
+func (lru *LRU) remove(element *list.Element) {
+	lru.order.Remove(element)
+	delete(lru.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(2)
+
+	_ = lru.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = lru.Set(ctx, "b", []byte("2"), time.Minute)
+
+	// Reading a makes b the least recently used entry
+	if value, ok, _ := lru.Get(ctx, "a"); !ok || string(value) != "1" {
+		t.Fatalf("Get(a) = %q, %t, want %q", value, ok, "1")
+	}
+
+	_ = lru.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, ok, _ := lru.Get(ctx, "b"); ok {
+		t.Fatalf("Get(b) found evicted entry")
+	}
+	if lru.Len() != 2 {
+		t.Fatalf("Len() = %d, want %d", lru.Len(), 2)
+	}
+
+	_ = lru.Set(ctx, "a", []byte("4"), -time.Second)
+	if _, ok, _ := lru.Get(ctx, "a"); ok {
+		t.Fatalf("Get(a) found expired entry")
+	}
+	if lru.Len() != 1 {
+		t.Fatalf("Len() = %d, want %d", lru.Len(), 1)
+	}
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// Results of cache lookups
+const (
+	resultHit       = "hit"
+	resultMiss      = "miss"
+	resultCoalesced = "coalesced"
+)
+
+var (
+	// Lookups counts the lookups of shopping carts in the cache
+	Lookups = stats.Int64("shoppingcart/cache/lookups", "Number of shopping cart lookups in the cache", stats.UnitDimensionless)
+
+	// Result is either hit, miss or coalesced, which is a miss served by
+	// another lookup of the same cart in flight
+	Result = tag.MustNewKey("result")
+
+	// LookupsView exports Lookups by Result
+	LookupsView = &view.View{
+		Name:        "cache_lookups",
+		Description: "Number of shopping cart lookups in the cache by result",
+		Measure:     Lookups,
+		TagKeys:     []tag.Key{Result},
+		Aggregation: view.Count(),
+	}
+)
+
+func init() {
+	if err := view.Register(LookupsView); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// recordLookup records a lookup with the result
+func recordLookup(ctx context.Context, result string) {
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(Result, result)}, Lookups.M(1))
+}
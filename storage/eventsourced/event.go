@@ -0,0 +1,119 @@
+package eventsourced
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// ErrVersionConflict is returned by EventLog when an event with the same
+// version was already appended to the log of the cart
+var ErrVersionConflict = errors.New("event version already exists")
+
+// Types of the events of a shopping cart
+const (
+	CartCreated     = "CartCreated"
+	ItemAdded       = "ItemAdded"
+	QuantityChanged = "QuantityChanged"
+	ItemMoved       = "ItemMoved"
+	ItemRemoved     = "ItemRemoved"
+	CartEmptied     = "CartEmptied"
+)
+
+// Event is a single change of a shopping cart. Events of a cart carry
+// consecutive versions, starting with 1 for CartCreated.
+type Event struct {
+	ID             int64           `json:"id"`
+	ShoppingCartID int64           `json:"shoppingcart_id" gorm:"column:shoppingcart_id"`
+	Version        int64           `json:"version"`
+	Type           string          `json:"type"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// TableName specifies storage table name
+func (Event) TableName() string {
+	return "shoppingcart_event"
+}
+
+// Snapshot keeps the state of a shopping cart after the event of Version,
+// so the events before it don't need to be replayed.
+type Snapshot struct {
+	ShoppingCartID int64 `gorm:"column:shoppingcart_id;primary_key;auto_increment:false"`
+	Version        int64 `gorm:"primary_key;auto_increment:false"`
+	State          []byte
+	CreatedAt      time.Time
+}
+
+// TableName specifies storage table name
+func (Snapshot) TableName() string {
+	return "shoppingcart_event_snapshot"
+}
+
+// EventLog describes an interface to persist the events of shopping carts
+type EventLog interface {
+	// NewCartID registers a new shopping cart of the user and returns its ID
+	NewCartID(ctx context.Context, userID int64) (int64, error)
+
+	// AppendEvents appends events to the log atomically. If the version of
+	// any event already exists, nothing is appended and ErrVersionConflict
+	// is returned.
+	AppendEvents(ctx context.Context, events ...Event) error
+
+	// LoadEvents returns the events of the cart after afterVersion, oldest first
+	LoadEvents(ctx context.Context, shoppingCartID, afterVersion int64) ([]Event, error)
+
+	// SaveSnapshot stores a snapshot of the state of the cart
+	SaveSnapshot(ctx context.Context, snapshot Snapshot) error
+
+	// LoadSnapshot returns the latest snapshot of the cart created at or
+	// before at, or the latest snapshot if at is zero. The zero Snapshot is
+	// returned if there is none.
+	LoadSnapshot(ctx context.Context, shoppingCartID int64, at time.Time) (Snapshot, error)
+
+	// WithTx runs fn in a transaction of the log carried by the context
+	// passed to fn, which is rolled back if fn fails. Nested calls join the
+	// transaction of the outer call.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Payloads of the events
+
+type cartCreated struct {
+	UserID int64 `json:"user_id"`
+}
+
+type itemAdded struct {
+	ItemID     int64                   `json:"item_id"`
+	ProductID  int64                   `json:"product_id"`
+	VariantID  string                  `json:"variant_id,omitempty"`
+	Attributes shoppingcart.Attributes `json:"attributes,omitempty"`
+	Quantity   uint64                  `json:"quantity"`
+	List       string                  `json:"list"`
+	AddedBy    int64                   `json:"added_by,omitempty"`
+}
+
+type quantityChanged struct {
+	ItemID   int64  `json:"item_id"`
+	Quantity uint64 `json:"quantity"`
+}
+
+type itemMoved struct {
+	ItemID int64  `json:"item_id"`
+	List   string `json:"list"`
+}
+
+type itemRemoved struct {
+	ItemID int64 `json:"item_id"`
+}
+
+type cartEmptied struct{}
+
+// change is an event which is not appended yet
+type change struct {
+	eventType string
+	payload   interface{}
+}
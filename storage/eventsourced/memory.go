@@ -0,0 +1,106 @@
+package eventsourced
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLog keeps the events of shopping carts in memory. It is meant for
+// tests and local development, events are lost when the process exits.
+type MemoryLog struct {
+	mu         sync.Mutex
+	nextCartID int64
+	events     map[int64][]Event
+	snapshots  map[int64][]Snapshot
+}
+
+var _ EventLog = (*MemoryLog)(nil)
+
+// NewMemoryLog returns an empty MemoryLog
+func NewMemoryLog() *MemoryLog {
+	return &MemoryLog{
+		nextCartID: 1,
+		events:     make(map[int64][]Event),
+		snapshots:  make(map[int64][]Snapshot),
+	}
+}
+
+// NewCartID returns the next cart ID
+func (log *MemoryLog) NewCartID(ctx context.Context, userID int64) (int64, error) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	ID := log.nextCartID
+	log.nextCartID++
+
+	return ID, nil
+}
+
+// AppendEvents appends events to the log atomically
+func (log *MemoryLog) AppendEvents(ctx context.Context, events ...Event) error {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	versions := make(map[int64]int64)
+	for _, event := range events {
+		version, ok := versions[event.ShoppingCartID]
+		if !ok {
+			version = int64(len(log.events[event.ShoppingCartID]))
+		}
+		if event.Version != version+1 {
+			return ErrVersionConflict
+		}
+		versions[event.ShoppingCartID] = event.Version
+	}
+
+	for _, event := range events {
+		log.events[event.ShoppingCartID] = append(log.events[event.ShoppingCartID], event)
+	}
+
+	return nil
+}
+
+// LoadEvents returns the events of the cart after afterVersion, oldest first
+func (log *MemoryLog) LoadEvents(ctx context.Context, shoppingCartID, afterVersion int64) ([]Event, error) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	events := log.events[shoppingCartID]
+	if afterVersion >= int64(len(events)) {
+		return nil, nil
+	}
+
+	return append([]Event(nil), events[afterVersion:]...), nil
+}
+
+// SaveSnapshot stores a snapshot of the state of the cart
+func (log *MemoryLog) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	log.snapshots[snapshot.ShoppingCartID] = append(log.snapshots[snapshot.ShoppingCartID], snapshot)
+
+	return nil
+}
+
+// LoadSnapshot returns the latest snapshot of the cart created at or before at
+func (log *MemoryLog) LoadSnapshot(ctx context.Context, shoppingCartID int64, at time.Time) (Snapshot, error) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	snapshots := log.snapshots[shoppingCartID]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if at.IsZero() || !snapshots[i].CreatedAt.After(at) {
+			return snapshots[i], nil
+		}
+	}
+
+	return Snapshot{}, nil
+}
+
+// WithTx runs fn. Events are appended atomically by AppendEvents already, so
+// there is nothing to roll back.
+func (log *MemoryLog) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,213 @@
+package eventsourced
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// state is a shopping cart rebuilt by folding its events
+type state struct {
+	version    int64
+	nextItemID int64
+	cart       shoppingcart.ShoppingCart
+
+	// lines holds the active items and the items saved for later, in the
+	// order they were added
+	lines []shoppingcart.ShoppingCartItem
+}
+
+func newState() *state {
+	return &state{nextItemID: 1}
+}
+
+// apply folds the event into the state
+func (s *state) apply(event Event) error {
+	switch event.Type {
+	case CartCreated:
+		var payload cartCreated
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		s.cart.ID = event.ShoppingCartID
+		s.cart.UserID = payload.UserID
+		s.cart.CreatedAt = event.CreatedAt
+
+	case ItemAdded:
+		var payload itemAdded
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		s.lines = append(s.lines, shoppingcart.ShoppingCartItem{
+			ID:             payload.ItemID,
+			ShoppingCartID: event.ShoppingCartID,
+			ProductID:      payload.ProductID,
+			VariantID:      payload.VariantID,
+			Attributes:     payload.Attributes,
+			Quantity:       payload.Quantity,
+			List:           payload.List,
+			AddedBy:        payload.AddedBy,
+			CreatedAt:      event.CreatedAt,
+			UpdatedAt:      event.CreatedAt,
+		})
+		if payload.ItemID >= s.nextItemID {
+			s.nextItemID = payload.ItemID + 1
+		}
+
+	case QuantityChanged:
+		var payload quantityChanged
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		if line := s.line(payload.ItemID); line != nil {
+			line.Quantity = payload.Quantity
+			line.UpdatedAt = event.CreatedAt
+		}
+
+	case ItemMoved:
+		var payload itemMoved
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		if line := s.line(payload.ItemID); line != nil {
+			line.List = payload.List
+			line.UpdatedAt = event.CreatedAt
+		}
+
+	case ItemRemoved:
+		var payload itemRemoved
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		s.removeLines(func(line shoppingcart.ShoppingCartItem) bool {
+			return line.ID == payload.ItemID
+		})
+
+	case CartEmptied:
+		s.removeLines(func(line shoppingcart.ShoppingCartItem) bool {
+			return !line.Saved()
+		})
+
+	default:
+		return fmt.Errorf("unknown event type %q of shopping cart %d", event.Type, event.ShoppingCartID)
+	}
+
+	s.version = event.Version
+	s.cart.UpdatedAt = event.CreatedAt
+
+	return nil
+}
+
+// line returns the line with the ID, nil if there is none
+func (s *state) line(itemID int64) *shoppingcart.ShoppingCartItem {
+	for i := range s.lines {
+		if s.lines[i].ID == itemID {
+			return &s.lines[i]
+		}
+	}
+	return nil
+}
+
+// removeLines removes the lines for which remove returns true
+func (s *state) removeLines(remove func(shoppingcart.ShoppingCartItem) bool) {
+	lines := s.lines[:0]
+	for _, line := range s.lines {
+		if !remove(line) {
+			lines = append(lines, line)
+		}
+	}
+	s.lines = lines
+}
+
+// shoppingCart returns the cart with the lines split into active items and
+// items saved for later
+func (s *state) shoppingCart() shoppingcart.ShoppingCart {
+	cart := s.cart
+	cart.Items = nil
+	cart.SavedItems = nil
+
+	for _, line := range s.lines {
+		if line.Saved() {
+			cart.SavedItems = append(cart.SavedItems, line)
+			continue
+		}
+		cart.Items = append(cart.Items, line)
+	}
+
+	return cart
+}
+
+// encodedLine keeps the list of a line, which is not part of its JSON form
+type encodedLine struct {
+	shoppingcart.ShoppingCartItem
+	List string `json:"list"`
+}
+
+// encodedState is the form in which state is kept in snapshots
+type encodedState struct {
+	NextItemID int64         `json:"next_item_id"`
+	UserID     int64         `json:"user_id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	Lines      []encodedLine `json:"lines"`
+}
+
+// snapshot returns a snapshot of the state
+func (s *state) snapshot() (Snapshot, error) {
+	encoded := encodedState{
+		NextItemID: s.nextItemID,
+		UserID:     s.cart.UserID,
+		CreatedAt:  s.cart.CreatedAt,
+		UpdatedAt:  s.cart.UpdatedAt,
+		Lines:      make([]encodedLine, 0, len(s.lines)),
+	}
+	for _, line := range s.lines {
+		encoded.Lines = append(encoded.Lines, encodedLine{ShoppingCartItem: line, List: line.List})
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return Snapshot{
+		ShoppingCartID: s.cart.ID,
+		Version:        s.version,
+		State:          data,
+		CreatedAt:      s.cart.UpdatedAt,
+	}, nil
+}
+
+// stateFromSnapshot rebuilds the state kept in the snapshot
+func stateFromSnapshot(snapshot Snapshot) (*state, error) {
+	var encoded encodedState
+	if err := json.Unmarshal(snapshot.State, &encoded); err != nil {
+		return nil, err
+	}
+
+	s := &state{
+		version:    snapshot.Version,
+		nextItemID: encoded.NextItemID,
+		cart: shoppingcart.ShoppingCart{
+			ID:        snapshot.ShoppingCartID,
+			UserID:    encoded.UserID,
+			CreatedAt: encoded.CreatedAt,
+			UpdatedAt: encoded.UpdatedAt,
+		},
+		lines: make([]shoppingcart.ShoppingCartItem, 0, len(encoded.Lines)),
+	}
+	for _, line := range encoded.Lines {
+		item := line.ShoppingCartItem
+		item.List = line.List
+		s.lines = append(s.lines, item)
+	}
+
+	return s, nil
+}
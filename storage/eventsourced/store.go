@@ -0,0 +1,436 @@
+// Package eventsourced persists shopping carts as an append-only log of
+// events. The state of a cart is rebuilt by folding its events, starting from
+// the latest snapshot, which gives the full history of every cart and the
+// state of a cart at any point in time.
+package eventsourced
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/internal/logging"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+const (
+	// DefaultSnapshotInterval is the number of events after which a new
+	// snapshot of a cart is taken
+	DefaultSnapshotInterval = 50
+
+	// maxAttempts limits how often a change is retried when another change
+	// of the same cart was appended concurrently
+	maxAttempts = 3
+)
+
+// Config defines the settings of Store
+type Config struct {
+	// Members is optional, it grants members of shared carts access in Get
+	Members storage.CartMembers
+
+	// SnapshotInterval is the number of events after which a snapshot is
+	// taken, DefaultSnapshotInterval if zero
+	SnapshotInterval int64
+}
+
+// Store is an event-sourced implementation of storage.ShoppingCart
+type Store struct {
+	log              EventLog
+	members          storage.CartMembers
+	snapshotInterval int64
+}
+
+// unitKey is the context key of the unit of work of WithTx on the store
+type unitKey struct {
+	store *Store
+}
+
+// unitOfWork keeps the carts changed within a unit of work along with the
+// events which are appended once it completes
+type unitOfWork struct {
+	states   map[int64]*state
+	versions map[int64]int64
+	events   []Event
+}
+
+var _ storage.ShoppingCart = (*Store)(nil)
+
+// New returns a new Store keeping the events in log
+func New(log EventLog, config Config) *Store {
+	if config.SnapshotInterval <= 0 {
+		config.SnapshotInterval = DefaultSnapshotInterval
+	}
+
+	return &Store{
+		log:              log,
+		members:          config.Members,
+		snapshotInterval: config.SnapshotInterval,
+	}
+}
+
+// Create creates shopping cart in the storage
+func (store *Store) Create(ctx context.Context, cart *shoppingcart.ShoppingCart) error {
+	ID, err := store.log.NewCartID(ctx, cart.UserID)
+	if err != nil {
+		return err
+	}
+
+	s := newState()
+	s.cart.ID = ID
+	if err := store.append(ctx, s, change{CartCreated, cartCreated{UserID: cart.UserID}}); err != nil {
+		return err
+	}
+
+	*cart = s.shoppingCart()
+
+	return nil
+}
+
+// Get retrieves shopping cart from the storage along with items
+// The cart is found for its owner and for members who accepted the invitation,
+// Members only holds the membership of userID.
+func (store *Store) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
+	s, err := store.current(ctx, ID)
+	if err != nil {
+		return shoppingcart.ShoppingCart{}, err
+	}
+
+	cart := s.shoppingCart()
+	if cart.UserID == userID {
+		return cart, nil
+	}
+
+	if store.members == nil {
+		return shoppingcart.ShoppingCart{}, shoppingcart.ErrCartNotFound
+	}
+
+	member, err := store.members.GetMember(ctx, ID, userID)
+	if err != nil || !member.Accepted() {
+		return shoppingcart.ShoppingCart{}, shoppingcart.ErrCartNotFound
+	}
+	cart.Members = []shoppingcart.CartMember{member}
+
+	return cart, nil
+}
+
+// GetAt retrieves the shopping cart as it was at the given time, regardless
+// of the user. ErrCartNotFound is returned if the cart didn't exist yet.
+func (store *Store) GetAt(ctx context.Context, ID int64, at time.Time) (shoppingcart.ShoppingCart, error) {
+	s, err := store.load(ctx, ID, at)
+	if err != nil {
+		return shoppingcart.ShoppingCart{}, err
+	}
+
+	return s.shoppingCart(), nil
+}
+
+// Events retrieves all events of the shopping cart, oldest first
+func (store *Store) Events(ctx context.Context, ID int64) ([]Event, error) {
+	return store.log.LoadEvents(ctx, ID, 0)
+}
+
+// Empty removes active items associated with shopping cart
+func (store *Store) Empty(ctx context.Context, shoppingCartID int64) error {
+	return store.change(ctx, shoppingCartID, func(s *state) ([]change, error) {
+		if len(s.shoppingCart().Items) == 0 {
+			return nil, nil
+		}
+		return []change{{CartEmptied, cartEmptied{}}}, nil
+	})
+}
+
+// AddProduct adds product to the shopping cart
+func (store *Store) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	if cartItem.List == "" {
+		cartItem.List = shoppingcart.ListCart
+	}
+
+	return store.change(ctx, cartItem.ShoppingCartID, func(s *state) ([]change, error) {
+		cartItem.ID = s.nextItemID
+		return []change{{ItemAdded, itemAdded{
+			ItemID:     cartItem.ID,
+			ProductID:  cartItem.ProductID,
+			VariantID:  cartItem.VariantID,
+			Attributes: cartItem.Attributes,
+			Quantity:   cartItem.Quantity,
+			List:       cartItem.List,
+			AddedBy:    cartItem.AddedBy,
+		}}}, nil
+	})
+}
+
+// UpdateProduct updates the quantity and the list of the line in the shopping cart
+func (store *Store) UpdateProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	return store.change(ctx, cartItem.ShoppingCartID, func(s *state) ([]change, error) {
+		line := s.line(cartItem.ID)
+		if line == nil {
+			return nil, shoppingcart.ErrCartItemNotFound
+		}
+
+		var changes []change
+		if line.Quantity != cartItem.Quantity {
+			changes = append(changes, change{QuantityChanged, quantityChanged{ItemID: cartItem.ID, Quantity: cartItem.Quantity}})
+		}
+		if cartItem.List != "" && line.List != cartItem.List {
+			changes = append(changes, change{ItemMoved, itemMoved{ItemID: cartItem.ID, List: cartItem.List}})
+		}
+		return changes, nil
+	})
+}
+
+// RemoveProduct removes all active lines of the product from the shopping cart
+func (store *Store) RemoveProduct(ctx context.Context, shoppingCartID, productID int64) error {
+	return store.change(ctx, shoppingCartID, func(s *state) ([]change, error) {
+		cart := s.shoppingCart()
+
+		var changes []change
+		for _, item := range cart.ProductItems(productID) {
+			changes = append(changes, change{ItemRemoved, itemRemoved{ItemID: item.ID}})
+		}
+		return changes, nil
+	})
+}
+
+// RemoveItem removes a single line from the shopping cart, active or saved for later
+func (store *Store) RemoveItem(ctx context.Context, shoppingCartID, itemID int64) error {
+	return store.change(ctx, shoppingCartID, func(s *state) ([]change, error) {
+		if s.line(itemID) == nil {
+			return nil, nil
+		}
+		return []change{{ItemRemoved, itemRemoved{ItemID: itemID}}}, nil
+	})
+}
+
+// WithTx runs fn as a unit of work, which is carried by the context passed to
+// fn. Carts aren't locked: the events of all changes within fn are appended
+// together once it returns, and if another change of the carts was appended
+// in the meantime, fn is run again on their new state. Every attempt runs in
+// a transaction of the log, which the storages sharing its database join, so
+// their writes are discarded along with the events of a failed attempt. fn
+// must not run within such a transaction started elsewhere, as it couldn't
+// be retried on its own. Nothing is appended if fn fails.
+func (store *Store) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if store.unit(ctx) != nil {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		tx := &unitOfWork{states: map[int64]*state{}, versions: map[int64]int64{}}
+
+		err = store.log.WithTx(ctx, func(ctx context.Context) error {
+			if err := fn(context.WithValue(ctx, unitKey{store}, tx)); err != nil {
+				return err
+			}
+
+			return store.commit(ctx, tx)
+		})
+		if err == nil {
+			for ID, s := range tx.states {
+				store.snapshot(ctx, s, tx.versions[ID])
+			}
+			return nil
+		}
+		if err != ErrVersionConflict {
+			return err
+		}
+	}
+
+	return err
+}
+
+// unit returns the unit of work of WithTx carried by ctx, nil outside of it
+func (store *Store) unit(ctx context.Context) *unitOfWork {
+	tx, _ := ctx.Value(unitKey{store}).(*unitOfWork)
+	return tx
+}
+
+// current returns the current state of the cart. Within a unit of work, the
+// state is loaded once and includes the changes which aren't appended yet.
+func (store *Store) current(ctx context.Context, ID int64) (*state, error) {
+	tx := store.unit(ctx)
+	if tx == nil {
+		return store.load(ctx, ID, time.Time{})
+	}
+
+	if s, ok := tx.states[ID]; ok {
+		return s, nil
+	}
+
+	s, err := store.load(ctx, ID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	tx.states[ID] = s
+	tx.versions[ID] = s.version
+
+	return s, nil
+}
+
+// commit appends the events of the unit of work
+func (store *Store) commit(ctx context.Context, tx *unitOfWork) error {
+	if len(tx.events) == 0 {
+		return nil
+	}
+
+	return store.log.AppendEvents(ctx, tx.events...)
+}
+
+// load rebuilds the state of the cart from the latest snapshot and the
+// events after it. If at is set, only the events until then are folded.
+func (store *Store) load(ctx context.Context, ID int64, at time.Time) (*state, error) {
+	snapshot, err := store.log.LoadSnapshot(ctx, ID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newState()
+	if snapshot.Version > 0 {
+		if s, err = stateFromSnapshot(snapshot); err != nil {
+			return nil, err
+		}
+	}
+
+	events, err := store.log.LoadEvents(ctx, ID, s.version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if !at.IsZero() && event.CreatedAt.After(at) {
+			break
+		}
+		if err := s.apply(event); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.version == 0 {
+		return nil, shoppingcart.ErrCartNotFound
+	}
+
+	return s, nil
+}
+
+// change appends the changes decided on the current state of the cart. If
+// another change was appended in the meantime, the state is loaded again
+// and decide is retried.
+func (store *Store) change(ctx context.Context, ID int64, decide func(*state) ([]change, error)) error {
+	if tx := store.unit(ctx); tx != nil {
+		// The unit of work is retried as a whole on conflicts
+		s, err := store.current(ctx, ID)
+		if err != nil {
+			return err
+		}
+
+		changes, err := decide(s)
+		if err != nil {
+			return err
+		}
+
+		events, err := store.apply(s, changes...)
+		tx.events = append(tx.events, events...)
+
+		return err
+	}
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var s *state
+		if s, err = store.load(ctx, ID, time.Time{}); err != nil {
+			return err
+		}
+
+		var changes []change
+		if changes, err = decide(s); err != nil || len(changes) == 0 {
+			return err
+		}
+
+		if err = store.append(ctx, s, changes...); err != ErrVersionConflict {
+			return err
+		}
+	}
+
+	return err
+}
+
+// append appends the changes to the log and applies them to the state.
+// A snapshot is taken whenever the version crosses the snapshot interval.
+func (store *Store) append(ctx context.Context, s *state, changes ...change) error {
+	events, err := store.events(s, changes...)
+	if err != nil {
+		return err
+	}
+
+	if err := store.log.AppendEvents(ctx, events...); err != nil {
+		return err
+	}
+
+	previousVersion := s.version
+	for _, event := range events {
+		if err := s.apply(event); err != nil {
+			return err
+		}
+	}
+
+	store.snapshot(ctx, s, previousVersion)
+
+	return nil
+}
+
+// apply applies the changes to the state and returns their events
+func (store *Store) apply(s *state, changes ...change) ([]Event, error) {
+	events, err := store.events(s, changes...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if err := s.apply(event); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+// events returns the events of the changes following the version of the state
+func (store *Store) events(s *state, changes ...change) ([]Event, error) {
+	now := time.Now()
+	events := make([]Event, 0, len(changes))
+	for i, c := range changes {
+		payload, err := json.Marshal(c.payload)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, Event{
+			ShoppingCartID: s.cart.ID,
+			Version:        s.version + int64(i) + 1,
+			Type:           c.eventType,
+			Payload:        payload,
+			CreatedAt:      now,
+		})
+	}
+
+	return events, nil
+}
+
+// snapshot takes a snapshot of the state if its version crossed the snapshot
+// interval since previousVersion
+func (store *Store) snapshot(ctx context.Context, s *state, previousVersion int64) {
+	if s.version/store.snapshotInterval <= previousVersion/store.snapshotInterval {
+		return
+	}
+
+	// Snapshots only bound the replay cost, the events are already stored
+	snapshot, err := s.snapshot()
+	if err == nil {
+		err = store.log.SaveSnapshot(ctx, snapshot)
+	}
+	if err != nil {
+		logging.FromContext(ctx).Errorf("Unable to save snapshot of shopping cart %d: %s", s.cart.ID, err)
+	}
+}
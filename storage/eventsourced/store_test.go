@@ -0,0 +1,264 @@
+package eventsourced
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	shoppingcart_mock "github.com/bugimetal/shoppingcart/internal/mock/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage/storagetest"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := New(NewMemoryLog(), Config{})
+
+	cart := shoppingcart.ShoppingCart{UserID: 1}
+	if err := store.Create(ctx, &cart); err != nil || cart.ID != 1 {
+		t.Fatalf("Create() = %+v, %v, want cart %d", cart, err, 1)
+	}
+
+	shirt := shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, VariantID: "M", Quantity: 2, AddedBy: 1}
+	shoes := shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 2, Quantity: 1, AddedBy: 1}
+	for _, item := range []*shoppingcart.ShoppingCartItem{&shirt, &shoes} {
+		if err := store.AddProduct(ctx, item); err != nil {
+			t.Fatalf("AddProduct() error = %v", err)
+		}
+	}
+	if shirt.ID != 1 || shoes.ID != 2 {
+		t.Fatalf("AddProduct() assigned lines %d and %d, want %d and %d", shirt.ID, shoes.ID, 1, 2)
+	}
+
+	shirt.Quantity = 3
+	if err := store.UpdateProduct(ctx, &shirt); err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+
+	shoes.List = shoppingcart.ListSaved
+	if err := store.UpdateProduct(ctx, &shoes); err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+
+	got, err := store.Get(ctx, cart.ID, 1)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got.Items) != 1 || got.Items[0].Quantity != 3 || got.Items[0].VariantID != "M" {
+		t.Fatalf("Get() items = %+v, want shirt with quantity %d", got.Items, 3)
+	}
+	if len(got.SavedItems) != 1 || got.SavedItems[0].ID != shoes.ID {
+		t.Fatalf("Get() saved items = %+v, want shoes", got.SavedItems)
+	}
+
+	if err := store.Empty(ctx, cart.ID); err != nil {
+		t.Fatalf("Empty() error = %v", err)
+	}
+
+	got, _ = store.Get(ctx, cart.ID, 1)
+	if len(got.Items) != 0 || len(got.SavedItems) != 1 {
+		t.Fatalf("Get() after Empty() = %+v, want saved items only", got)
+	}
+
+	if err := store.RemoveItem(ctx, cart.ID, shoes.ID); err != nil {
+		t.Fatalf("RemoveItem() error = %v", err)
+	}
+
+	events, err := store.Events(ctx, cart.ID)
+	if err != nil {
+		t.Fatalf("Events() error = %v", err)
+	}
+
+	want := []string{CartCreated, ItemAdded, ItemAdded, QuantityChanged, ItemMoved, CartEmptied, ItemRemoved}
+	if len(events) != len(want) {
+		t.Fatalf("Events() = %+v, want %v", events, want)
+	}
+	for i, event := range events {
+		if event.Type != want[i] || event.Version != int64(i+1) {
+			t.Fatalf("Event %d = %s version %d, want %s version %d", i, event.Type, event.Version, want[i], i+1)
+		}
+	}
+}
+
+func TestStore_Get(t *testing.T) {
+	ctx := context.Background()
+	store := New(NewMemoryLog(), Config{Members: &shoppingcart_mock.MockStorage{}})
+
+	cart := shoppingcart.ShoppingCart{UserID: 1}
+	if err := store.Create(ctx, &cart); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ID      int64
+		userID  int64
+		wantErr error
+	}{
+		{
+			name:   "owner",
+			ID:     cart.ID,
+			userID: 1,
+		},
+		{
+			name:   "accepted member",
+			ID:     cart.ID,
+			userID: 3,
+		},
+		{
+			name:    "invited user",
+			ID:      cart.ID,
+			userID:  5,
+			wantErr: shoppingcart.ErrCartNotFound,
+		},
+		{
+			name:    "other user",
+			ID:      cart.ID,
+			userID:  2,
+			wantErr: shoppingcart.ErrCartNotFound,
+		},
+		{
+			name:    "unknown cart",
+			ID:      2,
+			userID:  1,
+			wantErr: shoppingcart.ErrCartNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Get(ctx, tt.ID, tt.userID)
+			if err != tt.wantErr {
+				t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !got.CanEdit(tt.userID) {
+				t.Fatalf("Get() = %+v, want cart which user %d can edit", got, tt.userID)
+			}
+		})
+	}
+}
+
+func TestStore_snapshots(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryLog()
+	store := New(log, Config{SnapshotInterval: 4})
+
+	cart := shoppingcart.ShoppingCart{UserID: 1}
+	if err := store.Create(ctx, &cart); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	item := shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 1, Attributes: shoppingcart.Attributes{"engraving": "For Anna"}}
+	if err := store.AddProduct(ctx, &item); err != nil {
+		t.Fatalf("AddProduct() error = %v", err)
+	}
+	for quantity := uint64(2); quantity <= 5; quantity++ {
+		item.Quantity = quantity
+		if err := store.UpdateProduct(ctx, &item); err != nil {
+			t.Fatalf("UpdateProduct() error = %v", err)
+		}
+	}
+
+	snapshot, _ := log.LoadSnapshot(ctx, cart.ID, time.Time{})
+	if snapshot.Version != 4 {
+		t.Fatalf("Latest snapshot has version %d, want %d", snapshot.Version, 4)
+	}
+
+	// State is rebuilt from the snapshot and the events after it
+	s, err := store.load(ctx, cart.ID, time.Time{})
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	line := s.line(item.ID)
+	if s.version != 6 || line == nil || line.Quantity != 5 || line.List != shoppingcart.ListCart || !line.Attributes.Equal(item.Attributes) {
+		t.Fatalf("load() = version %d, line %+v, want version %d with quantity %d", s.version, line, 6, 5)
+	}
+
+	next := shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 2, Quantity: 1}
+	if err := store.AddProduct(ctx, &next); err != nil || next.ID != 2 {
+		t.Fatalf("AddProduct() assigned line %d, %v, want %d", next.ID, err, 2)
+	}
+}
+
+func TestStore_GetAt(t *testing.T) {
+	ctx := context.Background()
+	log := NewMemoryLog()
+	store := New(log, Config{SnapshotInterval: 2})
+
+	cart := shoppingcart.ShoppingCart{UserID: 1}
+	if err := store.Create(ctx, &cart); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	item := shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 1}
+	if err := store.AddProduct(ctx, &item); err != nil {
+		t.Fatalf("AddProduct() error = %v", err)
+	}
+
+	time.Sleep(time.Millisecond)
+	at := time.Now()
+	time.Sleep(time.Millisecond)
+
+	if err := store.Empty(ctx, cart.ID); err != nil {
+		t.Fatalf("Empty() error = %v", err)
+	}
+
+	got, err := store.GetAt(ctx, cart.ID, at)
+	if err != nil || len(got.Items) != 1 || got.Items[0].ProductID != 1 {
+		t.Fatalf("GetAt() = %+v, %v, want cart with product %d", got, err, 1)
+	}
+
+	if _, err := store.GetAt(ctx, cart.ID, cart.CreatedAt.Add(-time.Second)); err != shoppingcart.ErrCartNotFound {
+		t.Fatalf("GetAt() error = %v, want %v", err, shoppingcart.ErrCartNotFound)
+	}
+}
+
+// conflictingLog appends a concurrent event before the first append
+type conflictingLog struct {
+	*MemoryLog
+	conflicted bool
+}
+
+func (log *conflictingLog) AppendEvents(ctx context.Context, events ...Event) error {
+	if !log.conflicted {
+		log.conflicted = true
+		concurrent := events[0]
+		concurrent.Type = ItemAdded
+		concurrent.Payload = []byte(`{"item_id":7,"product_id":9,"quantity":1,"list":"cart"}`)
+		if err := log.MemoryLog.AppendEvents(ctx, concurrent); err != nil {
+			return err
+		}
+	}
+
+	return log.MemoryLog.AppendEvents(ctx, events...)
+}
+
+func TestStore_versionConflict(t *testing.T) {
+	ctx := context.Background()
+	memoryLog := NewMemoryLog()
+	store := New(memoryLog, Config{})
+
+	cart := shoppingcart.ShoppingCart{UserID: 1}
+	if err := store.Create(ctx, &cart); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	store.log = &conflictingLog{MemoryLog: memoryLog}
+
+	item := shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 1}
+	if err := store.AddProduct(ctx, &item); err != nil {
+		t.Fatalf("AddProduct() error = %v", err)
+	}
+
+	got, _ := store.Get(ctx, cart.ID, 1)
+	if len(got.Items) != 2 || item.ID != 8 {
+		t.Fatalf("Get() items = %+v, added line %d, want both lines and line %d", got.Items, item.ID, 8)
+	}
+}
+
+func TestStore_behavior(t *testing.T) {
+	store := New(NewMemoryLog(), Config{SnapshotInterval: 3})
+
+	t.Run("ShoppingCart", func(t *testing.T) { storagetest.ShoppingCart(t, store) })
+	t.Run("Transactions", func(t *testing.T) { storagetest.Transactions(t, store) })
+}
@@ -1,29 +1,113 @@
 package mysql
 
 import (
-	"fmt"
+	"errors"
+	"net"
+	"strconv"
 
+	"github.com/bugimetal/shoppingcart/storage/sqlstore"
+
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
 )
 
-type DB struct {
-	client *gorm.DB
+// MySQL error numbers of constraint violations
+const (
+	errDuplicateEntry    = 1062
+	errNoReferencedRow   = 1452
+	errNoReferencedRowV1 = 1216
+)
+
+// tlsConfigName is the name the TLS configuration is registered with at the driver
+const tlsConfigName = "shoppingcart"
+
+// Config defines the connection to the database
+type Config struct {
+	User     string
+	Password string
+	Host     string
+	Port     int
+	Database string
+
+	TLS sqlstore.TLSConfig
+
+	// Params are added to the DSN, e.g. timeout or readTimeout
+	Params map[string]string
 }
 
 // New creates database connection
-func New(user, password, host, database string, port int) (*DB, error) {
-	db, err := gorm.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&charset=utf8", user, password, host, port, database))
+func New(config Config) (*sqlstore.DB, error) {
+	dsn, err := config.dsn()
+	if err != nil {
+		return &sqlstore.DB{}, err
+	}
+
+	db, err := gorm.Open("mysql", dsn)
+	if err != nil {
+		return &sqlstore.DB{}, err
+	}
+
+	return sqlstore.New(db, dialect{}), nil
+}
+
+// dsn returns the DSN of the connection. The TLS configuration is
+// registered at the driver, as the DSN can only refer to it by name.
+func (config Config) dsn() (string, error) {
+	dsnConfig := driver.NewConfig()
+	dsnConfig.User = config.User
+	dsnConfig.Passwd = config.Password
+	dsnConfig.Net = "tcp"
+	dsnConfig.Addr = net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	dsnConfig.DBName = config.Database
+	dsnConfig.ParseTime = true
+
+	dsnConfig.Params = map[string]string{"charset": "utf8"}
+	for key, value := range config.Params {
+		dsnConfig.Params[key] = value
+	}
+
+	tlsConfig, err := config.TLS.Load(config.Host)
 	if err != nil {
-		return &DB{}, err
+		return "", err
+	}
+	if tlsConfig != nil {
+		if err := driver.RegisterTLSConfig(tlsConfigName, tlsConfig); err != nil {
+			return "", err
+		}
+		dsnConfig.TLSConfig = tlsConfigName
 	}
 
-	return &DB{client: db}, nil
+	return dsnConfig.FormatDSN(), nil
+}
+
+// dialect recognizes the errors of the MySQL driver
+type dialect struct{}
+
+func (dialect) IsUniqueViolation(err error) bool {
+	return hasNumber(err, errDuplicateEntry)
 }
 
-// Close closes the connection to database
-func (db *DB) Close() {
-	if db.client != nil {
-		db.client.Close()
+func (dialect) IsForeignKeyViolation(err error) bool {
+	return hasNumber(err, errNoReferencedRow, errNoReferencedRowV1)
+}
+
+func (dialect) RowLock() string {
+	return "FOR UPDATE"
+}
+
+// hasNumber reports whether err is a MySQL error with one of numbers
+func hasNumber(err error, numbers ...uint16) bool {
+	var mysqlErr *driver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
 	}
+
+	for _, number := range numbers {
+		if mysqlErr.Number == number {
+			return true
+		}
+	}
+
+	return false
 }
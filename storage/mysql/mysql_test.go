@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart/storage/eventsourced"
+	"github.com/bugimetal/shoppingcart/storage/sqlstore"
+	"github.com/bugimetal/shoppingcart/storage/storagetest"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+)
+
+// The behavioral tests run against the migrated database of
+// SHOPPINGCART_TEST_MYSQL_DSN, e.g. "shoppingcart:secret@/shoppingcart?parseTime=true"
+func TestStorage(t *testing.T) {
+	dsn := os.Getenv("SHOPPINGCART_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("SHOPPINGCART_TEST_MYSQL_DSN is not set")
+	}
+
+	client, err := gorm.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("Can't connect to database: %v", err)
+	}
+
+	db := sqlstore.New(client, dialect{})
+	defer db.Close()
+
+	storagetest.Run(t, db)
+
+	t.Run("EventSourced", func(t *testing.T) {
+		store := eventsourced.New(db.EventLog(), eventsourced.Config{Members: db, SnapshotInterval: 3})
+		storagetest.ShoppingCart(t, store)
+		storagetest.CartMembers(t, store, db)
+		storagetest.SharedTransactions(t, store, db)
+	})
+}
+
+func TestConfig_dsn(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "defaults",
+			config: Config{User: "shoppingcart", Password: "secret", Host: "localhost", Port: 3306, Database: "shoppingcart"},
+			want:   "shoppingcart:secret@tcp(localhost:3306)/shoppingcart?parseTime=true&charset=utf8",
+		},
+		{
+			name: "params and TLS",
+			config: Config{
+				User: "shoppingcart", Password: "secret", Host: "db.internal", Port: 3306, Database: "shoppingcart",
+				TLS:    sqlstore.TLSConfig{Mode: sqlstore.TLSRequire},
+				Params: map[string]string{"timeout": "5s", "charset": "utf8mb4"},
+			},
+			want: "shoppingcart:secret@tcp(db.internal:3306)/shoppingcart?parseTime=true&tls=shoppingcart&charset=utf8mb4&timeout=5s",
+		},
+		{
+			name:    "unknown TLS mode",
+			config:  Config{Host: "localhost", Port: 3306, TLS: sqlstore.TLSConfig{Mode: "always"}},
+			wantErr: sqlstore.ErrInvalidTLSConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn, err := tt.config.dsn()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, but got %v", tt.wantErr, err)
+			}
+			if dsn != tt.want {
+				t.Fatalf("Expected DSN %q, but got %q", tt.want, dsn)
+			}
+		})
+	}
+}
+
+func TestDialect(t *testing.T) {
+	tests := []struct {
+		err        error
+		unique     bool
+		foreignKey bool
+	}{
+		{&driver.MySQLError{Number: errDuplicateEntry}, true, false},
+		{&driver.MySQLError{Number: errNoReferencedRow}, false, true},
+		{fmt.Errorf("insert: %w", &driver.MySQLError{Number: errNoReferencedRowV1}), false, true},
+		{&driver.MySQLError{Number: 1146}, false, false},
+		{gorm.ErrRecordNotFound, false, false},
+	}
+
+	for _, test := range tests {
+		if unique := (dialect{}).IsUniqueViolation(test.err); unique != test.unique {
+			t.Errorf("Expected unique violation of %v to be %t, but got %t", test.err, test.unique, unique)
+		}
+		if foreignKey := (dialect{}).IsForeignKeyViolation(test.err); foreignKey != test.foreignKey {
+			t.Errorf("Expected foreign key violation of %v to be %t, but got %t", test.err, test.foreignKey, foreignKey)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bugimetal/shoppingcart/storage/sqlstore"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/lib/pq"
+)
+
+// PostgreSQL error codes of constraint violations
+const (
+	errUniqueViolation     = "23505"
+	errForeignKeyViolation = "23503"
+)
+
+// Config defines the connection to the database
+type Config struct {
+	User     string
+	Password string
+	Host     string
+	Port     int
+	Database string
+
+	TLS sqlstore.TLSConfig
+
+	// Params are added to the DSN, e.g. connect_timeout or application_name
+	Params map[string]string
+}
+
+// New creates database connection
+func New(config Config) (*sqlstore.DB, error) {
+	dsn, err := config.dsn()
+	if err != nil {
+		return &sqlstore.DB{}, err
+	}
+
+	db, err := gorm.Open("postgres", dsn)
+	if err != nil {
+		return &sqlstore.DB{}, err
+	}
+
+	return sqlstore.New(db, dialect{}), nil
+}
+
+// dsn returns the DSN of the connection, as key=value pairs ordered by key
+func (config Config) dsn() (string, error) {
+	if err := config.TLS.Validate(); err != nil {
+		return "", err
+	}
+
+	params := map[string]string{
+		"host":     config.Host,
+		"port":     strconv.Itoa(config.Port),
+		"user":     config.User,
+		"password": config.Password,
+		"dbname":   config.Database,
+		"sslmode":  sqlstore.TLSDisable,
+	}
+	if config.TLS.Mode != "" {
+		params["sslmode"] = config.TLS.Mode
+	}
+	if config.TLS.CAFile != "" {
+		params["sslrootcert"] = config.TLS.CAFile
+	}
+	if config.TLS.CertFile != "" {
+		params["sslcert"] = config.TLS.CertFile
+	}
+	if config.TLS.KeyFile != "" {
+		params["sslkey"] = config.TLS.KeyFile
+	}
+	for key, value := range config.Params {
+		params[key] = value
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+quote(params[key]))
+	}
+
+	return strings.Join(pairs, " "), nil
+}
+
+// quote quotes value for the DSN, so it may contain spaces and quotes
+func quote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// dialect recognizes the errors of the PostgreSQL driver
+type dialect struct{}
+
+func (dialect) IsUniqueViolation(err error) bool {
+	return hasCode(err, errUniqueViolation)
+}
+
+func (dialect) IsForeignKeyViolation(err error) bool {
+	return hasCode(err, errForeignKeyViolation)
+}
+
+func (dialect) RowLock() string {
+	return "FOR UPDATE"
+}
+
+// hasCode reports whether err is a PostgreSQL error with code
+func hasCode(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
@@ -0,0 +1,76 @@
+// Package sqlite keeps shopping carts in a SQLite database file, for
+// single-node deployments which don't run a database server.
+//
+// The database is opened in WAL mode, so reads don't wait for writes.
+// SQLite allows a single writer at a time: every write runs in a transaction
+// which takes the write lock up front and waits for other writers instead of
+// failing with SQLITE_BUSY.
+package sqlite
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/bugimetal/shoppingcart/storage/sqlstore"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	driver "github.com/mattn/go-sqlite3"
+)
+
+// busyTimeout is how long writers wait for the write lock, in milliseconds
+const busyTimeout = "5000"
+
+// New opens the database file at path, which is created if it doesn't exist
+func New(path string) (*sqlstore.DB, error) {
+	db, err := open(path)
+	if err != nil {
+		return &sqlstore.DB{}, err
+	}
+
+	return sqlstore.New(db, dialect{}), nil
+}
+
+// open connects to the database file at path with WAL mode, foreign keys
+// and immediate transactions
+func open(path string) (*gorm.DB, error) {
+	params := url.Values{}
+	params.Set("_journal_mode", "WAL")
+	params.Set("_foreign_keys", "1")
+	params.Set("_busy_timeout", busyTimeout)
+	params.Set("_txlock", "immediate")
+
+	return gorm.Open("sqlite3", "file:"+path+"?"+params.Encode())
+}
+
+// dialect recognizes the errors of the SQLite driver
+type dialect struct{}
+
+func (dialect) IsUniqueViolation(err error) bool {
+	return hasCode(err, driver.ErrConstraintUnique, driver.ErrConstraintPrimaryKey)
+}
+
+func (dialect) IsForeignKeyViolation(err error) bool {
+	return hasCode(err, driver.ErrConstraintForeignKey)
+}
+
+// Transactions take the write lock of the database up front, see open
+func (dialect) RowLock() string {
+	return ""
+}
+
+// hasCode reports whether err is a SQLite error with one of the extended codes
+func hasCode(err error, codes ...driver.ErrNoExtended) bool {
+	var sqliteErr driver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	for _, code := range codes {
+		if sqliteErr.ExtendedCode == code {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/migrations"
+	"github.com/bugimetal/shoppingcart/storage/eventsourced"
+	"github.com/bugimetal/shoppingcart/storage/sqlstore"
+	"github.com/bugimetal/shoppingcart/storage/storagetest"
+
+	driver "github.com/mattn/go-sqlite3"
+)
+
+// newTestDB opens a migrated database in a temporary directory
+func newTestDB(t *testing.T) (*sqlstore.DB, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "shoppingcart")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s", err)
+	}
+
+	client, err := open(filepath.Join(dir, "shoppingcart.db"))
+	if err != nil {
+		t.Fatalf("Can't open database: %v", err)
+	}
+
+	migrator, err := migrations.New(client.DB(), "sqlite")
+	if err != nil {
+		t.Fatalf("Unable to load migrations: %s", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Unable to migrate database: %s", err)
+	}
+
+	db := sqlstore.New(client, dialect{})
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestStorage(t *testing.T) {
+	db, closeDB := newTestDB(t)
+	defer closeDB()
+
+	storagetest.Run(t, db)
+
+	t.Run("EventSourced", func(t *testing.T) {
+		store := eventsourced.New(db.EventLog(), eventsourced.Config{Members: db, SnapshotInterval: 3})
+		storagetest.ShoppingCart(t, store)
+		storagetest.CartMembers(t, store, db)
+		storagetest.SharedTransactions(t, store, db)
+	})
+}
+
+func TestOpen_walMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "shoppingcart")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client, err := open(filepath.Join(dir, "shoppingcart.db"))
+	if err != nil {
+		t.Fatalf("Can't open database: %v", err)
+	}
+	defer client.Close()
+
+	var mode string
+	if err := client.DB().QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("Unable to read journal mode: %s", err)
+	}
+	if mode != "wal" {
+		t.Errorf("Expected journal mode wal, but got %s", mode)
+	}
+}
+
+// Concurrent writers wait for each other instead of failing with SQLITE_BUSY
+func TestStorage_concurrentWrites(t *testing.T) {
+	db, closeDB := newTestDB(t)
+	defer closeDB()
+
+	ctx := context.Background()
+	cart := shoppingcart.ShoppingCart{UserID: 1}
+	if err := db.Create(ctx, &cart); err != nil {
+		t.Fatalf("Unable to create shopping cart: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 1; i <= cap(errs); i++ {
+		wg.Add(1)
+		go func(productID int64) {
+			defer wg.Done()
+			errs <- db.AddProduct(ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: productID, Quantity: 1})
+		}(int64(i))
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Unable to add product: %s", err)
+		}
+	}
+
+	got, err := db.Get(ctx, cart.ID, cart.UserID)
+	if err != nil {
+		t.Fatalf("Unable to get shopping cart: %s", err)
+	}
+	if len(got.Items) != cap(errs) {
+		t.Errorf("Expected %d items, but got %d", cap(errs), len(got.Items))
+	}
+}
+
+func TestStorage_queryTimeout(t *testing.T) {
+	db, closeDB := newTestDB(t)
+	defer closeDB()
+
+	// Queries time out before they start
+	db.SetQueryTimeout(time.Nanosecond)
+
+	if _, err := db.Get(context.Background(), 1, 1); !errors.Is(err, shoppingcart.ErrStorageTimeout) {
+		t.Fatalf("Expected error %v, but got %v", shoppingcart.ErrStorageTimeout, err)
+	}
+
+	// The context of the call takes precedence
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := db.Get(ctx, 1, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected error %v, but got %v", context.Canceled, err)
+	}
+}
+
+func TestDialect(t *testing.T) {
+	tests := []struct {
+		err        error
+		unique     bool
+		foreignKey bool
+	}{
+		{driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintUnique}, true, false},
+		{driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintPrimaryKey}, true, false},
+		{fmt.Errorf("insert: %w", driver.Error{Code: driver.ErrConstraint, ExtendedCode: driver.ErrConstraintForeignKey}), false, true},
+		{driver.Error{Code: driver.ErrBusy}, false, false},
+		{errors.New("record not found"), false, false},
+	}
+
+	for _, test := range tests {
+		if unique := (dialect{}).IsUniqueViolation(test.err); unique != test.unique {
+			t.Errorf("Expected unique violation of %v to be %t, but got %t", test.err, test.unique, unique)
+		}
+		if foreignKey := (dialect{}).IsForeignKeyViolation(test.err); foreignKey != test.foreignKey {
+			t.Errorf("Expected foreign key violation of %v to be %t, but got %t", test.err, test.foreignKey, foreignKey)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/tracing"
+
+	"go.opencensus.io/trace"
+)
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn runs the queries of gorm within the context of a storage call, as gorm
+// doesn't pass contexts on to database/sql. ctx is the context of the call,
+// bounded by the query timeout. Every statement is traced, see statement.
+type conn struct {
+	ctx    context.Context
+	parent context.Context
+	db     queryer
+	system string
+}
+
+func (c conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	span := c.statement("Exec", query)
+	result, err := c.db.ExecContext(c.ctx, query, args...)
+	if err == nil {
+		if rows, err := result.RowsAffected(); err == nil {
+			span.AddAttributes(trace.Int64Attribute("db.rows_affected", rows))
+		}
+	}
+
+	err = c.err(err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (c conn) Prepare(query string) (*sql.Stmt, error) {
+	span := c.statement("Prepare", query)
+	stmt, err := c.db.PrepareContext(c.ctx, query)
+
+	err = c.err(err)
+	tracing.End(span, err)
+	return stmt, err
+}
+
+func (c conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	span := c.statement("Query", query)
+	rows, err := c.db.QueryContext(c.ctx, query, args...)
+
+	err = c.err(err)
+	tracing.End(span, err)
+	return rows, err
+}
+
+func (c conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	span := c.statement("QueryRow", query)
+	row := c.db.QueryRowContext(c.ctx, query, args...)
+
+	tracing.End(span, c.err(row.Err()))
+	return row
+}
+
+// statement starts the span of a statement run by method of database/sql.
+// The span holds the summary of the statement, without any values, and ends
+// once the statement returns: reading the rows of a query is not part of it.
+func (c conn) statement(method, query string) *trace.Span {
+	_, span := trace.StartSpan(c.ctx, "sql."+method, trace.WithSpanKind(trace.SpanKindClient))
+	span.AddAttributes(
+		trace.StringAttribute("db.system", c.system),
+		trace.StringAttribute("db.statement", summarize(query)),
+	)
+
+	return span
+}
+
+// summarize returns the operation of query along with the table it works on,
+// e.g. "SELECT shoppingcart_item"
+func summarize(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	operation := strings.ToUpper(fields[0])
+
+	// The table follows the keyword, or the operation itself for updates
+	keyword := map[string]string{"SELECT": "FROM", "DELETE": "FROM", "INSERT": "INTO", "UPDATE": "UPDATE"}[operation]
+	if keyword == "" {
+		return operation
+	}
+
+	for i, field := range fields[:len(fields)-1] {
+		if strings.EqualFold(field, keyword) {
+			return operation + " " + strings.Trim(fields[i+1], "`\"'(),;")
+		}
+	}
+
+	return operation
+}
+
+// err reports a query which failed because its context ended with the error
+// of the context of the call, or with ErrStorageTimeout if the query timed
+// out. Drivers report canceled queries in their own ways.
+func (c conn) err(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case c.parent.Err() != nil:
+		return c.parent.Err()
+	case c.ctx.Err() != nil:
+		return shoppingcart.ErrStorageTimeout
+	}
+
+	return err
+}
+
+// poolConn is the conn of the connection pool. gorm starts transactions
+// through it for writes of several rows, e.g. of a snapshot and its items.
+type poolConn struct {
+	*conn
+	pool *sql.DB
+}
+
+func (c poolConn) Begin() (*sql.Tx, error) {
+	return c.BeginTx(c.ctx, nil)
+}
+
+func (c poolConn) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	tx, err := c.pool.BeginTx(ctx, opts)
+	return tx, c.err(err)
+}
@@ -0,0 +1,26 @@
+package sqlstore
+
+import "testing"
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: `SELECT * FROM "shoppingcart"  WHERE ("shoppingcart"."id" = $1)`, want: "SELECT shoppingcart"},
+		{query: "select id from `shoppingcart_item` where shopping_cart_id = ?", want: "SELECT shoppingcart_item"},
+		{query: "INSERT  INTO `shoppingcart_item` (`product_id`,`quantity`) VALUES (?,?)", want: "INSERT shoppingcart_item"},
+		{query: `UPDATE "shoppingcart_item" SET "quantity" = $1`, want: "UPDATE shoppingcart_item"},
+		{query: "DELETE FROM shoppingcart_item WHERE id = ?", want: "DELETE shoppingcart_item"},
+		{query: "SELECT 1", want: "SELECT"},
+		{query: "PRAGMA foreign_keys = ON", want: "PRAGMA"},
+		{query: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := summarize(tt.query); got != tt.want {
+				t.Fatalf("Expected summary %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
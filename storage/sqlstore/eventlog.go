@@ -0,0 +1,107 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage/eventsourced"
+
+	"github.com/jinzhu/gorm"
+)
+
+// EventLog keeps the events of event-sourced shopping carts in the database.
+// Carts are still registered in the shoppingcart table, so members, snapshots
+// and history keep referring to them.
+type EventLog struct {
+	db *DB
+}
+
+var _ eventsourced.EventLog = (*EventLog)(nil)
+
+// EventLog returns the event log sharing the connection of db
+func (db *DB) EventLog() *EventLog {
+	return &EventLog{db: db}
+}
+
+// NewCartID registers a new shopping cart of the user and returns its ID
+func (log *EventLog) NewCartID(ctx context.Context, userID int64) (int64, error) {
+	client, release := log.db.conn(ctx)
+	defer release()
+
+	cart := shoppingcart.ShoppingCart{UserID: userID, CreatedAt: time.Now()}
+	cart.UpdatedAt = cart.CreatedAt
+
+	if err := client.Create(&cart).Error; err != nil {
+		return 0, err
+	}
+
+	return cart.ID, nil
+}
+
+// AppendEvents appends events to the log in a single transaction, or within
+// the transaction of WithTx carried by ctx
+func (log *EventLog) AppendEvents(ctx context.Context, events ...eventsourced.Event) error {
+	return log.db.WithTx(ctx, func(ctx context.Context) error {
+		client, release := log.db.conn(ctx)
+		defer release()
+
+		for i := range events {
+			if err := client.Create(&events[i]).Error; err != nil {
+				if log.db.dialect.IsUniqueViolation(err) {
+					return eventsourced.ErrVersionConflict
+				}
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// WithTx runs fn in a transaction of the database, see DB.WithTx
+func (log *EventLog) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return log.db.WithTx(ctx, fn)
+}
+
+// LoadEvents returns the events of the cart after afterVersion, oldest first
+func (log *EventLog) LoadEvents(ctx context.Context, shoppingCartID, afterVersion int64) ([]eventsourced.Event, error) {
+	client, release := log.db.conn(ctx)
+	defer release()
+
+	var events []eventsourced.Event
+	err := client.
+		Where("shoppingcart_id = ? AND version > ?", shoppingCartID, afterVersion).
+		Order("version").
+		Find(&events).Error
+
+	return events, err
+}
+
+// SaveSnapshot stores a snapshot of the state of the cart
+func (log *EventLog) SaveSnapshot(ctx context.Context, snapshot eventsourced.Snapshot) error {
+	client, release := log.db.conn(ctx)
+	defer release()
+
+	return client.Create(&snapshot).Error
+}
+
+// LoadSnapshot returns the latest snapshot of the cart created at or before at,
+// or the latest snapshot if at is zero
+func (log *EventLog) LoadSnapshot(ctx context.Context, shoppingCartID int64, at time.Time) (eventsourced.Snapshot, error) {
+	client, release := log.db.conn(ctx)
+	defer release()
+
+	query := client.Where("shoppingcart_id = ?", shoppingCartID)
+	if !at.IsZero() {
+		query = query.Where("created_at <= ?", at)
+	}
+
+	var snapshot eventsourced.Snapshot
+	err := query.Order("version DESC").First(&snapshot).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return eventsourced.Snapshot{}, nil
+	}
+
+	return snapshot, err
+}
@@ -0,0 +1,85 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// historyColumns are the columns written by AddHistoryEntries
+var historyColumns = []string{
+	"shoppingcart_id", "action", "actor_id", "item_id", "product_id", "variant_id",
+	"quantity_before", "quantity_after", "request_id", "created_at",
+}
+
+// historyBatchSize is the maximum number of entries inserted by a single
+// statement, which keeps the placeholders within the limits of SQLite
+const historyBatchSize = 90
+
+// AddHistoryEntries appends entries to the history of shopping carts with
+// multi-row inserts, within a single transaction
+func (db *DB) AddHistoryEntries(ctx context.Context, entries ...shoppingcart.HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		client, release := db.conn(ctx)
+		defer release()
+
+		columns := make([]string, len(historyColumns))
+		for i, column := range historyColumns {
+			columns[i] = client.Dialect().Quote(column)
+		}
+		row := "(?" + strings.Repeat(", ?", len(historyColumns)-1) + ")"
+
+		now := time.Now()
+		for len(entries) > 0 {
+			batch := entries
+			if len(batch) > historyBatchSize {
+				batch = batch[:historyBatchSize]
+			}
+			entries = entries[len(batch):]
+
+			rows := make([]string, 0, len(batch))
+			args := make([]interface{}, 0, len(batch)*len(historyColumns))
+			for _, entry := range batch {
+				rows = append(rows, row)
+				args = append(args, entry.ShoppingCartID, entry.Action, entry.ActorID, entry.ItemID, entry.ProductID, entry.VariantID,
+					entry.QuantityBefore, entry.QuantityAfter, entry.RequestID, now)
+			}
+
+			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+				client.Dialect().Quote(shoppingcart.HistoryEntry{}.TableName()), strings.Join(columns, ", "), strings.Join(rows, ", "))
+			if err := client.Exec(query, args...).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListHistory retrieves a page of the history of the shopping cart, newest entries first
+func (db *DB) ListHistory(ctx context.Context, shoppingCartID int64, limit, offset int) ([]shoppingcart.HistoryEntry, error) {
+	client, release := db.conn(ctx)
+	defer release()
+
+	var cart shoppingcart.ShoppingCart
+	if err := client.Select("id").Where("id = ?", shoppingCartID).First(&cart).Error; err != nil {
+		return nil, db.mapError(err, shoppingcart.ErrCartNotFound)
+	}
+
+	var entries []shoppingcart.HistoryEntry
+	err := client.
+		Where("shoppingcart_id = ?", shoppingCartID).
+		Order("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+
+	return entries, err
+}
@@ -0,0 +1,62 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// AddMember adds a member to the shopping cart
+func (db *DB) AddMember(ctx context.Context, member *shoppingcart.CartMember) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	member.CreatedAt = time.Now()
+	member.UpdatedAt = member.CreatedAt
+
+	err := client.Create(member).Error
+	if err != nil && db.dialect.IsUniqueViolation(err) {
+		return shoppingcart.ErrMemberAlreadyExists
+	}
+
+	return db.mapError(err, shoppingcart.ErrCartNotFound)
+}
+
+// GetMember retrieves the membership of the user in the shopping cart
+// Within WithTx the membership is locked until the transaction ends.
+func (db *DB) GetMember(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.CartMember, error) {
+	client, release := db.conn(ctx)
+	defer release()
+
+	var member shoppingcart.CartMember
+	err := db.forUpdate(ctx, client).
+		Where("shoppingcart_id = ? AND user_id = ?", shoppingCartID, userID).
+		First(&member).Error
+
+	if err != nil {
+		return shoppingcart.CartMember{}, db.mapError(err, shoppingcart.ErrMemberNotFound)
+	}
+
+	return member, nil
+}
+
+// UpdateMember updates the membership
+func (db *DB) UpdateMember(ctx context.Context, member *shoppingcart.CartMember) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	member.UpdatedAt = time.Now()
+
+	return client.Save(member).Error
+}
+
+// RemoveMember removes the user from the members of the shopping cart
+func (db *DB) RemoveMember(ctx context.Context, shoppingCartID, userID int64) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	return client.
+		Where("shoppingcart_id = ? AND user_id = ?", shoppingCartID, userID).
+		Delete(shoppingcart.CartMember{}).Error
+}
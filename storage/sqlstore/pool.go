@@ -0,0 +1,113 @@
+package sqlstore
+
+import (
+	"time"
+
+	"go.opencensus.io/metric"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/metric/metricproducer"
+)
+
+// PoolConfig defines the connection pool of the database. Zero values keep
+// the defaults of database/sql.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// SetPool configures the connection pool
+func (db *DB) SetPool(config PoolConfig) {
+	pool := db.client.DB()
+
+	if config.MaxOpenConns > 0 {
+		pool.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		pool.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		pool.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+	if config.ConnMaxIdleTime > 0 {
+		pool.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	}
+}
+
+// RegisterPoolMetrics exports the statistics of the connection pool along
+// with the other metrics of the service. It is meant to be called once, for
+// the database of the service.
+func (db *DB) RegisterPoolMetrics() error {
+	registry := metric.NewRegistry()
+	pool := db.client.DB()
+
+	gauges := []struct {
+		name        string
+		description string
+		value       func() int64
+	}{
+		{"database_connections_max_open", "Maximum number of open connections to the database", func() int64 {
+			return int64(pool.Stats().MaxOpenConnections)
+		}},
+		{"database_connections_open", "Number of open connections to the database", func() int64 {
+			return int64(pool.Stats().OpenConnections)
+		}},
+		{"database_connections_in_use", "Number of connections to the database in use", func() int64 {
+			return int64(pool.Stats().InUse)
+		}},
+		{"database_connections_idle", "Number of idle connections to the database", func() int64 {
+			return int64(pool.Stats().Idle)
+		}},
+	}
+	for _, g := range gauges {
+		gauge, err := registry.AddInt64DerivedGauge(g.name, metric.WithDescription(g.description), metric.WithUnit(metricdata.UnitDimensionless))
+		if err != nil {
+			return err
+		}
+		if err := gauge.UpsertEntry(g.value); err != nil {
+			return err
+		}
+	}
+
+	counters := []struct {
+		name        string
+		description string
+		value       func() int64
+	}{
+		{"database_connections_waited", "Number of times a connection to the database was waited for", func() int64 {
+			return pool.Stats().WaitCount
+		}},
+		{"database_connections_closed_max_idle", "Number of connections closed as the idle pool was full", func() int64 {
+			return pool.Stats().MaxIdleClosed
+		}},
+		{"database_connections_closed_max_idle_time", "Number of connections closed as they were idle for too long", func() int64 {
+			return pool.Stats().MaxIdleTimeClosed
+		}},
+		{"database_connections_closed_max_lifetime", "Number of connections closed as they were open for too long", func() int64 {
+			return pool.Stats().MaxLifetimeClosed
+		}},
+	}
+	for _, c := range counters {
+		counter, err := registry.AddInt64DerivedCumulative(c.name, metric.WithDescription(c.description), metric.WithUnit(metricdata.UnitDimensionless))
+		if err != nil {
+			return err
+		}
+		if err := counter.UpsertEntry(c.value); err != nil {
+			return err
+		}
+	}
+
+	waited, err := registry.AddFloat64DerivedCumulative("database_connections_wait_seconds",
+		metric.WithDescription("Total time waited for connections to the database"), metric.WithUnit(metricdata.Unit("s")))
+	if err != nil {
+		return err
+	}
+	if err := waited.UpsertEntry(func() float64 { return pool.Stats().WaitDuration.Seconds() }); err != nil {
+		return err
+	}
+
+	metricproducer.GlobalManager().AddProducer(registry)
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// Create creates shopping cart in the storate
+func (db *DB) Create(ctx context.Context, cart *shoppingcart.ShoppingCart) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	cart.CreatedAt = time.Now()
+	cart.UpdatedAt = cart.CreatedAt
+
+	return client.Create(cart).Error
+}
+
+// Get retrieves shopping cart from the storage along with items and members
+// The cart is found for its owner and for members who accepted the invitation.
+// Within WithTx the cart is locked until the transaction ends.
+func (db *DB) Get(ctx context.Context, ID, userID int64) (shoppingcart.ShoppingCart, error) {
+	client, release := db.conn(ctx)
+	defer release()
+
+	var cart shoppingcart.ShoppingCart
+	err := db.forUpdate(ctx, client).
+		Preload("Items").
+		Preload("Members").
+		Where("shoppingcart.id = ? AND (shoppingcart.user_id = ? OR shoppingcart.id IN (?))", ID, userID,
+			client.
+				Table("shoppingcart_member").
+				Select("shoppingcart_id").
+				Where("user_id = ? AND accepted_at IS NOT NULL", userID).
+				QueryExpr(),
+		).
+		First(&cart).Error
+
+	if err != nil {
+		return shoppingcart.ShoppingCart{}, db.mapError(err, shoppingcart.ErrCartNotFound)
+	}
+
+	splitSavedItems(&cart)
+
+	return cart, nil
+}
+
+// splitSavedItems moves the items saved for later from Items to SavedItems,
+// as both lists are stored in the same table
+func splitSavedItems(cart *shoppingcart.ShoppingCart) {
+	items := cart.Items[:0]
+	for _, item := range cart.Items {
+		if item.Saved() {
+			cart.SavedItems = append(cart.SavedItems, item)
+			continue
+		}
+		items = append(items, item)
+	}
+	cart.Items = items
+}
+
+// Empty removes active items associated with shopping cart
+func (db *DB) Empty(ctx context.Context, shoppingCartID int64) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	return client.
+		Where("shoppingcart_id = ? AND list = ?", shoppingCartID, shoppingcart.ListCart).
+		Delete(shoppingcart.ShoppingCartItem{}).Error
+}
+
+// AddProduct adds product to the shopping cart
+func (db *DB) AddProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	cartItem.CreatedAt = time.Now()
+	cartItem.UpdatedAt = cartItem.CreatedAt
+	if cartItem.List == "" {
+		cartItem.List = shoppingcart.ListCart
+	}
+
+	return db.mapError(client.Create(cartItem).Error, shoppingcart.ErrCartNotFound)
+}
+
+// UpdateProduct updates product in the shopping cart
+func (db *DB) UpdateProduct(ctx context.Context, cartItem *shoppingcart.ShoppingCartItem) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	cartItem.UpdatedAt = time.Now()
+
+	return client.Save(cartItem).Error
+}
+
+// RemoveProduct removes all active lines of the product from the shopping cart
+func (db *DB) RemoveProduct(ctx context.Context, shoppingCartID, productID int64) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	return client.
+		Where("shoppingcart_id = ? AND product_id = ? AND list = ?", shoppingCartID, productID, shoppingcart.ListCart).
+		Delete(shoppingcart.ShoppingCartItem{}).Error
+}
+
+// RemoveItem removes a single line from the shopping cart, active or saved for later
+func (db *DB) RemoveItem(ctx context.Context, shoppingCartID, itemID int64) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	return client.
+		Where("shoppingcart_id = ? AND id = ?", shoppingCartID, itemID).
+		Delete(shoppingcart.ShoppingCartItem{}).Error
+}
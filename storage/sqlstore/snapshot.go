@@ -0,0 +1,50 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// CreateSnapshot creates a snapshot along with its items
+func (db *DB) CreateSnapshot(ctx context.Context, snapshot *shoppingcart.Snapshot) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	snapshot.CreatedAt = time.Now()
+
+	return db.mapError(client.Create(snapshot).Error, shoppingcart.ErrCartNotFound)
+}
+
+// GetSnapshot retrieves a snapshot of the shopping cart along with items
+func (db *DB) GetSnapshot(ctx context.Context, shoppingCartID, snapshotID int64) (shoppingcart.Snapshot, error) {
+	client, release := db.conn(ctx)
+	defer release()
+
+	var snapshot shoppingcart.Snapshot
+	err := client.
+		Preload("Items").
+		Where("id = ? AND shoppingcart_id = ?", snapshotID, shoppingCartID).
+		First(&snapshot).Error
+
+	if err != nil {
+		return shoppingcart.Snapshot{}, db.mapError(err, shoppingcart.ErrSnapshotNotFound)
+	}
+
+	return snapshot, nil
+}
+
+// ListSnapshots retrieves the snapshots of the shopping cart, newest first, without items
+func (db *DB) ListSnapshots(ctx context.Context, shoppingCartID int64) ([]shoppingcart.Snapshot, error) {
+	client, release := db.conn(ctx)
+	defer release()
+
+	var snapshots []shoppingcart.Snapshot
+	err := client.
+		Where("shoppingcart_id = ?", shoppingCartID).
+		Order("id DESC").
+		Find(&snapshots).Error
+
+	return snapshots, err
+}
@@ -0,0 +1,180 @@
+// Package sqlstore keeps shopping carts, wishlists and their history in a SQL
+// database through gorm. The queries are shared by all supported databases,
+// which only differ in how the driver reports constraint violations.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// Dialect tells apart the errors of a database driver
+type Dialect interface {
+	// IsUniqueViolation reports whether err is caused by a duplicate key
+	IsUniqueViolation(err error) bool
+	// IsForeignKeyViolation reports whether err is caused by a reference to a missing row
+	IsForeignKeyViolation(err error) bool
+	// RowLock returns the clause which locks the selected rows until the
+	// transaction ends, empty if transactions lock the whole database
+	RowLock() string
+}
+
+type DB struct {
+	client       *gorm.DB
+	dialect      Dialect
+	queryTimeout time.Duration
+	sessions     sync.Pool
+}
+
+// txKey is the context key of the transaction of WithTx on the pool
+type txKey struct {
+	pool *sql.DB
+}
+
+// New creates the storage on top of an open connection
+func New(client *gorm.DB, dialect Dialect) *DB {
+	db := &DB{client: client, dialect: dialect}
+	db.sessions.New = db.newSession
+
+	return db
+}
+
+// Close closes the connection to database
+func (db *DB) Close() {
+	if db.client != nil {
+		db.client.Close()
+	}
+}
+
+// SetQueryTimeout bounds the queries of every call to timeout, zero means no
+// limit. Calls which time out fail with shoppingcart.ErrStorageTimeout.
+func (db *DB) SetQueryTimeout(timeout time.Duration) {
+	db.queryTimeout = timeout
+}
+
+// session holds the clients of a call, which run their queries through the
+// same conn: one on the connection pool and one within the transaction of
+// WithTx. gorm can't change the connection of a client without opening a new
+// one, so sessions are opened once, reused and bound to one call at a time.
+type session struct {
+	conn *conn
+	pool *gorm.DB
+	tx   *gorm.DB
+}
+
+func (db *DB) newSession() interface{} {
+	dialect := db.client.Dialect().GetName()
+	c := &conn{system: dialect}
+
+	// Open only fails for sources of unknown types
+	pool, _ := gorm.Open(dialect, poolConn{conn: c, pool: db.client.DB()})
+	tx, _ := gorm.Open(dialect, c)
+
+	return &session{conn: c, pool: pool, tx: tx}
+}
+
+// conn returns the client running the queries of a call within ctx, and
+// within the transaction of WithTx carried by ctx. The queries of the call are bounded by
+// the query timeout together, release must be called once they are done.
+func (db *DB) conn(ctx context.Context) (client *gorm.DB, release func()) {
+	queryCtx, cancel := ctx, func() {}
+	if db.queryTimeout > 0 {
+		queryCtx, cancel = context.WithTimeout(ctx, db.queryTimeout)
+	}
+
+	s := db.sessions.Get().(*session)
+	s.conn.ctx, s.conn.parent = queryCtx, ctx
+	if tx := db.tx(ctx); tx != nil {
+		s.conn.db, client = tx, s.tx
+	} else {
+		s.conn.db, client = db.client.DB(), s.pool
+	}
+
+	return client, func() {
+		cancel()
+		s.conn.ctx, s.conn.parent, s.conn.db = nil, nil, nil
+		db.sessions.Put(s)
+	}
+}
+
+// WithTx runs fn in a transaction, which is carried by the context passed to
+// fn. Shopping carts, wishlists and memberships read within the transaction
+// are locked until it ends, so concurrent units of work on them run one after
+// the other. Other storages on the same connection, e.g. the event log, join
+// the transaction. It is rolled back if fn fails or ctx ends.
+func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if db.tx(ctx) != nil {
+		return fn(ctx)
+	}
+
+	pool := db.client.DB()
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{pool}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// tx returns the transaction of WithTx carried by ctx, nil outside of it
+func (db *DB) tx(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{db.client.DB()}).(*sql.Tx)
+	return tx
+}
+
+// forUpdate locks the rows selected by query until the transaction of WithTx
+// ends, if ctx carries one
+func (db *DB) forUpdate(ctx context.Context, query *gorm.DB) *gorm.DB {
+	if db.tx(ctx) == nil || db.dialect.RowLock() == "" {
+		return query
+	}
+
+	return query.Set("gorm:query_option", db.dialect.RowLock())
+}
+
+// Ping checks that the database is reachable
+func (db *DB) Ping(ctx context.Context) error {
+	return db.client.DB().PingContext(ctx)
+}
+
+// DB returns the underlying connection pool, e.g. to migrate the schema
+func (db *DB) DB() *sql.DB {
+	return db.client.DB()
+}
+
+// mapError maps the errors of the driver to the errors of the shoppingcart
+// package: a missing row is reported as notFound, as is a reference to a
+// missing parent row. Other errors are returned as they are.
+func (db *DB) mapError(err, notFound error) error {
+	switch {
+	case err == nil:
+		return nil
+	case gorm.IsRecordNotFoundError(err), db.dialect.IsForeignKeyViolation(err):
+		return notFound
+	}
+
+	return err
+}
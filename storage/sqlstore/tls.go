@@ -0,0 +1,83 @@
+package sqlstore
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// TLS modes of the connections to the database
+const (
+	TLSDisable    = "disable"
+	TLSRequire    = "require"
+	TLSVerifyFull = "verify-full"
+)
+
+// ErrInvalidTLSConfig is returned when the TLS configuration can't be used
+var ErrInvalidTLSConfig = errors.New("invalid TLS configuration")
+
+// TLSConfig defines how the connections to the database are encrypted
+type TLSConfig struct {
+	// Mode is TLSDisable if empty, TLSRequire to encrypt without verifying
+	// the server, or TLSVerifyFull to verify its certificate and host name
+	Mode string
+
+	// CAFile holds the certificates of the CAs verifying the server, the
+	// roots of the system are used if empty
+	CAFile string
+
+	// CertFile and KeyFile hold the client certificate, which is optional
+	CertFile string
+	KeyFile  string
+}
+
+// Validate checks the mode of the configuration
+func (config TLSConfig) Validate() error {
+	switch config.Mode {
+	case "", TLSDisable, TLSRequire, TLSVerifyFull:
+		return nil
+	}
+
+	return fmt.Errorf("%w: unknown mode %q", ErrInvalidTLSConfig, config.Mode)
+}
+
+// Load returns the configuration of connections to serverName, nil if TLS is
+// disabled
+func (config TLSConfig) Load(serverName string) (*tls.Config, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.Mode == "" || config.Mode == TLSDisable {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: config.Mode == TLSRequire,
+	}
+
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTLSConfig, err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: no certificates in %s", ErrInvalidTLSConfig, config.CAFile)
+		}
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTLSConfig, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
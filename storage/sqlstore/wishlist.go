@@ -0,0 +1,109 @@
+package sqlstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// CreateWishlist creates wishlist in the storage
+func (db *DB) CreateWishlist(ctx context.Context, wishlist *shoppingcart.Wishlist) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	wishlist.CreatedAt = time.Now()
+	wishlist.UpdatedAt = wishlist.CreatedAt
+
+	return client.Create(wishlist).Error
+}
+
+// GetWishlist retrieves wishlist of the user from the storage along with items
+// Within WithTx the wishlist is locked until the transaction ends.
+func (db *DB) GetWishlist(ctx context.Context, wishlistID, userID int64) (shoppingcart.Wishlist, error) {
+	client, release := db.conn(ctx)
+	defer release()
+
+	var wishlist shoppingcart.Wishlist
+	err := db.forUpdate(ctx, client).
+		Preload("Items").
+		Where("wishlist.id = ? AND user_id = ?", wishlistID, userID).
+		First(&wishlist).Error
+
+	if err != nil {
+		return shoppingcart.Wishlist{}, db.mapError(err, shoppingcart.ErrWishlistNotFound)
+	}
+
+	return wishlist, nil
+}
+
+// ListWishlists retrieves all wishlists of the user without items
+func (db *DB) ListWishlists(ctx context.Context, userID int64) ([]shoppingcart.Wishlist, error) {
+	client, release := db.conn(ctx)
+	defer release()
+
+	wishlists := []shoppingcart.Wishlist{}
+	err := client.
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&wishlists).Error
+
+	return wishlists, err
+}
+
+// UpdateWishlist updates the name of the wishlist
+func (db *DB) UpdateWishlist(ctx context.Context, wishlist *shoppingcart.Wishlist) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	wishlist.UpdatedAt = time.Now()
+
+	return client.
+		Model(wishlist).
+		Updates(map[string]interface{}{"name": wishlist.Name, "updated_at": wishlist.UpdatedAt}).Error
+}
+
+// DeleteWishlist removes the wishlist along with items in a transaction
+func (db *DB) DeleteWishlist(ctx context.Context, wishlistID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		client, release := db.conn(ctx)
+		defer release()
+
+		if err := client.Where("wishlist_id = ?", wishlistID).Delete(shoppingcart.WishlistItem{}).Error; err != nil {
+			return err
+		}
+
+		return client.Where("id = ?", wishlistID).Delete(shoppingcart.Wishlist{}).Error
+	})
+}
+
+// AddWishlistItem adds product to the wishlist
+func (db *DB) AddWishlistItem(ctx context.Context, wishlistItem *shoppingcart.WishlistItem) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	wishlistItem.CreatedAt = time.Now()
+	wishlistItem.UpdatedAt = wishlistItem.CreatedAt
+
+	return db.mapError(client.Create(wishlistItem).Error, shoppingcart.ErrWishlistNotFound)
+}
+
+// UpdateWishlistItem updates product in the wishlist
+func (db *DB) UpdateWishlistItem(ctx context.Context, wishlistItem *shoppingcart.WishlistItem) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	wishlistItem.UpdatedAt = time.Now()
+
+	return client.Save(wishlistItem).Error
+}
+
+// RemoveWishlistItem removes a single line from the wishlist
+func (db *DB) RemoveWishlistItem(ctx context.Context, wishlistID, itemID int64) error {
+	client, release := db.conn(ctx)
+	defer release()
+
+	return client.
+		Where("wishlist_id = ? AND id = ?", wishlistID, itemID).
+		Delete(shoppingcart.WishlistItem{}).Error
+}
@@ -6,7 +6,19 @@ import (
 	"github.com/bugimetal/shoppingcart"
 )
 
+// Transactional describes a storage running units of work
+type Transactional interface {
+	// WithTx runs fn as a unit of work: the calls made with the context
+	// passed to fn are part of it. The carts, wishlists and memberships read
+	// within fn can't be changed by others until fn returns, and the writes
+	// of fn are discarded if it returns an error. Storages sharing a database
+	// share the unit of work, and nested calls join the outer unit of work.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // ShoppingCart describes an interface to store shopping carts and manipulate with products inside the cart
+// Empty and RemoveProduct only apply to the active items, items saved for later are kept.
+// Items are moved between the lists by updating ShoppingCartItem.List.
 type ShoppingCart interface {
 	Create(context.Context, *shoppingcart.ShoppingCart) error
 	Get(ctx context.Context, shoppingCartID int64, userID int64) (shoppingcart.ShoppingCart, error)
@@ -15,4 +27,53 @@ type ShoppingCart interface {
 	AddProduct(context.Context, *shoppingcart.ShoppingCartItem) error
 	UpdateProduct(context.Context, *shoppingcart.ShoppingCartItem) error
 	RemoveProduct(ctx context.Context, shoppingCartID, productID int64) error
+	RemoveItem(ctx context.Context, shoppingCartID, itemID int64) error
+
+	Transactional
+}
+
+// Wishlist describes an interface to store wishlists of users and the products inside them
+type Wishlist interface {
+	CreateWishlist(context.Context, *shoppingcart.Wishlist) error
+	GetWishlist(ctx context.Context, wishlistID, userID int64) (shoppingcart.Wishlist, error)
+	ListWishlists(ctx context.Context, userID int64) ([]shoppingcart.Wishlist, error)
+	UpdateWishlist(context.Context, *shoppingcart.Wishlist) error
+	DeleteWishlist(ctx context.Context, wishlistID int64) error
+
+	AddWishlistItem(context.Context, *shoppingcart.WishlistItem) error
+	UpdateWishlistItem(context.Context, *shoppingcart.WishlistItem) error
+	RemoveWishlistItem(ctx context.Context, wishlistID, itemID int64) error
+
+	Transactional
+}
+
+// CartMembers describes an interface to store the members of shared shopping carts
+// Get of ShoppingCart grants access to the owner and to accepted members.
+type CartMembers interface {
+	AddMember(context.Context, *shoppingcart.CartMember) error
+	GetMember(ctx context.Context, shoppingCartID, userID int64) (shoppingcart.CartMember, error)
+	UpdateMember(context.Context, *shoppingcart.CartMember) error
+	RemoveMember(ctx context.Context, shoppingCartID, userID int64) error
+
+	Transactional
+}
+
+// History describes an interface to store the audit trail of shopping carts
+// Entries are never changed once added, entries added together are stored
+// together. Within a unit of work of a storage sharing the database, entries
+// are added as part of it. ListHistory returns the newest entries first, or
+// ErrCartNotFound if the shopping cart doesn't exist.
+type History interface {
+	AddHistoryEntries(context.Context, ...shoppingcart.HistoryEntry) error
+	ListHistory(ctx context.Context, shoppingCartID int64, limit, offset int) ([]shoppingcart.HistoryEntry, error)
+}
+
+// Snapshots describes an interface to store snapshots of shopping carts
+// Snapshots are never changed once created. Within a unit of work of a
+// storage sharing the database, snapshots are created as part of it.
+// ListSnapshots returns the newest snapshots first and without items.
+type Snapshots interface {
+	CreateSnapshot(context.Context, *shoppingcart.Snapshot) error
+	GetSnapshot(ctx context.Context, shoppingCartID, snapshotID int64) (shoppingcart.Snapshot, error)
+	ListSnapshots(ctx context.Context, shoppingCartID int64) ([]shoppingcart.Snapshot, error)
 }
@@ -0,0 +1,51 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+)
+
+// Context tests that calls of the storage end along with their context and
+// report the error of the context
+func Context(t *testing.T, s Storage) {
+	cart := createCart(t, s)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{name: "canceled", ctx: canceled, want: context.Canceled},
+		{name: "deadline exceeded", ctx: expired, want: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Get(tt.ctx, cart.ID, cart.UserID)
+			expectError(t, err, tt.want)
+
+			err = s.AddProduct(tt.ctx, &shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 1})
+			expectError(t, err, tt.want)
+
+			_, err = s.ListHistory(tt.ctx, cart.ID, 10, 0)
+			expectError(t, err, tt.want)
+
+			err = s.WithTx(tt.ctx, func(ctx context.Context) error {
+				t.Fatal("Expected the unit of work not to run")
+				return nil
+			})
+			expectError(t, err, tt.want)
+		})
+	}
+
+	// Nothing was written
+	expectQuantities(t, getCart(t, s, cart.ID, cart.UserID).Items, map[int64]uint64{})
+}
@@ -0,0 +1,64 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// History tests the storage of the audit trail of shopping carts
+func History(t *testing.T, carts storage.ShoppingCart, history storage.History) {
+	ctx := context.Background()
+	cart := createCart(t, carts)
+
+	entries := []shoppingcart.HistoryEntry{
+		{ShoppingCartID: cart.ID, Action: shoppingcart.ActionCreate, ActorID: cart.UserID},
+		{ShoppingCartID: cart.ID, Action: shoppingcart.ActionAdd, ActorID: cart.UserID, ProductID: 1, QuantityAfter: 2},
+		{ShoppingCartID: cart.ID, Action: shoppingcart.ActionRemove, ActorID: cart.UserID, ProductID: 1, QuantityBefore: 2, RequestID: "request"},
+	}
+	if err := history.AddHistoryEntries(ctx, entries...); err != nil {
+		t.Fatalf("Unable to add history entries: %s", err)
+	}
+
+	page, err := history.ListHistory(ctx, cart.ID, 2, 0)
+	if err != nil {
+		t.Fatalf("Unable to list history: %s", err)
+	}
+	if len(page) != 2 || page[0].Action != shoppingcart.ActionRemove || page[1].Action != shoppingcart.ActionAdd {
+		t.Fatalf("Expected the newest entries first, but got %+v", page)
+	}
+	if page[0].QuantityBefore != 2 || page[0].RequestID != "request" {
+		t.Fatalf("Expected entry to keep quantities and request ID, but got %+v", page[0])
+	}
+
+	page, err = history.ListHistory(ctx, cart.ID, 2, 2)
+	if err != nil {
+		t.Fatalf("Unable to list history: %s", err)
+	}
+	if len(page) != 1 || page[0].Action != shoppingcart.ActionCreate {
+		t.Fatalf("Expected the oldest entry on the last page, but got %+v", page)
+	}
+
+	_, err = history.ListHistory(ctx, cart.ID+1<<40, 2, 0)
+	expectError(t, err, shoppingcart.ErrCartNotFound)
+
+	// Many entries, as recorded when emptying a large cart
+	cart = createCart(t, carts)
+	entries = make([]shoppingcart.HistoryEntry, 250)
+	for i := range entries {
+		entries[i] = shoppingcart.HistoryEntry{ShoppingCartID: cart.ID, Action: shoppingcart.ActionEmpty, ActorID: cart.UserID, ProductID: int64(i + 1), QuantityBefore: 1}
+	}
+	if err := history.AddHistoryEntries(ctx, entries...); err != nil {
+		t.Fatalf("Unable to add history entries: %s", err)
+	}
+
+	page, err = history.ListHistory(ctx, cart.ID, 300, 0)
+	if err != nil {
+		t.Fatalf("Unable to list history: %s", err)
+	}
+	if len(page) != len(entries) || page[0].ProductID != int64(len(entries)) || page[len(page)-1].ProductID != 1 {
+		t.Fatalf("Expected %d entries in order, but got %d", len(entries), len(page))
+	}
+}
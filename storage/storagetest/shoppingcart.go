@@ -0,0 +1,169 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// ShoppingCart tests the storage of shopping carts and their items
+func ShoppingCart(t *testing.T, carts storage.ShoppingCart) {
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		cart := createCart(t, carts)
+
+		_, err := carts.Get(ctx, cart.ID, newUserID())
+		expectError(t, err, shoppingcart.ErrCartNotFound)
+
+		_, err = carts.Get(ctx, cart.ID+1<<40, cart.UserID)
+		expectError(t, err, shoppingcart.ErrCartNotFound)
+	})
+
+	t.Run("items", func(t *testing.T) {
+		cart := createCart(t, carts)
+
+		addProduct(t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 2, AddedBy: cart.UserID})
+		addProduct(t, carts, shoppingcart.ShoppingCartItem{
+			ShoppingCartID: cart.ID,
+			ProductID:      2,
+			VariantID:      "tshirt-m",
+			Attributes:     shoppingcart.Attributes{"engraving": "For Anna"},
+			Quantity:       1,
+			AddedBy:        cart.UserID,
+		})
+
+		got := getCart(t, carts, cart.ID, cart.UserID)
+		if got.UserID != cart.UserID {
+			t.Fatalf("Expected owner %d, but got %d", cart.UserID, got.UserID)
+		}
+		expectQuantities(t, got.Items, map[int64]uint64{1: 2, 2: 1})
+
+		line, err := got.GetProduct(shoppingcart.ShoppingCartItem{
+			ProductID:  2,
+			VariantID:  "tshirt-m",
+			Attributes: shoppingcart.Attributes{"engraving": "For Anna"},
+		})
+		if err != nil {
+			t.Fatalf("Expected the line of variant and attributes, but got %v", got.Items)
+		}
+		if line.AddedBy != cart.UserID {
+			t.Fatalf("Expected line to be added by %d, but got %d", cart.UserID, line.AddedBy)
+		}
+
+		line.Quantity = 5
+		if err := carts.UpdateProduct(ctx, &line); err != nil {
+			t.Fatalf("Unable to update product: %s", err)
+		}
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{1: 2, 2: 5})
+
+		if err := carts.RemoveItem(ctx, cart.ID, line.ID); err != nil {
+			t.Fatalf("Unable to remove line: %s", err)
+		}
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{1: 2})
+	})
+
+	t.Run("saved items", func(t *testing.T) {
+		cart := createCart(t, carts)
+
+		addProduct(t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 1})
+		addProduct(t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 2, VariantID: "s", Quantity: 1})
+		saved := addProduct(t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 2, VariantID: "m", Quantity: 3})
+
+		saved.List = shoppingcart.ListSaved
+		if err := carts.UpdateProduct(ctx, &saved); err != nil {
+			t.Fatalf("Unable to save line for later: %s", err)
+		}
+
+		got := getCart(t, carts, cart.ID, cart.UserID)
+		expectQuantities(t, got.Items, map[int64]uint64{1: 1, 2: 1})
+		expectQuantities(t, got.SavedItems, map[int64]uint64{2: 3})
+
+		// Removing a product only removes its active lines
+		if err := carts.RemoveProduct(ctx, cart.ID, 2); err != nil {
+			t.Fatalf("Unable to remove product: %s", err)
+		}
+		got = getCart(t, carts, cart.ID, cart.UserID)
+		expectQuantities(t, got.Items, map[int64]uint64{1: 1})
+		expectQuantities(t, got.SavedItems, map[int64]uint64{2: 3})
+
+		// Emptying the cart keeps the saved lines
+		if err := carts.Empty(ctx, cart.ID); err != nil {
+			t.Fatalf("Unable to empty shopping cart: %s", err)
+		}
+		got = getCart(t, carts, cart.ID, cart.UserID)
+		expectQuantities(t, got.Items, map[int64]uint64{})
+		expectQuantities(t, got.SavedItems, map[int64]uint64{2: 3})
+
+		if err := carts.RemoveItem(ctx, cart.ID, saved.ID); err != nil {
+			t.Fatalf("Unable to remove saved line: %s", err)
+		}
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).SavedItems, map[int64]uint64{})
+	})
+}
+
+// CartMembers tests the storage of members of shared carts, which grant
+// access to the carts once they accept
+func CartMembers(t *testing.T, carts storage.ShoppingCart, members storage.CartMembers) {
+	ctx := context.Background()
+
+	t.Run("access", func(t *testing.T) {
+		cart := createCart(t, carts)
+		member := shoppingcart.CartMember{
+			ShoppingCartID: cart.ID,
+			UserID:         newUserID(),
+			Role:           shoppingcart.RoleEditor,
+			InvitedBy:      cart.UserID,
+		}
+
+		if err := members.AddMember(ctx, &member); err != nil {
+			t.Fatalf("Unable to add member: %s", err)
+		}
+
+		_, err := carts.Get(ctx, cart.ID, member.UserID)
+		expectError(t, err, shoppingcart.ErrCartNotFound)
+
+		got, err := members.GetMember(ctx, cart.ID, member.UserID)
+		if err != nil {
+			t.Fatalf("Unable to get member: %s", err)
+		}
+		if got.Role != shoppingcart.RoleEditor || got.InvitedBy != cart.UserID || got.Accepted() {
+			t.Fatalf("Expected pending editor invited by %d, but got %+v", cart.UserID, got)
+		}
+
+		now := got.CreatedAt
+		got.AcceptedAt = &now
+		if err := members.UpdateMember(ctx, &got); err != nil {
+			t.Fatalf("Unable to update member: %s", err)
+		}
+
+		shared := getCart(t, carts, cart.ID, member.UserID)
+		if shared.RoleOf(member.UserID) != shoppingcart.RoleEditor {
+			t.Fatalf("Expected member to be an editor, but got %q", shared.RoleOf(member.UserID))
+		}
+
+		if err := members.RemoveMember(ctx, cart.ID, member.UserID); err != nil {
+			t.Fatalf("Unable to remove member: %s", err)
+		}
+
+		_, err = carts.Get(ctx, cart.ID, member.UserID)
+		expectError(t, err, shoppingcart.ErrCartNotFound)
+
+		_, err = members.GetMember(ctx, cart.ID, member.UserID)
+		expectError(t, err, shoppingcart.ErrMemberNotFound)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		cart := createCart(t, carts)
+		member := shoppingcart.CartMember{ShoppingCartID: cart.ID, UserID: newUserID(), Role: shoppingcart.RoleViewer}
+
+		if err := members.AddMember(ctx, &member); err != nil {
+			t.Fatalf("Unable to add member: %s", err)
+		}
+
+		duplicate := member
+		expectError(t, members.AddMember(ctx, &duplicate), shoppingcart.ErrMemberAlreadyExists)
+	})
+}
@@ -0,0 +1,46 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// Snapshots tests the storage of snapshots of shopping carts
+func Snapshots(t *testing.T, carts storage.ShoppingCart, snapshots storage.Snapshots) {
+	ctx := context.Background()
+	cart := createCart(t, carts)
+
+	cart.Items = []shoppingcart.ShoppingCartItem{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, VariantID: "m", Attributes: shoppingcart.Attributes{"engraving": "For Anna"}, Quantity: 1},
+	}
+	snapshot := shoppingcart.NewSnapshot(cart, shoppingcart.SnapshotManual, cart.UserID)
+	if err := snapshots.CreateSnapshot(ctx, &snapshot); err != nil {
+		t.Fatalf("Unable to create snapshot: %s", err)
+	}
+
+	got, err := snapshots.GetSnapshot(ctx, cart.ID, snapshot.ID)
+	if err != nil {
+		t.Fatalf("Unable to get snapshot: %s", err)
+	}
+	if got.Reason != shoppingcart.SnapshotManual || got.CreatedBy != cart.UserID || len(got.Items) != 2 {
+		t.Fatalf("Expected manual snapshot with 2 items, but got %+v", got)
+	}
+
+	_, err = snapshots.GetSnapshot(ctx, cart.ID+1<<40, snapshot.ID)
+	expectError(t, err, shoppingcart.ErrSnapshotNotFound)
+
+	list, err := snapshots.ListSnapshots(ctx, cart.ID)
+	if err != nil {
+		t.Fatalf("Unable to list snapshots: %s", err)
+	}
+	if len(list) != 1 || list[0].ID != snapshot.ID || len(list[0].Items) != 0 {
+		t.Fatalf("Expected snapshot %d without items, but got %+v", snapshot.ID, list)
+	}
+
+	missing := shoppingcart.Snapshot{ShoppingCartID: cart.ID + 1<<40, Reason: shoppingcart.SnapshotManual}
+	expectError(t, snapshots.CreateSnapshot(ctx, &missing), shoppingcart.ErrCartNotFound)
+}
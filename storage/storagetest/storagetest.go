@@ -0,0 +1,133 @@
+// Package storagetest provides behavioral tests shared by the storage
+// implementations, so every database behaves the same towards the service.
+// The tests run against a migrated database and create their own carts and
+// wishlists for unique users, so the database doesn't need to be empty.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// Storage is implemented by stores keeping all data of the service
+type Storage interface {
+	storage.ShoppingCart
+	storage.CartMembers
+	storage.Wishlist
+	storage.History
+	storage.Snapshots
+}
+
+// Run runs all behavioral tests against s
+func Run(t *testing.T, s Storage) {
+	t.Run("ShoppingCart", func(t *testing.T) { ShoppingCart(t, s) })
+	t.Run("Transactions", func(t *testing.T) { Transactions(t, s) })
+	t.Run("SharedTransactions", func(t *testing.T) { SharedTransactions(t, s, s) })
+	t.Run("CartMembers", func(t *testing.T) { CartMembers(t, s, s) })
+	t.Run("Wishlist", func(t *testing.T) { Wishlist(t, s) })
+	t.Run("History", func(t *testing.T) { History(t, s, s) })
+	t.Run("Snapshots", func(t *testing.T) { Snapshots(t, s, s) })
+	t.Run("Context", func(t *testing.T) { Context(t, s) })
+}
+
+var lastUserID = time.Now().UnixNano() / int64(time.Microsecond)
+
+// newUserID returns a user ID which isn't used by earlier runs
+func newUserID() int64 {
+	return atomic.AddInt64(&lastUserID, 1)
+}
+
+// createCart creates a cart of a new user
+func createCart(t *testing.T, carts storage.ShoppingCart) shoppingcart.ShoppingCart {
+	t.Helper()
+
+	cart := shoppingcart.ShoppingCart{UserID: newUserID()}
+	if err := carts.Create(context.Background(), &cart); err != nil {
+		t.Fatalf("Unable to create shopping cart: %s", err)
+	}
+	if cart.ID == 0 {
+		t.Fatal("Expected ID of the created shopping cart to be set")
+	}
+
+	return cart
+}
+
+// getCart retrieves the cart for its owner
+func getCart(t *testing.T, carts storage.ShoppingCart, ID, userID int64) shoppingcart.ShoppingCart {
+	t.Helper()
+
+	return getCartWithin(context.Background(), t, carts, ID, userID)
+}
+
+// getCartWithin retrieves the cart for its owner within the unit of work of ctx
+func getCartWithin(ctx context.Context, t *testing.T, carts storage.ShoppingCart, ID, userID int64) shoppingcart.ShoppingCart {
+	t.Helper()
+
+	cart, err := carts.Get(ctx, ID, userID)
+	if err != nil {
+		t.Fatalf("Unable to get shopping cart %d: %s", ID, err)
+	}
+
+	return cart
+}
+
+// addProduct adds a line to the cart
+func addProduct(t *testing.T, carts storage.ShoppingCart, item shoppingcart.ShoppingCartItem) shoppingcart.ShoppingCartItem {
+	t.Helper()
+
+	return addProductWithin(context.Background(), t, carts, item)
+}
+
+// addProductWithin adds a line to the cart within the unit of work of ctx
+func addProductWithin(ctx context.Context, t *testing.T, carts storage.ShoppingCart, item shoppingcart.ShoppingCartItem) shoppingcart.ShoppingCartItem {
+	t.Helper()
+
+	if err := carts.AddProduct(ctx, &item); err != nil {
+		t.Fatalf("Unable to add product %d: %s", item.ProductID, err)
+	}
+	if item.ID == 0 {
+		t.Fatal("Expected ID of the added line to be set")
+	}
+
+	return item
+}
+
+// expectError fails the test unless err is expected
+func expectError(t *testing.T, err, expected error) {
+	t.Helper()
+
+	if !errors.Is(err, expected) {
+		t.Fatalf("Expected error %v, but got %v", expected, err)
+	}
+}
+
+// quantities returns the quantities of items by product
+func quantities(items []shoppingcart.ShoppingCartItem) map[int64]uint64 {
+	result := map[int64]uint64{}
+	for _, item := range items {
+		result[item.ProductID] += item.Quantity
+	}
+
+	return result
+}
+
+// expectQuantities fails the test unless items hold the expected quantities by product
+func expectQuantities(t *testing.T, items []shoppingcart.ShoppingCartItem, expected map[int64]uint64) {
+	t.Helper()
+
+	actual := quantities(items)
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected quantities %v, but got %v", expected, actual)
+	}
+	for productID, quantity := range expected {
+		if actual[productID] != quantity {
+			t.Fatalf("Expected quantities %v, but got %v", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// errAbort fails units of work in the tests
+var errAbort = errors.New("abort")
+
+// Transactions tests the units of work of the storage of shopping carts
+func Transactions(t *testing.T, carts storage.ShoppingCart) {
+	ctx := context.Background()
+
+	t.Run("commit", func(t *testing.T) {
+		cart := createCart(t, carts)
+
+		err := carts.WithTx(ctx, func(ctx context.Context) error {
+			addProductWithin(ctx, t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 2})
+
+			// Writes are visible within the unit of work
+			expectQuantities(t, getCartWithin(ctx, t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{1: 2})
+
+			addProductWithin(ctx, t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 2, Quantity: 1})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unable to run unit of work: %s", err)
+		}
+
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{1: 2, 2: 1})
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		cart := createCart(t, carts)
+		addProduct(t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 2})
+
+		err := carts.WithTx(ctx, func(ctx context.Context) error {
+			if err := carts.Empty(ctx, cart.ID); err != nil {
+				return err
+			}
+			addProductWithin(ctx, t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 2, Quantity: 1})
+			return errAbort
+		})
+		expectError(t, err, errAbort)
+
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{1: 2})
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		cart := createCart(t, carts)
+
+		err := carts.WithTx(ctx, func(ctx context.Context) error {
+			err := carts.WithTx(ctx, func(ctx context.Context) error {
+				addProductWithin(ctx, t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 2})
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			// The nested unit of work is part of the outer one
+			return errAbort
+		})
+		expectError(t, err, errAbort)
+
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{})
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		cart := createCart(t, carts)
+		item := addProduct(t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 1})
+
+		// Every unit of work increments the quantity it read. Units of work
+		// may fail on conflicts, but no increment may be lost.
+		const workers = 10
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded uint64
+		)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := carts.WithTx(ctx, func(ctx context.Context) error {
+					cart, err := carts.Get(ctx, cart.ID, cart.UserID)
+					if err != nil {
+						return err
+					}
+
+					line, err := cart.GetItem(item.ID)
+					if err != nil {
+						return err
+					}
+
+					line.Quantity++
+					return carts.UpdateProduct(ctx, &line)
+				})
+				if err == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if succeeded == 0 {
+			t.Fatal("Expected some of the concurrent units of work to succeed")
+		}
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{1: 1 + succeeded})
+	})
+}
+
+// SharedTransactions tests that the storages sharing the database of the
+// carts take part in their units of work
+func SharedTransactions(t *testing.T, carts storage.ShoppingCart, s Storage) {
+	ctx := context.Background()
+
+	t.Run("rollback", func(t *testing.T) {
+		cart := createCart(t, carts)
+		wishlist := shoppingcart.Wishlist{UserID: cart.UserID, Name: "Birthday"}
+		if err := s.CreateWishlist(ctx, &wishlist); err != nil {
+			t.Fatalf("Unable to create wishlist: %s", err)
+		}
+		member := shoppingcart.CartMember{ShoppingCartID: cart.ID, UserID: newUserID(), Role: shoppingcart.RoleViewer, InvitedBy: cart.UserID}
+
+		err := carts.WithTx(ctx, func(ctx context.Context) error {
+			addProductWithin(ctx, t, carts, shoppingcart.ShoppingCartItem{ShoppingCartID: cart.ID, ProductID: 1, Quantity: 1})
+
+			if err := s.AddMember(ctx, &member); err != nil {
+				return err
+			}
+			if err := s.AddHistoryEntries(ctx, shoppingcart.HistoryEntry{ShoppingCartID: cart.ID, Action: shoppingcart.ActionAdd, ActorID: cart.UserID}); err != nil {
+				return err
+			}
+			if err := s.CreateSnapshot(ctx, &shoppingcart.Snapshot{ShoppingCartID: cart.ID, Reason: shoppingcart.SnapshotManual, CreatedBy: cart.UserID}); err != nil {
+				return err
+			}
+			if err := s.DeleteWishlist(ctx, wishlist.ID); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		expectError(t, err, errAbort)
+
+		expectQuantities(t, getCart(t, carts, cart.ID, cart.UserID).Items, map[int64]uint64{})
+
+		_, err = s.GetMember(ctx, cart.ID, member.UserID)
+		expectError(t, err, shoppingcart.ErrMemberNotFound)
+
+		if entries, err := s.ListHistory(ctx, cart.ID, 10, 0); err != nil || len(entries) != 0 {
+			t.Fatalf("Expected no history entries, but got %+v (%v)", entries, err)
+		}
+		if snapshots, err := s.ListSnapshots(ctx, cart.ID); err != nil || len(snapshots) != 0 {
+			t.Fatalf("Expected no snapshots, but got %+v (%v)", snapshots, err)
+		}
+		if _, err := s.GetWishlist(ctx, wishlist.ID, wishlist.UserID); err != nil {
+			t.Fatalf("Expected wishlist to be kept, but got %v", err)
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		cart := createCart(t, carts)
+		member := shoppingcart.CartMember{ShoppingCartID: cart.ID, UserID: newUserID(), Role: shoppingcart.RoleViewer, InvitedBy: cart.UserID}
+
+		err := carts.WithTx(ctx, func(ctx context.Context) error {
+			// Memberships read within the unit of work are locked as well
+			getCartWithin(ctx, t, carts, cart.ID, cart.UserID)
+
+			if err := s.AddMember(ctx, &member); err != nil {
+				return err
+			}
+			if _, err := s.GetMember(ctx, cart.ID, member.UserID); err != nil {
+				return err
+			}
+			return s.AddHistoryEntries(ctx, shoppingcart.HistoryEntry{ShoppingCartID: cart.ID, Action: shoppingcart.ActionAdd, ActorID: cart.UserID})
+		})
+		if err != nil {
+			t.Fatalf("Unable to run unit of work: %s", err)
+		}
+
+		if _, err := s.GetMember(ctx, cart.ID, member.UserID); err != nil {
+			t.Fatalf("Unable to get member: %s", err)
+		}
+		if entries, err := s.ListHistory(ctx, cart.ID, 10, 0); err != nil || len(entries) != 1 {
+			t.Fatalf("Expected %d history entry, but got %+v (%v)", 1, entries, err)
+		}
+	})
+}
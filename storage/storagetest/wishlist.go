@@ -0,0 +1,73 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bugimetal/shoppingcart"
+	"github.com/bugimetal/shoppingcart/storage"
+)
+
+// Wishlist tests the storage of wishlists and their items
+func Wishlist(t *testing.T, wishlists storage.Wishlist) {
+	ctx := context.Background()
+	userID := newUserID()
+
+	wishlist := shoppingcart.Wishlist{UserID: userID, Name: "Birthday"}
+	if err := wishlists.CreateWishlist(ctx, &wishlist); err != nil {
+		t.Fatalf("Unable to create wishlist: %s", err)
+	}
+
+	item := shoppingcart.WishlistItem{
+		WishlistID: wishlist.ID,
+		ProductID:  1,
+		Attributes: shoppingcart.Attributes{"gift_message": "Happy birthday"},
+		Quantity:   1,
+	}
+	if err := wishlists.AddWishlistItem(ctx, &item); err != nil {
+		t.Fatalf("Unable to add wishlist item: %s", err)
+	}
+
+	item.Quantity = 2
+	if err := wishlists.UpdateWishlistItem(ctx, &item); err != nil {
+		t.Fatalf("Unable to update wishlist item: %s", err)
+	}
+
+	wishlist.Name = "Christmas"
+	if err := wishlists.UpdateWishlist(ctx, &wishlist); err != nil {
+		t.Fatalf("Unable to rename wishlist: %s", err)
+	}
+
+	got, err := wishlists.GetWishlist(ctx, wishlist.ID, userID)
+	if err != nil {
+		t.Fatalf("Unable to get wishlist: %s", err)
+	}
+	if got.Name != "Christmas" || len(got.Items) != 1 || got.Items[0].Quantity != 2 ||
+		got.Items[0].Attributes["gift_message"] != "Happy birthday" {
+		t.Fatalf("Expected renamed wishlist with the updated item, but got %+v", got)
+	}
+
+	_, err = wishlists.GetWishlist(ctx, wishlist.ID, newUserID())
+	expectError(t, err, shoppingcart.ErrWishlistNotFound)
+
+	list, err := wishlists.ListWishlists(ctx, userID)
+	if err != nil {
+		t.Fatalf("Unable to list wishlists: %s", err)
+	}
+	if len(list) != 1 || list[0].ID != wishlist.ID {
+		t.Fatalf("Expected wishlist %d, but got %+v", wishlist.ID, list)
+	}
+
+	if err := wishlists.RemoveWishlistItem(ctx, wishlist.ID, item.ID); err != nil {
+		t.Fatalf("Unable to remove wishlist item: %s", err)
+	}
+	if err := wishlists.DeleteWishlist(ctx, wishlist.ID); err != nil {
+		t.Fatalf("Unable to delete wishlist: %s", err)
+	}
+
+	_, err = wishlists.GetWishlist(ctx, wishlist.ID, userID)
+	expectError(t, err, shoppingcart.ErrWishlistNotFound)
+
+	missing := shoppingcart.WishlistItem{WishlistID: wishlist.ID, ProductID: 1, Quantity: 1}
+	expectError(t, wishlists.AddWishlistItem(ctx, &missing), shoppingcart.ErrWishlistNotFound)
+}
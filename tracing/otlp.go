@@ -0,0 +1,258 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// Batching of exported spans
+const (
+	defaultFlushInterval = 5 * time.Second
+	maxBatchSize         = 512
+	maxQueueSize         = 4096
+)
+
+// Span kinds and status codes of OTLP
+const (
+	otlpKindInternal = 1
+	otlpKindServer   = 2
+	otlpKindClient   = 3
+
+	otlpStatusError = 2
+)
+
+// The types below are the OTLP/HTTP JSON encoding of an export request
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue holds one of the values, integers are encoded as strings
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+// exporter queues the spans ended by the service and sends them in batches
+// to sink, every flush interval or once a batch is full. Spans are dropped
+// while the queue is full.
+type exporter struct {
+	resource otlpResource
+	sink     func(ctx context.Context, batch []byte) error
+
+	mu    sync.Mutex
+	spans []otlpSpan
+
+	flushMu sync.Mutex
+	full    chan struct{}
+	done    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// newExporter returns a new exporter of the spans of service
+func newExporter(service string, sink func(ctx context.Context, batch []byte) error, interval time.Duration) *exporter {
+	e := &exporter{
+		resource: otlpResource{Attributes: attributes(map[string]interface{}{"service.name": service})},
+		sink:     sink,
+		full:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+
+	e.stopped.Add(1)
+	go func() {
+		defer e.stopped.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-e.full:
+			case <-e.done:
+				return
+			}
+			e.Flush()
+		}
+	}()
+
+	return e
+}
+
+// ExportSpan queues a span which ended
+func (e *exporter) ExportSpan(data *trace.SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.spans) >= maxQueueSize {
+		return
+	}
+	e.spans = append(e.spans, span(data))
+
+	if len(e.spans) >= maxBatchSize {
+		select {
+		case e.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush sends the queued spans
+func (e *exporter) Flush() {
+	e.flushMu.Lock()
+	defer e.flushMu.Unlock()
+
+	for {
+		e.mu.Lock()
+		n := len(e.spans)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		spans := e.spans[:n:n]
+		e.spans = e.spans[n:]
+		e.mu.Unlock()
+
+		if len(spans) == 0 {
+			return
+		}
+
+		if err := e.send(spans); err != nil {
+			logrus.Errorf("Unable to export %d spans: %s", len(spans), err)
+		}
+	}
+}
+
+// Close stops the exporter and sends the queued spans
+func (e *exporter) Close() {
+	close(e.done)
+	e.stopped.Wait()
+	e.Flush()
+}
+
+// send sends a batch of spans to the sink
+func (e *exporter) send(spans []otlpSpan) error {
+	batch, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: e.resource,
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "go.opencensus.io"},
+			Spans: spans,
+		}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return e.sink(ctx, batch)
+}
+
+// span converts an OpenCensus span to OTLP
+func span(data *trace.SpanData) otlpSpan {
+	s := otlpSpan{
+		TraceID:           hex.EncodeToString(data.TraceID[:]),
+		SpanID:            hex.EncodeToString(data.SpanID[:]),
+		Name:              data.Name,
+		Kind:              otlpKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(data.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(data.EndTime.UnixNano(), 10),
+		Attributes:        attributes(data.Attributes),
+	}
+
+	if data.ParentSpanID != (trace.SpanID{}) {
+		s.ParentSpanID = hex.EncodeToString(data.ParentSpanID[:])
+	}
+
+	switch data.SpanKind {
+	case trace.SpanKindServer:
+		s.Kind = otlpKindServer
+	case trace.SpanKindClient:
+		s.Kind = otlpKindClient
+	}
+
+	if data.Code != trace.StatusCodeOK {
+		s.Status = otlpStatus{Code: otlpStatusError, Message: data.Message}
+	}
+
+	return s
+}
+
+// attributes converts the attributes of a span to OTLP, sorted by key
+func attributes(attrs map[string]interface{}) []otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, key := range keys {
+		kv := otlpKeyValue{Key: key}
+		switch v := attrs[key].(type) {
+		case string:
+			kv.Value.StringValue = &v
+		case int64:
+			i := strconv.FormatInt(v, 10)
+			kv.Value.IntValue = &i
+		case float64:
+			kv.Value.DoubleValue = &v
+		case bool:
+			kv.Value.BoolValue = &v
+		default:
+			continue
+		}
+		kvs = append(kvs, kv)
+	}
+
+	return kvs
+}
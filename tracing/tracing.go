@@ -0,0 +1,131 @@
+// Package tracing exports the spans of the service, either to an OTLP
+// collector or to a local file, and provides helpers to trace calls.
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+)
+
+// Exporters of spans
+const (
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// DefaultServiceName names the service in exported spans
+const DefaultServiceName = "shoppingcart"
+
+// ErrUnknownExporter is returned by Setup for exporters which are not supported
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// Config defines how spans are exported
+type Config struct {
+	// Exporter is empty to disable tracing, ExporterOTLP or ExporterFile
+	Exporter string
+	// Endpoint is the URL spans are posted to as OTLP/HTTP JSON, e.g.
+	// http://collector:4318/v1/traces
+	Endpoint string
+	// File is the path spans are appended to, one OTLP JSON batch per line
+	File string
+	// SampleRate is the probability of tracing a request which doesn't
+	// come with the sampling decision of its caller
+	SampleRate float64
+	// ServiceName is DefaultServiceName if empty
+	ServiceName string
+}
+
+// Setup starts to export spans as configured. The returned function exports
+// the pending spans and must be called before the service exits.
+func Setup(config Config) (flush func(), err error) {
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultServiceName
+	}
+
+	var sink func(ctx context.Context, batch []byte) error
+	var closer io.Closer
+	switch config.Exporter {
+	case "":
+		return func() {}, nil
+	case ExporterOTLP:
+		if config.Endpoint == "" {
+			return nil, fmt.Errorf("no endpoint of the %s trace exporter", ExporterOTLP)
+		}
+		sink = post(config.Endpoint, &http.Client{Timeout: 10 * time.Second})
+	case ExporterFile:
+		file, err := os.OpenFile(config.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		sink, closer = write(file), file
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownExporter, config.Exporter)
+	}
+
+	exporter := newExporter(config.ServiceName, sink, defaultFlushInterval)
+	trace.RegisterExporter(exporter)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(config.SampleRate)})
+
+	return func() {
+		trace.UnregisterExporter(exporter)
+		exporter.Close()
+		if closer != nil {
+			closer.Close()
+		}
+	}, nil
+}
+
+// End ends span, with the error status if err is set
+func End(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{Code: trace.StatusCodeUnknown, Message: err.Error()})
+	}
+	span.End()
+}
+
+// post returns a sink posting batches to the OTLP/HTTP endpoint
+func post(endpoint string, client *http.Client) func(ctx context.Context, batch []byte) error {
+	return func(ctx context.Context, batch []byte) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(batch))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected response status %d of %s", resp.StatusCode, endpoint)
+		}
+
+		return nil
+	}
+}
+
+// write returns a sink appending batches to w, one per line
+func write(w io.Writer) func(ctx context.Context, batch []byte) error {
+	var mu sync.Mutex
+
+	return func(ctx context.Context, batch []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, err := w.Write(append(batch, '\n'))
+		return err
+	}
+}
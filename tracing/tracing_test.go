@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opencensus.io/trace"
+)
+
+func TestExporter(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := newExporter("shoppingcart", write(&buf), time.Hour)
+
+	start := time.Unix(1600000000, 0)
+	exporter.ExportSpan(&trace.SpanData{
+		SpanContext: trace.SpanContext{
+			TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:  trace.SpanID{0, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		},
+		ParentSpanID: trace.SpanID{1},
+		Name:         "sql.Exec",
+		SpanKind:     trace.SpanKindClient,
+		StartTime:    start,
+		EndTime:      start.Add(time.Millisecond),
+		Attributes:   map[string]interface{}{"db.statement": "DELETE shoppingcart_item", "db.rows_affected": int64(2)},
+		Status:       trace.Status{Code: trace.StatusCodeUnknown, Message: "deadlock"},
+	})
+	exporter.Close()
+
+	var req otlpRequest
+	if err := json.Unmarshal(buf.Bytes(), &req); err != nil {
+		t.Fatalf("Unable to decode exported batch %q: %s", buf.String(), err)
+	}
+
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans[0].Spans) != 1 {
+		t.Fatalf("Expected a single span, but got %s", buf.String())
+	}
+	if service := *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue; service != "shoppingcart" {
+		t.Fatalf("Expected service name shoppingcart, but got %q", service)
+	}
+
+	s := req.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "trace ID", got: s.TraceID, want: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "span ID", got: s.SpanID, want: "00f067aa0ba902b7"},
+		{name: "parent span ID", got: s.ParentSpanID, want: "0100000000000000"},
+		{name: "kind", got: s.Kind, want: otlpKindClient},
+		{name: "start", got: s.StartTimeUnixNano, want: "1600000000000000000"},
+		{name: "end", got: s.EndTimeUnixNano, want: "1600000000001000000"},
+		{name: "status", got: s.Status, want: otlpStatus{Code: otlpStatusError, Message: "deadlock"}},
+		{name: "first attribute", got: s.Attributes[0].Key, want: "db.rows_affected"},
+		{name: "rows affected", got: *s.Attributes[0].Value.IntValue, want: "2"},
+		{name: "statement", got: *s.Attributes[1].Value.StringValue, want: "DELETE shoppingcart_item"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Fatalf("Expected %s %v, but got %v", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+func TestPost(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusOK},
+		{name: "rejected", status: http.StatusBadRequest, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contentType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := post(server.URL+"/v1/traces", server.Client())(context.Background(), []byte(`{}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %t, but got %v", tt.wantErr, err)
+			}
+			if contentType != "application/json" {
+				t.Fatalf("Expected content type application/json, but got %q", contentType)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	_, err := Setup(Config{Exporter: "zipkin"})
+	if !errors.Is(err, ErrUnknownExporter) {
+		t.Fatalf("Expected error %v, but got %v", ErrUnknownExporter, err)
+	}
+
+	flush, err := Setup(Config{})
+	if err != nil {
+		t.Fatalf("Unable to set up disabled tracing: %s", err)
+	}
+	flush()
+}
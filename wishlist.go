@@ -0,0 +1,121 @@
+package shoppingcart
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// These errors can be returned by the service or storage packages when performing
+// operating on Wishlist
+var (
+	ErrWishlistNotFound     = errors.New("wishlist not found")
+	ErrWishlistNameNotSet   = errors.New("wishlist name is not specified")
+	ErrWishlistItemNotFound = errors.New("wishlist item not found")
+)
+
+// Wishlist is a named list of products a user keeps independently of shopping carts
+// swagger:response Wishlist
+type Wishlist struct {
+	ID        int64          `json:"id" gorm:"primary_key"`
+	UserID    int64          `json:"user_id"`
+	Name      string         `json:"name"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Items     []WishlistItem `json:"items,omitempty" gorm:"foreignkey:WishlistID;association_foreignkey:ID"`
+}
+
+// TableName specifies storage table name
+func (Wishlist) TableName() string {
+	return "wishlist"
+}
+
+// Validate validates Wishlist
+func (wishlist *Wishlist) Validate() error {
+	if wishlist.UserID == 0 {
+		return ErrUserNotSet
+	}
+
+	if strings.TrimSpace(wishlist.Name) == "" {
+		return ValidationErrors{{Field: "name", Err: ErrWishlistNameNotSet}}
+	}
+
+	return nil
+}
+
+// GetProduct retrieves the line of wishlistItem from the wishlist,
+// see WishlistItem.SameLine
+func (wishlist *Wishlist) GetProduct(wishlistItem WishlistItem) (WishlistItem, error) {
+	for _, item := range wishlist.Items {
+		if item.SameLine(wishlistItem) {
+			return item, nil
+		}
+	}
+	return WishlistItem{}, ErrWishlistItemNotFound
+}
+
+// GetItem retrieves wishlist item by its ID
+func (wishlist *Wishlist) GetItem(itemID int64) (WishlistItem, error) {
+	for _, item := range wishlist.Items {
+		if item.ID == itemID {
+			return item, nil
+		}
+	}
+	return WishlistItem{}, ErrWishlistItemNotFound
+}
+
+// WishlistItem is a product kept in a wishlist. Like a shopping cart line it
+// is identified by product, variant and attributes.
+// swagger:response WishlistItem
+type WishlistItem struct {
+	ID         int64      `json:"id"`
+	WishlistID int64      `json:"wishlist_id"`
+	ProductID  int64      `json:"product_id"`
+	VariantID  string     `json:"variant_id,omitempty"`
+	Attributes Attributes `json:"attributes,omitempty"`
+	Quantity   uint64     `json:"quantity"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies storage table name
+func (WishlistItem) TableName() string {
+	return "wishlist_item"
+}
+
+// SameLine reports whether both items have the same product, variant and attributes
+func (wishlistItem WishlistItem) SameLine(other WishlistItem) bool {
+	return wishlistItem.ProductID == other.ProductID &&
+		wishlistItem.VariantID == other.VariantID &&
+		wishlistItem.Attributes.Equal(other.Attributes)
+}
+
+// Validate validates WishlistItem. All failed fields are reported at once
+// as ValidationErrors.
+func (wishlistItem *WishlistItem) Validate() error {
+	var errs ValidationErrors
+
+	if wishlistItem.ProductID == 0 {
+		errs = append(errs, FieldError{Field: "product_id", Err: ErrCartItemNoProductSet})
+	}
+	if wishlistItem.Quantity == 0 {
+		errs = append(errs, FieldError{Field: "quantity", Err: ErrCartItemNoQuantitySet})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// CartItem converts the wishlist item to an item of the shopping cart
+func (wishlistItem WishlistItem) CartItem(shoppingCartID int64) ShoppingCartItem {
+	return ShoppingCartItem{
+		ShoppingCartID: shoppingCartID,
+		ProductID:      wishlistItem.ProductID,
+		VariantID:      wishlistItem.VariantID,
+		Attributes:     wishlistItem.Attributes,
+		Quantity:       wishlistItem.Quantity,
+	}
+}
@@ -0,0 +1,65 @@
+package shoppingcart
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWishlist_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		wishlist Wishlist
+		wantErr  error
+	}{
+		{
+			name:     "no user",
+			wishlist: Wishlist{Name: "Birthday"},
+			wantErr:  ErrUserNotSet,
+		},
+		{
+			name:     "blank name",
+			wishlist: Wishlist{UserID: 1, Name: "  "},
+			wantErr:  ErrWishlistNameNotSet,
+		},
+		{
+			name:     "everything set",
+			wishlist: Wishlist{UserID: 1, Name: "Birthday"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.wishlist.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWishlist_GetProduct(t *testing.T) {
+	wishlist := Wishlist{
+		Items: []WishlistItem{
+			{ID: 1, ProductID: 1, Quantity: 1},
+			{ID: 2, ProductID: 1, VariantID: "M", Attributes: Attributes{"engraving": "For Anna"}, Quantity: 2},
+		},
+	}
+
+	item, err := wishlist.GetProduct(WishlistItem{ProductID: 1, VariantID: "M", Attributes: Attributes{"engraving": "For Anna"}})
+	if err != nil || item.ID != 2 {
+		t.Fatalf("GetProduct() = %v, %v, want line %d", item, err, 2)
+	}
+
+	if _, err := wishlist.GetProduct(WishlistItem{ProductID: 1, VariantID: "M"}); err != ErrWishlistItemNotFound {
+		t.Fatalf("GetProduct() error = %v, want %v", err, ErrWishlistItemNotFound)
+	}
+}
+
+func TestWishlistItem_CartItem(t *testing.T) {
+	wishlistItem := WishlistItem{ID: 3, WishlistID: 2, ProductID: 1, VariantID: "M", Attributes: Attributes{"gift_message": "Enjoy"}, Quantity: 2}
+
+	cartItem := wishlistItem.CartItem(5)
+
+	want := ShoppingCartItem{ShoppingCartID: 5, ProductID: 1, VariantID: "M", Attributes: Attributes{"gift_message": "Enjoy"}, Quantity: 2}
+	if !cartItem.SameLine(want) || cartItem.ShoppingCartID != want.ShoppingCartID || cartItem.Quantity != want.Quantity || cartItem.ID != 0 {
+		t.Fatalf("CartItem() = %+v, want %+v", cartItem, want)
+	}
+}